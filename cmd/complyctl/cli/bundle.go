@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// bundleOptions defines options for the "bundle" subcommands
+type bundleOptions struct {
+	*option.Common
+	option.Format
+}
+
+var bundleExample = `
+# Install the content of a bundle archive after validating it.
+complyctl bundle install ./mybundle-1.0.0.tar.gz
+
+# Install a bundle from a directory with "bundles" and "controls" subdirectories.
+complyctl bundle install ./mybundle
+
+# List the installed bundles.
+complyctl bundle list
+
+# Remove an installed bundle.
+complyctl bundle remove mybundle
+`
+
+// bundleCmd creates a new cobra.Command for the "bundle" subcommand
+func bundleCmd(common *option.Common) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "bundle",
+		Short:   "Install, list and remove content bundles",
+		Example: bundleExample,
+		Args:    cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		bundleInstallCmd(common),
+		bundleListCmd(common),
+		bundleRemoveCmd(common),
+	)
+	return cmd
+}
+
+func bundleInstallCmd(common *option.Common) *cobra.Command {
+	bundleOpts := &bundleOptions{Common: common}
+	return &cobra.Command{
+		Use:          "install [flags] tar.gz|dir",
+		Short:        "Validate and install a content bundle from an archive or a directory",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runBundleInstall(bundleOpts, args[0])
+		},
+	}
+}
+
+func bundleListCmd(common *option.Common) *cobra.Command {
+	bundleOpts := &bundleOptions{Common: common}
+	cmd := &cobra.Command{
+		Use:          "list [flags]",
+		Short:        "List installed content bundles",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runBundleList(bundleOpts)
+		},
+	}
+	bundleOpts.Format.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func bundleRemoveCmd(common *option.Common) *cobra.Command {
+	bundleOpts := &bundleOptions{Common: common}
+	return &cobra.Command{
+		Use:          "remove [flags] name",
+		Short:        "Remove an installed content bundle",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runBundleRemove(bundleOpts, args[0])
+		},
+	}
+}
+
+func runBundleInstall(opts *bundleOptions, source string) error {
+	// Create the application directory if it does not exist
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, true)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+
+	bundle, err := complytime.InstallBundle(appDir, source)
+	if err != nil {
+		var validationErr *complytime.BundleValidationError
+		if errors.As(err, &validationErr) {
+			showContentReport(opts.Out, validationErr.Report)
+		}
+		return err
+	}
+	logger.Info(fmt.Sprintf("Bundle %s %s installed with %d file(s) for framework(s): %s", bundle.Name, bundle.Version, len(bundle.Files), strings.Join(bundle.Frameworks, ", ")))
+	return nil
+}
+
+func runBundleList(opts *bundleOptions) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, false)
+	if err != nil {
+		return err
+	}
+	bundles, err := complytime.ListBundles(appDir)
+	if err != nil {
+		return err
+	}
+
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, bundles)
+	}
+	if len(bundles) == 0 {
+		_, _ = fmt.Fprintf(opts.Out, "No bundles installed in %s.\n", appDir.AppDir())
+		return nil
+	}
+	showBundleTable(opts.Out, bundles)
+	return nil
+}
+
+func runBundleRemove(opts *bundleOptions, name string) error {
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, false)
+	if err != nil {
+		return err
+	}
+	bundle, err := complytime.RemoveBundle(appDir, name)
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("Bundle %s %s removed with %d file(s)", bundle.Name, bundle.Version, len(bundle.Files)))
+	return nil
+}
+
+// showBundleTable prints a plain table of the installed bundles.
+func showBundleTable(writer io.Writer, bundles []complytime.InstalledBundle) {
+	var rows []table.Row
+	for _, bundle := range bundles {
+		rows = append(rows, table.Row{
+			bundle.Name,
+			bundle.Version,
+			strings.Join(bundle.Frameworks, ", "),
+			fmt.Sprint(len(bundle.Files)),
+			bundle.InstalledAt.Format(time.RFC3339),
+		})
+	}
+	columns := []table.Column{
+		{Title: "Name", Width: 15},
+		{Title: "Version", Width: 10},
+		{Title: "Frameworks", Width: 20},
+		{Title: "Files", Width: 6},
+		{Title: "Installed", Width: 21},
+	}
+	fitColumnWidths(columns, rows)
+	terminal.ShowPlainTable(writer, columns, rows)
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestShowBundleTable(t *testing.T) {
+	bundles := []complytime.InstalledBundle{
+		{
+			Name:        "example",
+			Version:     "1.0.0",
+			Frameworks:  []string{"example", "example-high"},
+			Files:       []string{"bundles/example-component-definition.json", "controls/sample-profile.json"},
+			InstalledAt: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	out := bytes.NewBuffer(nil)
+	showBundleTable(out, bundles)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, []string{"Name", "Version", "Frameworks", "Files", "Installed"}, strings.Fields(lines[0]))
+	require.Equal(t, []string{"example", "1.0.0", "example,", "example-high", "2", "2025-05-01T12:00:00Z"}, strings.Fields(lines[1]))
+}
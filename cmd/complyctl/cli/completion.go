@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// Shell completion functions suggest the IDs found in the installed component definitions,
+// or in the workspace history for scan runs.
+// Content is not validated against the OSCAL schema so completion stays responsive, and
+// logging is disabled because the completions are written to stdout.
+// Completion requests do not run the persistent hooks of the root command, so each function
+// loads the configuration files before resolving the application directory or the workspace.
+
+// prepareCompletion applies the configuration files to the flags of cmd and disables logging.
+// The loaded configuration is returned.
+func prepareCompletion(cmd *cobra.Command) (option.Config, error) {
+	config, err := applyConfig(cmd)
+	if err != nil {
+		return option.Config{}, err
+	}
+	logger.SetLevel(hclog.Off)
+	return config, nil
+}
+
+// completeFrameworkIDs suggests the framework IDs for the first positional argument.
+func completeFrameworkIDs(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return frameworkCompletions(cmd, nil)
+}
+
+// completeFrameworkIDsList suggests the framework IDs not given yet for every positional argument.
+func completeFrameworkIDsList(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return frameworkCompletions(cmd, args)
+}
+
+// frameworkCompletions returns the IDs of the installed frameworks, except the given ones.
+func frameworkCompletions(cmd *cobra.Command, exclude []string) ([]string, cobra.ShellCompDirective) {
+	config, err := prepareCompletion(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	appDir, err := complytime.NewApplicationDirectory(config.ApplicationDirectory, false)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	frameworks, err := complytime.LoadFrameworks(appDir, validation.NoopValidator{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var completions []string
+	for _, framework := range frameworks {
+		if slices.Contains(exclude, framework.ID) {
+			continue
+		}
+		completions = append(completions, cobra.CompletionWithDesc(framework.ID, framework.Title))
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRunIDs suggests the IDs of the scan runs recorded in the workspace, most recent first.
+func completeRunIDs(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if _, err := prepareCompletion(cmd); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	workspace := option.DefaultWorkspace
+	if flag := cmd.Flags().Lookup("workspace"); flag != nil {
+		workspace = flag.Value.String()
+	}
+	runs, err := complytime.ListRuns(workspace)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	completions := []string{complytime.LatestRun}
+	for _, run := range runs {
+		completions = append(completions, cobra.CompletionWithDesc(run.ID, run.FrameworkID))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeControlIDs suggests the IDs of the controls implemented for the framework given as argument.
+func completeControlIDs(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	index, err := loadCompletionIndex(cmd, args)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var completions []string
+	for id, control := range index.controls {
+		completions = append(completions, cobra.CompletionWithDesc(id, control.Title))
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRuleIDs suggests the IDs of the rules defined for the framework given as argument.
+func completeRuleIDs(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	index, err := loadCompletionIndex(cmd, args)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var completions []string
+	for id, remarks := range index.ruleRemarks {
+		ruleDetails := extractRuleDetails(index.remarksProps[remarks])
+		completions = append(completions, cobra.CompletionWithDesc(id, ruleDetails.Description))
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionIndex holds the controls and rules of a framework, indexed as for the info command.
+type completionIndex struct {
+	controls     indexedControls
+	ruleRemarks  ruleRemarksMap
+	remarksProps remarksPropertiesMap
+}
+
+// loadCompletionIndex indexes the controls and rules of the framework given as first argument.
+func loadCompletionIndex(cmd *cobra.Command, args []string) (completionIndex, error) {
+	if len(args) == 0 {
+		return completionIndex{}, fmt.Errorf("a framework id is required")
+	}
+	config, err := prepareCompletion(cmd)
+	if err != nil {
+		return completionIndex{}, err
+	}
+	appDir, err := complytime.NewApplicationDirectory(config.ApplicationDirectory, false)
+	if err != nil {
+		return completionIndex{}, err
+	}
+	validator := validation.NoopValidator{}
+	compDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validator)
+	if err != nil {
+		return completionIndex{}, err
+	}
+	frameworkComponents, validationComponents := loadComponents(compDefs, args[0])
+	rulePlugins := extractRulePluginMapping(validationComponents)
+	index := completionIndex{}
+	index.ruleRemarks, index.remarksProps = processComponentProperties(frameworkComponents)
+	index.controls, _ = processControlImplementations(frameworkComponents, rulePlugins, appDir, validator)
+	return index, nil
+}
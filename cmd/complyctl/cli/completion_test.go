@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestCompletions(t *testing.T) {
+	// Completion requests load the configuration files, here the workspace configuration file.
+	appDir := t.TempDir()
+	require.NoError(t, os.CopyFS(appDir, os.DirFS(filepath.Join("..", "..", "..", "internal", "complytime", "testdata", "bundle"))))
+	workspace := t.TempDir()
+	config := "applicationDirectory: " + appDir + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, option.WorkspaceConfigFile), []byte(config), 0600))
+	cmd := infoCmd(&option.Common{})
+	require.NoError(t, cmd.Flags().Set("workspace", workspace))
+
+	tests := []struct {
+		name     string
+		complete cobra.CompletionFunc
+		args     []string
+		want     []string
+	}{
+		{
+			name:     "Frameworks",
+			complete: completeFrameworkIDs,
+			want:     []string{"example\tExample Profile (low)"},
+		},
+		{
+			name:     "FrameworksAfterFirstArgument",
+			complete: completeFrameworkIDs,
+			args:     []string{"example"},
+		},
+		{
+			name:     "FrameworksList",
+			complete: completeFrameworkIDsList,
+			want:     []string{"example\tExample Profile (low)"},
+		},
+		{
+			name:     "FrameworksListWithoutGivenFrameworks",
+			complete: completeFrameworkIDsList,
+			args:     []string{"example"},
+		},
+		{
+			name:     "Controls",
+			complete: completeControlIDs,
+			args:     []string{"example"},
+			want:     []string{"example-1\tHardware Support"},
+		},
+		{
+			name:     "Rules",
+			complete: completeRuleIDs,
+			args:     []string{"example"},
+			want:     []string{"rule-1\tMy first rule"},
+		},
+		{
+			name:     "RulesOfUnknownFramework",
+			complete: completeRuleIDs,
+			args:     []string{"unknown"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completions, directive := tt.complete(cmd, tt.args, "")
+			require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+			require.Equal(t, tt.want, completions)
+		})
+	}
+
+	_, directive := completeControlIDs(cmd, nil, "")
+	require.Equal(t, cobra.ShellCompDirectiveError, directive)
+
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, option.WorkspaceConfigFile), []byte("logFormat: xml\n"), 0600))
+	_, directive = completeFrameworkIDs(cmd, nil, "")
+	require.Equal(t, cobra.ShellCompDirectiveError, directive)
+}
+
+func TestCompleteRunIDs(t *testing.T) {
+	workspace := t.TempDir()
+	_, err := complytime.RecordRun(workspace, complytime.Run{FrameworkID: "example"}, nil, time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	cmd := historyShowCmd(&option.Common{})
+	require.NoError(t, cmd.Flags().Set("workspace", workspace))
+	completions, directive := completeRunIDs(cmd, nil, "")
+	require.Equal(t, cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveKeepOrder, directive)
+	require.Equal(t, []string{"latest", "20250501T120000Z\texample"}, completions)
+}
+
+func TestCompleteRunIDsConfig(t *testing.T) {
+	// The workspace is only set by the user configuration file.
+	t.Cleanup(xdg.Reload)
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	xdg.Reload()
+	workspace := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Dir(option.UserConfigFile()), 0700))
+	require.NoError(t, os.WriteFile(option.UserConfigFile(), []byte("workspace: "+workspace+"\n"), 0600))
+	_, err := complytime.RecordRun(workspace, complytime.Run{FrameworkID: "example"}, nil, time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	completions, directive := completeRunIDs(historyShowCmd(&option.Common{}), nil, "")
+	require.Equal(t, cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveKeepOrder, directive)
+	require.Equal(t, []string{"latest", "20250501T120000Z\texample"}, completions)
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/table"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// diffOptions defines options for the "diff" subcommand
+type diffOptions struct {
+	*option.Common
+	option.Format
+	complyTimeOpts *option.ComplyTime
+	beforePath     string
+	afterPath      string
+}
+
+var diffExample = `
+# Compare the results of a previous scan with the latest results in the workspace.
+complyctl diff previous-assessment-results.json complytime/assessment-results.json
+
+# Print the changes as JSON.
+complyctl diff previous-assessment-results.json complytime/assessment-results.json --output json
+`
+
+// diffCmd creates a new cobra.Command for the "diff" subcommand
+func diffCmd(common *option.Common) *cobra.Command {
+	diffOpts := &diffOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "diff [flags] before after",
+		Short:        "Compare the status of rules and controls in two assessment results",
+		Example:      diffExample,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		PreRun: func(_ *cobra.Command, args []string) {
+			diffOpts.beforePath = filepath.Clean(args[0])
+			diffOpts.afterPath = filepath.Clean(args[1])
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runDiff(diffOpts)
+		},
+	}
+	diffOpts.Format.BindFlags(cmd.Flags())
+	diffOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runDiff(opts *diffOptions) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	validator := validation.NewSchemaValidator()
+
+	plan, err := loadOptionalPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+
+	before, err := complytime.ReadAssessmentResults(opts.beforePath, validator)
+	if err != nil {
+		return err
+	}
+	after, err := complytime.ReadAssessmentResults(opts.afterPath, validator)
+	if err != nil {
+		return err
+	}
+
+	diff := complytime.DiffResults(
+		complytime.SummarizeResults(before, plan),
+		complytime.SummarizeResults(after, plan),
+	)
+
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, diff)
+	}
+	showDiffTables(opts.Out, diff)
+	return nil
+}
+
+// loadOptionalPlan returns the assessment plan from the workspace or nil
+// if the workspace has no plan. Assessment results can be summarized without
+// a plan, but only the plan maps passing rules to controls.
+func loadOptionalPlan(opts *option.ComplyTime, validator validation.Validator) (*oscalTypes.AssessmentPlan, error) {
+	plan, _, err := loadPlan(opts, validator)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Debug(fmt.Sprintf("No assessment plan found in workspace %s, controls are mapped from findings only.", opts.UserWorkspace))
+			return nil, nil
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+// showDiffTables prints plain tables with the control and rule status changes.
+func showDiffTables(writer io.Writer, diff complytime.ResultsDiff) {
+	if diff.Empty() {
+		_, _ = fmt.Fprintln(writer, "No status changes found.")
+		return
+	}
+	_, _ = fmt.Fprintf(writer, "Controls (%d changed)\n", len(diff.Controls))
+	columns, rows := getDiffColumnsAndRows("Control ID", diff.Controls)
+	terminal.ShowPlainTable(writer, columns, rows)
+	_, _ = fmt.Fprintln(writer)
+	_, _ = fmt.Fprintf(writer, "Rules (%d changed)\n", len(diff.Rules))
+	columns, rows = getDiffColumnsAndRows("Rule ID", diff.Rules)
+	terminal.ShowPlainTable(writer, columns, rows)
+}
+
+// getDiffColumnsAndRows prepares columns and rows for a table of status changes.
+func getDiffColumnsAndRows(idTitle string, changes []complytime.StatusChange) ([]table.Column, []table.Row) {
+	var rows []table.Row
+	for _, change := range changes {
+		rows = append(rows, table.Row{change.ID, displayStatus(change.Before), displayStatus(change.After), change.Change})
+	}
+
+	columns := []table.Column{
+		{Title: idTitle, Width: 20},
+		{Title: "Before", Width: 14},
+		{Title: "After", Width: 14},
+		{Title: "Change", Width: 14},
+	}
+	fitColumnWidths(columns, rows)
+	return columns, rows
+}
+
+// displayStatus returns a printable status, using "-" for absent items.
+func displayStatus(status string) string {
+	if status == "" {
+		return "-"
+	}
+	return status
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestShowDiffTables(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	showDiffTables(out, complytime.ResultsDiff{})
+	require.Equal(t, "No status changes found.\n", out.String())
+
+	out.Reset()
+	diff := complytime.ResultsDiff{
+		Controls: []complytime.StatusChange{
+			{ID: "r31", Before: "pass", After: "fail", Change: complytime.ChangeNewFailure},
+		},
+		Rules: []complytime.StatusChange{
+			{ID: "enable_authselect", Before: "", After: "fail", Change: complytime.ChangeNewFailure},
+		},
+	}
+	showDiffTables(out, diff)
+	want := "Controls (1 changed)\n" +
+		"Control ID          Before        After         Change        \n" +
+		"r31                 pass          fail          new-failure   \n" +
+		"\n" +
+		"Rules (1 changed)\n" +
+		"Rule ID             Before        After         Change        \n" +
+		"enable_authselect   -             fail          new-failure   \n"
+	require.Equal(t, want, out.String())
+}
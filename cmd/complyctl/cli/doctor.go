@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// doctorOptions defines options for the "doctor" subcommand
+type doctorOptions struct {
+	*option.Common
+	option.Format
+	complyTimeOpts   *option.ComplyTime
+	withPluginConfig string
+}
+
+var doctorExample = `
+# Check the complyctl installation and content.
+complyctl doctor
+
+# Also run the plugin configuration checks with the assessment plan in a workspace.
+complyctl doctor --workspace ./complytime
+
+# Print the checks as JSON.
+complyctl doctor --output json
+`
+
+// doctorCmd creates a new cobra.Command for the "doctor" subcommand
+func doctorCmd(common *option.Common) *cobra.Command {
+	doctorOpts := &doctorOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "doctor [flags]",
+		Short:        "Check the environment for common setup problems",
+		Example:      doctorExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runDoctor(doctorOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&doctorOpts.withPluginConfig, "plugin-config", "c", "", "Directory where user customized plugin manifests located.")
+	doctorOpts.Format.BindFlags(cmd.Flags())
+	doctorOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runDoctor(opts *doctorOptions) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	// Do not create the application directory so missing directories are reported.
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, false)
+	if err != nil {
+		return err
+	}
+	validator := validation.NewSchemaValidator()
+
+	diagnostics := []complytime.Diagnostic{complytime.DiagnoseDevMode(os.Getenv("COMPLYTIME_DEV_MODE"), appDir)}
+	diagnostics = append(diagnostics, complytime.DiagnoseDirectories(appDir)...)
+	contentDiagnostics, compDefs := complytime.DiagnoseContent(appDir, validator)
+	diagnostics = append(diagnostics, contentDiagnostics...)
+	pluginDiagnostics := complytime.DiagnosePlugins(appDir, compDefs)
+	diagnostics = append(diagnostics, pluginDiagnostics...)
+	if countFailed(pluginDiagnostics) == 0 {
+		diagnostics = append(diagnostics, diagnosePluginConfiguration(opts, appDir, validator))
+	}
+
+	if opts.Structured() {
+		if err := writeStructured(opts.Out, opts.OutputFormat, diagnostics); err != nil {
+			return err
+		}
+	} else {
+		showDiagnostics(opts.Out, diagnostics)
+	}
+
+	if failed := countFailed(diagnostics); failed > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", failed)
+	}
+	return nil
+}
+
+// diagnosePluginConfiguration launches the plugins requested by the workspace assessment plan
+// so each plugin can validate its configuration, such as locating a matching datastream.
+func diagnosePluginConfiguration(opts *doctorOptions, appDir complytime.ApplicationDirectory, validator validation.Validator) complytime.Diagnostic {
+	diagnostic := complytime.Diagnostic{Name: "plugin configuration"}
+	ap, err := loadOptionalPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		diagnostic.Status = complytime.DiagnosticFailed
+		diagnostic.Message = err.Error()
+		diagnostic.Fix = "Regenerate the assessment plan with \"complyctl plan <framework-id>\"."
+		return diagnostic
+	}
+	if ap == nil {
+		diagnostic.Status = complytime.DiagnosticWarning
+		diagnostic.Message = fmt.Sprintf("no assessment plan in workspace %s, plugin configuration was not checked", opts.complyTimeOpts.UserWorkspace)
+		diagnostic.Fix = "Run \"complyctl plan <framework-id>\" or pass --workspace to check the plugin configuration."
+		return diagnostic
+	}
+
+	err = launchPlugins(opts, appDir, ap)
+	if err != nil {
+		diagnostic.Status = complytime.DiagnosticFailed
+		diagnostic.Message = err.Error()
+		configDir := opts.withPluginConfig
+		if configDir == "" {
+			configDir = complytime.DefaultPluginConfigDir
+		}
+		diagnostic.Fix = fmt.Sprintf("Review the plugin configuration in %s and the plugin requirements for this system.", configDir)
+		return diagnostic
+	}
+	diagnostic.Status = complytime.DiagnosticOK
+	diagnostic.Message = fmt.Sprintf("plugins accepted the configuration for workspace %s", opts.complyTimeOpts.UserWorkspace)
+	return diagnostic
+}
+
+// launchPlugins launches and configures the plugins requested by the assessment plan.
+func launchPlugins(opts *doctorOptions, appDir complytime.ApplicationDirectory, ap *oscalTypes.AssessmentPlan) error {
+	inputContext, err := complytime.ActionsContextFromPlan(ap)
+	if err != nil {
+		return err
+	}
+	frameworkID, err := complytime.PlanFrameworkID(ap)
+	if err != nil {
+		return err
+	}
+	cfg, err := complytime.Config(appDir)
+	if err != nil {
+		return err
+	}
+	pluginLogger := logger
+	if opts.Structured() {
+		// Keep machine-readable output free of plugin logs.
+		pluginLogger = hclog.NewNullLogger()
+	}
+	cfg.Logger = pluginLogger
+	manager, err := framework.NewPluginManager(cfg)
+	if err != nil {
+		return fmt.Errorf("error initializing plugin manager: %w", err)
+	}
+
+	opts.complyTimeOpts.FrameworkID = frameworkID
+	pluginOptions := opts.complyTimeOpts.ToPluginOptions()
+	pluginOptions.UserConfigRoot = opts.withPluginConfig
+	_, cleanup, err := complytime.Plugins(manager, inputContext, pluginOptions, pluginLogger)
+	if cleanup != nil {
+		defer cleanup()
+	}
+	return err
+}
+
+// showDiagnostics prints the diagnostics as a checklist with the fix for each problem.
+func showDiagnostics(writer io.Writer, diagnostics []complytime.Diagnostic) {
+	for _, diagnostic := range diagnostics {
+		_, _ = fmt.Fprintf(writer, "%-10s %s: %s\n", "["+diagnostic.Status+"]", diagnostic.Name, diagnostic.Message)
+		if diagnostic.Fix != "" {
+			_, _ = fmt.Fprintf(writer, "%s Fix: %s\n", strings.Repeat(" ", 10), diagnostic.Fix)
+		}
+	}
+}
+
+// countFailed returns the number of failed diagnostics.
+func countFailed(diagnostics []complytime.Diagnostic) int {
+	var failed int
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Status == complytime.DiagnosticFailed {
+			failed++
+		}
+	}
+	return failed
+}
@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestShowDiagnostics(t *testing.T) {
+	diagnostics := []complytime.Diagnostic{
+		{Name: "directory /usr/share/complytime", Status: complytime.DiagnosticOK, Message: "directory exists"},
+		{Name: "plugin openscap", Status: complytime.DiagnosticFailed, Message: "plugin manifest not found", Fix: "Install the openscap plugin."},
+	}
+	out := bytes.NewBuffer(nil)
+	showDiagnostics(out, diagnostics)
+	want := "[ok]       directory /usr/share/complytime: directory exists\n" +
+		"[fail]     plugin openscap: plugin manifest not found\n" +
+		"           Fix: Install the openscap plugin.\n"
+	require.Equal(t, want, out.String())
+	require.Equal(t, 1, countFailed(diagnostics))
+}
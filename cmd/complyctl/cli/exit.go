@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// Exit codes returned by complyctl. Threshold breaches detected by
+// "scan" each have their own exit code so CI pipelines can gate on them.
+const (
+	// ExitCodeError is returned for any error other than a threshold breach.
+	ExitCodeError = 1
+	// ExitCodeFailOn is returned when a rule matches the --fail-on selection.
+	ExitCodeFailOn = 2
+	// ExitCodeMaxFailures is returned when more rules fail than --max-failures.
+	ExitCodeMaxFailures = 3
+	// ExitCodeMinPassRate is returned when the framework pass rate is below --min-pass-rate.
+	ExitCodeMinPassRate = 4
+	// ExitCodeMinControlPassRate is returned when a control pass rate is below --min-control-pass-rate.
+	ExitCodeMinControlPassRate = 5
+)
+
+// breachExitCodes maps threshold breach kinds to exit codes.
+var breachExitCodes = map[string]int{
+	complytime.BreachFailOn:             ExitCodeFailOn,
+	complytime.BreachMaxFailures:        ExitCodeMaxFailures,
+	complytime.BreachMinPassRate:        ExitCodeMinPassRate,
+	complytime.BreachMinControlPassRate: ExitCodeMinControlPassRate,
+}
+
+// ExitError is an error with a specific process exit code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for an error returned by a command.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitCodeError
+}
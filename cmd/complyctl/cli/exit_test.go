@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestExitCode(t *testing.T) {
+	require.Equal(t, 0, ExitCode(nil))
+	require.Equal(t, ExitCodeError, ExitCode(errors.New("error")))
+
+	exitErr := &ExitError{Code: ExitCodeMaxFailures, Err: errors.New("too many failures")}
+	require.Equal(t, ExitCodeMaxFailures, ExitCode(exitErr))
+	require.Equal(t, ExitCodeMaxFailures, ExitCode(fmt.Errorf("wrapped: %w", exitErr)))
+	require.EqualError(t, exitErr, "too many failures")
+}
+
+func TestCheckThresholds(t *testing.T) {
+	summary := complytime.ResultsSummary{
+		Rules: []complytime.RuleResult{
+			{RuleID: "rule-1", Status: complytime.StatusPass},
+			{RuleID: "rule-2", Status: complytime.StatusError},
+		},
+		RuleCounts: complytime.StatusCounts{Pass: 1, Error: 1},
+	}
+
+	require.NoError(t, checkThresholds(complytime.Thresholds{MaxFailures: -1}, summary))
+
+	err := checkThresholds(complytime.Thresholds{FailOn: complytime.FailOnAny, MaxFailures: 0}, summary)
+	require.EqualError(t, err, "threshold fail-on breached: 1 rule(s) matched --fail-on any: rule-2")
+	require.Equal(t, ExitCodeFailOn, ExitCode(err))
+
+	err = checkThresholds(complytime.Thresholds{MaxFailures: -1, MinPassRate: 75}, summary)
+	require.Equal(t, ExitCodeMinPassRate, ExitCode(err))
+}
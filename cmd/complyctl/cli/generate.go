@@ -4,10 +4,10 @@ package cli
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework/actions"
-	"github.com/oscal-compass/oscal-sdk-go/extensions"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 	"github.com/spf13/cobra"
 
@@ -44,7 +44,7 @@ func generateCmd(common *option.Common) *cobra.Command {
 
 func runGenerate(cmd *cobra.Command, opts *generateOptions) error {
 	validator := validation.NewSchemaValidator()
-	ap, _, err := loadPlan(opts.complyTimeOpts, validator)
+	ap, apCleanedPath, err := loadPlan(opts.complyTimeOpts, validator)
 	if err != nil {
 		return err
 	}
@@ -55,7 +55,7 @@ func runGenerate(cmd *cobra.Command, opts *generateOptions) error {
 	}
 
 	// Create the application directory if it does not exist
-	appDir, err := complytime.NewApplicationDirectory(true)
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, true)
 	if err != nil {
 		return err
 	}
@@ -74,13 +74,11 @@ func runGenerate(cmd *cobra.Command, opts *generateOptions) error {
 		return fmt.Errorf("error initializing plugin manager: %w", err)
 	}
 
-	// Set the framework ID from state (assessment plan). This is required to populate complyTime required plugin options.
-	frameworkProp, valid := extensions.GetTrestleProp(extensions.FrameworkProp, *ap.Metadata.Props)
-	if !valid {
-		return fmt.Errorf("error reading framework property from assessment plan")
+	// Set the framework ID from the workspace state. This is required to populate complyTime required plugin options.
+	state, err := loadWorkspaceState(opts.complyTimeOpts, ap, apCleanedPath)
+	if err != nil {
+		return err
 	}
-	opts.complyTimeOpts.FrameworkID = frameworkProp.Value
-	logger.Debug(fmt.Sprintf("Framework property was successfully read from the assessment plan: %v.", frameworkProp))
 
 	pluginOptions := opts.complyTimeOpts.ToPluginOptions()
 	pluginOptions.UserConfigRoot = opts.withPluginConfig
@@ -98,5 +96,8 @@ func runGenerate(cmd *cobra.Command, opts *generateOptions) error {
 	}
 
 	logger.Info("Policy generation process completed for available plugins.")
-	return nil
+
+	generated := time.Now().UTC()
+	state.LastGenerate = &generated
+	return writeWorkspaceState(opts.complyTimeOpts.UserWorkspace, state)
 }
@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// historyOptions defines options for the "history" subcommands
+type historyOptions struct {
+	*option.Common
+	option.Format
+	complyTimeOpts *option.ComplyTime
+
+	// keep and maxAge override the configured retention when pruning
+	keep   int
+	maxAge string
+	dryRun bool
+}
+
+var historyExample = `
+# List the scan runs recorded in the workspace.
+complyctl history list
+
+# Show the files, counts and evidence of the latest scan run.
+complyctl history show latest
+
+# Remove all but the 10 most recent scan runs.
+complyctl history prune --keep 10
+
+# List the scan runs older than 30 days without removing them.
+complyctl history prune --max-age 30d --dry-run
+`
+
+// historyCmd creates a new cobra.Command for the "history" subcommand
+func historyCmd(common *option.Common) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "history",
+		Short:   "List, show and prune the scan runs recorded in the workspace",
+		Example: historyExample,
+		Args:    cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		historyListCmd(common),
+		historyShowCmd(common),
+		historyPruneCmd(common),
+	)
+	return cmd
+}
+
+func newHistoryOptions(common *option.Common) *historyOptions {
+	return &historyOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+}
+
+func historyListCmd(common *option.Common) *cobra.Command {
+	historyOpts := newHistoryOptions(common)
+	cmd := &cobra.Command{
+		Use:          "list [flags]",
+		Short:        "List the scan runs, most recent first",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runHistoryList(historyOpts)
+		},
+	}
+	historyOpts.Format.BindFlags(cmd.Flags())
+	historyOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func historyShowCmd(common *option.Common) *cobra.Command {
+	historyOpts := newHistoryOptions(common)
+	cmd := &cobra.Command{
+		Use:               "show [flags] id|latest",
+		Short:             "Show a scan run",
+		SilenceUsage:      true,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRunIDs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runHistoryShow(historyOpts, args[0])
+		},
+	}
+	historyOpts.Format.BindFlags(cmd.Flags())
+	historyOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func historyPruneCmd(common *option.Common) *cobra.Command {
+	historyOpts := newHistoryOptions(common)
+	cmd := &cobra.Command{
+		Use:          "prune [flags]",
+		Short:        "Remove the scan runs exceeding the retention, always keeping the latest run",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runHistoryPrune(cmd, historyOpts)
+		},
+	}
+	cmd.Flags().IntVar(&historyOpts.keep, "keep", 0, "number of most recent runs to keep. Defaults to history.keep in the configuration.")
+	cmd.Flags().StringVar(&historyOpts.maxAge, "max-age", "", "remove the runs older than the given age, such as 30d or 12h. Defaults to history.maxAge in the configuration.")
+	cmd.Flags().BoolVar(&historyOpts.dryRun, "dry-run", false, "list the runs to remove without removing them")
+	historyOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runHistoryList(opts *historyOptions) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	workspace := opts.complyTimeOpts.UserWorkspace
+	runs, err := complytime.ListRuns(workspace)
+	if err != nil {
+		return err
+	}
+
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, runs)
+	}
+	if len(runs) == 0 {
+		_, _ = fmt.Fprintf(opts.Out, "No scan runs recorded in %s.\n", complytime.HistoryPath(workspace))
+		return nil
+	}
+	latest, err := complytime.ReadRun(workspace, complytime.LatestRun)
+	if err != nil {
+		return err
+	}
+	showHistoryTable(opts.Out, runs, latest.ID)
+	return nil
+}
+
+func runHistoryShow(opts *historyOptions, id string) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	run, err := complytime.ReadRun(opts.complyTimeOpts.UserWorkspace, id)
+	if err != nil {
+		return err
+	}
+
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, run)
+	}
+	showRun(opts.Out, opts.complyTimeOpts.UserWorkspace, run)
+	return nil
+}
+
+func runHistoryPrune(cmd *cobra.Command, opts *historyOptions) error {
+	retention, err := loadedConfig.History.Retention()
+	if err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("keep") {
+		if opts.keep < 0 {
+			return fmt.Errorf("invalid keep %d: must not be negative", opts.keep)
+		}
+		retention.Keep = opts.keep
+	}
+	if cmd.Flags().Changed("max-age") {
+		retention.MaxAge, err = complytime.ParseAge(opts.maxAge)
+		if err != nil {
+			return err
+		}
+	}
+	if retention.Keep == 0 && retention.MaxAge == 0 {
+		return fmt.Errorf("no retention given: set --keep or --max-age, or history.keep or history.maxAge in the configuration")
+	}
+
+	pruned, err := complytime.PruneRuns(opts.complyTimeOpts.UserWorkspace, retention, time.Now(), opts.dryRun)
+	if err != nil {
+		return err
+	}
+	for _, run := range pruned {
+		if opts.dryRun {
+			_, _ = fmt.Fprintf(opts.Out, "Would remove scan run %s\n", run.ID)
+		} else {
+			logger.Info(fmt.Sprintf("Removed scan run %s", run.ID))
+		}
+	}
+	if len(pruned) == 0 {
+		logger.Info("No scan runs to remove.")
+	}
+	return nil
+}
+
+// showHistoryTable prints a plain table of the scan runs, marking the latest run.
+func showHistoryTable(writer io.Writer, runs []complytime.Run, latestID string) {
+	var rows []table.Row
+	for _, run := range runs {
+		id := run.ID
+		if run.ID == latestID {
+			id += " *"
+		}
+		rows = append(rows, table.Row{
+			id,
+			run.Time.Format(time.RFC3339),
+			strings.Join(runFrameworkIDs(run), ", "),
+			fmt.Sprint(run.RuleCounts.Pass),
+			fmt.Sprint(run.RuleCounts.Fail),
+			fmt.Sprint(run.RuleCounts.Error),
+			fmt.Sprint(run.RuleCounts.NotAssessed),
+		})
+	}
+	columns := []table.Column{
+		{Title: "Run ID", Width: 20},
+		{Title: "Time", Width: 21},
+		{Title: "Framework", Width: 15},
+		{Title: "Pass", Width: 6},
+		{Title: "Fail", Width: 6},
+		{Title: "Error", Width: 6},
+		{Title: "Not Assessed", Width: 13},
+	}
+	fitColumnWidths(columns, rows)
+	terminal.ShowPlainTable(writer, columns, rows)
+	_, _ = fmt.Fprintln(writer, "* latest run")
+}
+
+// runFrameworkIDs returns the frameworks of a scan run, starting with the primary framework.
+func runFrameworkIDs(run complytime.Run) []string {
+	if len(run.Frameworks) == 0 {
+		return []string{run.FrameworkID}
+	}
+	frameworkIDs := make([]string, 0, len(run.Frameworks))
+	for _, framework := range run.Frameworks {
+		frameworkIDs = append(frameworkIDs, framework.FrameworkID)
+	}
+	return frameworkIDs
+}
+
+// showRun prints the details of a scan run.
+func showRun(writer io.Writer, workspace string, run complytime.Run) {
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Run ID", run.ID))
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Time", run.Time.Format(time.RFC3339)))
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Framework", run.FrameworkID))
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Plan Digest", run.PlanDigest))
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Rules", formatStatusCounts(run.RuleCounts)))
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Controls", formatStatusCounts(run.ControlCounts)))
+	for _, framework := range run.Frameworks {
+		_, _ = fmt.Fprintln(writer, renderKeyValuePair(framework.FrameworkID, formatFrameworkCounts(framework)))
+	}
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Directory", complytime.RunPath(workspace, run.ID)))
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Files", strings.Join(run.Files, ", ")))
+	if len(run.Artifacts) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Artifacts", ""))
+	for _, artifact := range run.Artifacts {
+		line := "  " + artifact.Href
+		if artifact.Path != "" {
+			line += " copied to " + artifact.Path
+		}
+		if artifact.Digest != "" {
+			line += " " + artifact.Digest
+		}
+		_, _ = fmt.Fprintln(writer, line)
+	}
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestShowHistoryTable(t *testing.T) {
+	runs := []complytime.Run{
+		{ID: "20250502T120000Z", Time: time.Date(2025, 5, 2, 12, 0, 0, 0, time.UTC), FrameworkID: "example", RuleCounts: complytime.StatusCounts{Pass: 3, Fail: 1}},
+		{ID: "20250501T120000Z", Time: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC), FrameworkID: "example", RuleCounts: complytime.StatusCounts{Pass: 2, Fail: 2}},
+	}
+	out := bytes.NewBuffer(nil)
+	showHistoryTable(out, runs, "20250502T120000Z")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, []string{"20250502T120000Z", "*", "2025-05-02T12:00:00Z", "example", "3", "1", "0", "0"}, strings.Fields(lines[1]))
+	require.Equal(t, []string{"20250501T120000Z", "2025-05-01T12:00:00Z", "example", "2", "2", "0", "0"}, strings.Fields(lines[2]))
+}
+
+func TestRunHistoryPrune(t *testing.T) {
+	workspace := t.TempDir()
+	for day := 1; day <= 3; day++ {
+		_, err := complytime.RecordRun(workspace, complytime.Run{FrameworkID: "example"}, nil, time.Date(2025, 5, day, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+	}
+
+	out := bytes.NewBuffer(nil)
+	cmd := historyPruneCmd(&option.Common{Output: option.Output{Out: out}})
+	cmd.SetArgs([]string{"--workspace", workspace})
+	require.EqualError(t, cmd.Execute(), "no retention given: set --keep or --max-age, or history.keep or history.maxAge in the configuration")
+
+	cmd = historyPruneCmd(&option.Common{Output: option.Output{Out: out}})
+	cmd.SetArgs([]string{"--workspace", workspace, "--keep", "1", "--dry-run"})
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "Would remove scan run 20250502T000000Z\nWould remove scan run 20250501T000000Z\n", out.String())
+
+	cmd = historyPruneCmd(&option.Common{Output: option.Output{Out: out}})
+	cmd.SetArgs([]string{"--workspace", workspace, "--keep", "2"})
+	require.NoError(t, cmd.Execute())
+	runs, err := complytime.ListRuns(workspace)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+}
@@ -64,19 +64,38 @@ var (
 
 // rule represents details about a rule for easy mapping of rules to plugins.
 type rule struct {
-	ID          string
-	Plugin      string
-	Description string
-	Parameters  []string
+	ID          string   `json:"id" yaml:"id"`
+	Plugin      string   `json:"plugin" yaml:"plugin"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Parameters  []string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 }
 
 // control repsents details about a control across component sources.
 type control struct {
-	ID                   string
-	Title                string
-	Description          string
-	ImplementationStatus string
-	Rules                []rule
+	ID                   string `json:"id" yaml:"id"`
+	Title                string `json:"title" yaml:"title"`
+	Description          string `json:"description" yaml:"description"`
+	ImplementationStatus string `json:"implementationStatus" yaml:"implementationStatus"`
+	Rules                []rule `json:"rules" yaml:"rules"`
+}
+
+// frameworkInfo is the machine-readable representation of a framework's controls.
+type frameworkInfo struct {
+	FrameworkID string    `json:"frameworkId" yaml:"frameworkId"`
+	Controls    []control `json:"controls" yaml:"controls"`
+}
+
+// ruleParameter is a parameter used by a rule with its set values.
+type ruleParameter struct {
+	ID     string   `json:"id" yaml:"id"`
+	Values []string `json:"values" yaml:"values"`
+}
+
+// ruleInfo is the machine-readable representation of a single rule.
+type ruleInfo struct {
+	ID          string          `json:"id" yaml:"id"`
+	Description string          `json:"description" yaml:"description"`
+	Parameters  []ruleParameter `json:"parameters" yaml:"parameters"`
 }
 
 // rulePluginMap maps a Rule ID to the plugin that implements it.
@@ -96,6 +115,7 @@ type indexedSetParameters map[string][]string
 
 type infoOptions struct {
 	*option.Common
+	option.Format
 	complyTimeOpts *option.ComplyTime
 	controlID      string // show info for a specific control ID
 	ruleID         string // show info for a specific rule ID
@@ -109,10 +129,11 @@ func infoCmd(common *option.Common) *cobra.Command {
 		complyTimeOpts: &option.ComplyTime{},
 	}
 	cmd := &cobra.Command{
-		Use:     "info <framework-id> [flags]",
-		Short:   "Show information about a framework's controls and rules",
-		Example: " complyctl info anssi_bp28_minimal\n complyctl info anssi_bp28_minimal --control r31\n complyctl info anssi_bp28_minimal --rule enable_authselect",
-		Args:    cobra.ExactArgs(1),
+		Use:               "info <framework-id> [flags]",
+		Short:             "Show information about a framework's controls and rules",
+		Example:           " complyctl info anssi_bp28_minimal\n complyctl info anssi_bp28_minimal --control r31\n complyctl info anssi_bp28_minimal --rule enable_authselect\n complyctl info anssi_bp28_minimal --output json",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeFrameworkIDs,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			if len(args) == 1 {
 				infoOpts.complyTimeOpts.FrameworkID = filepath.Clean(args[0])
@@ -124,14 +145,20 @@ func infoCmd(common *option.Common) *cobra.Command {
 	cmd.Flags().StringVarP(&infoOpts.ruleID, "rule", "r", "", "show info for a specific rule ID")
 	cmd.Flags().IntVarP(&infoOpts.limit, "limit", "l", 0, "limit the number of table rows")
 	cmd.Flags().BoolVarP(&infoOpts.plain, "plain", "p", false, "print the table with minimal formatting")
+	infoOpts.Format.BindFlags(cmd.Flags())
 	infoOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	_ = cmd.RegisterFlagCompletionFunc("control", completeControlIDs)
+	_ = cmd.RegisterFlagCompletionFunc("rule", completeRuleIDs)
 	return cmd
 }
 
 // runInfo executes the info command using the provided options.
 func runInfo(opts *infoOptions) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
 
-	appDir, err := complytime.NewApplicationDirectory(true)
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, true)
 	if err != nil {
 		return fmt.Errorf("failed to initialize application directory: %w", err)
 	}
@@ -180,7 +207,7 @@ func extractRulePluginMapping(validationComponents []oscalTypes.DefinedComponent
 }
 
 // processControlImplementations extracts control details and set parameters from component definitions.
-func processControlImplementations(components []oscalTypes.DefinedComponent, rulePluginsMap rulePluginMap, appDir complytime.ApplicationDirectory, validator *validation.SchemaValidator) (indexedControls, indexedSetParameters) {
+func processControlImplementations(components []oscalTypes.DefinedComponent, rulePluginsMap rulePluginMap, appDir complytime.ApplicationDirectory, validator validation.Validator) (indexedControls, indexedSetParameters) {
 	controlMap := make(indexedControls)
 	setParameters := make(indexedSetParameters)
 
@@ -558,7 +585,10 @@ func displayControlInfo(opts *infoOptions, controlMap indexedControls) error {
 		return fmt.Errorf("control '%s' does not exist in workspace", opts.controlID)
 	}
 
-	if opts.plain {
+	if opts.Structured() {
+		control.Rules = sortedRules(control.Rules)
+		return writeStructured(opts.Out, opts.OutputFormat, control)
+	} else if opts.plain {
 		cols, rows := getControlRulesColumnsAndRows(control)
 
 		_, _ = fmt.Fprintf(opts.Out, "Control ID: %s \n", control.ID)
@@ -590,7 +620,9 @@ func displayRuleInfo(opts *infoOptions, ruleID string, ruleRemarksMap ruleRemark
 	ruleDetails := extractRuleDetails(propsForRule)
 	ruleDetails.ID = ruleID // Ensure ID is set for consistency
 
-	if opts.plain {
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, newRuleInfo(ruleDetails, setParameters))
+	} else if opts.plain {
 		_, _ = fmt.Fprintf(opts.Out, "Rule ID: %s \n", ruleDetails.ID)
 		_, _ = fmt.Fprintf(opts.Out, "Rule Description: %s \n", ruleDetails.Description)
 		_, _ = fmt.Fprintln(opts.Out)
@@ -611,7 +643,9 @@ func displayAllControls(opts *infoOptions, indexedControls indexedControls) erro
 		controls = append(controls, control)
 	}
 
-	if opts.plain {
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, newFrameworkInfo(opts.complyTimeOpts.FrameworkID, controls))
+	} else if opts.plain {
 		cols, rows := getControlListColumnsAndRows(controls)
 		terminal.ShowPlainTable(opts.Out, cols, rows)
 		return nil
@@ -621,6 +655,54 @@ func displayAllControls(opts *infoOptions, indexedControls indexedControls) erro
 	}
 }
 
+// newFrameworkInfo returns the machine-readable representation of the given
+// controls, sorted by control ID and rule ID.
+func newFrameworkInfo(frameworkID string, controls []control) frameworkInfo {
+	info := frameworkInfo{
+		FrameworkID: frameworkID,
+		Controls:    make([]control, 0, len(controls)),
+	}
+	for _, control := range controls {
+		control.Rules = sortedRules(control.Rules)
+		info.Controls = append(info.Controls, control)
+	}
+	sort.Slice(info.Controls, func(i, j int) bool {
+		return info.Controls[i].ID < info.Controls[j].ID
+	})
+	return info
+}
+
+// newRuleInfo returns the machine-readable representation of a rule
+// and the values set for its parameters.
+func newRuleInfo(ruleDetails rule, setParameters indexedSetParameters) ruleInfo {
+	info := ruleInfo{
+		ID:          ruleDetails.ID,
+		Description: ruleDetails.Description,
+		Parameters:  make([]ruleParameter, 0, len(ruleDetails.Parameters)),
+	}
+	for _, paramID := range ruleDetails.Parameters {
+		values := setParameters[paramID]
+		if values == nil {
+			values = []string{}
+		}
+		info.Parameters = append(info.Parameters, ruleParameter{ID: paramID, Values: values})
+	}
+	sort.Slice(info.Parameters, func(i, j int) bool {
+		return info.Parameters[i].ID < info.Parameters[j].ID
+	})
+	return info
+}
+
+// sortedRules returns a copy of the rules sorted by rule ID.
+func sortedRules(rules []rule) []rule {
+	sorted := make([]rule, len(rules))
+	copy(sorted, rules)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
 // calculateRowLimit determines how many rows should be displayed based
 // on the number of rows availabe and the limit set by the user.
 func calculateRowLimit(rowLimit int, availableRows int) int {
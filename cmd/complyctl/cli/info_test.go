@@ -221,3 +221,50 @@ func TestCalculateRowLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestNewFrameworkInfo(t *testing.T) {
+	controls := []control{
+		{
+			ID:    "r2",
+			Title: "Second",
+			Rules: []rule{
+				{ID: "rule-b", Plugin: "openscap"},
+				{ID: "rule-a", Plugin: "openscap"},
+			},
+		},
+		{
+			ID:    "r1",
+			Title: "First",
+		},
+	}
+
+	info := newFrameworkInfo("example", controls)
+	require.Equal(t, "example", info.FrameworkID)
+	require.Len(t, info.Controls, 2)
+	require.Equal(t, "r1", info.Controls[0].ID)
+	require.Equal(t, "r2", info.Controls[1].ID)
+	require.Equal(t, []rule{{ID: "rule-a", Plugin: "openscap"}, {ID: "rule-b", Plugin: "openscap"}}, info.Controls[1].Rules)
+	// The input order must be left untouched.
+	require.Equal(t, "rule-b", controls[0].Rules[0].ID)
+}
+
+func TestNewRuleInfo(t *testing.T) {
+	ruleDetails := rule{
+		ID:          "rule-1",
+		Description: "Rule 1",
+		Parameters:  []string{"param-2", "param-1"},
+	}
+	setParameters := indexedSetParameters{
+		"param-1": {"value-1"},
+	}
+
+	wantInfo := ruleInfo{
+		ID:          "rule-1",
+		Description: "Rule 1",
+		Parameters: []ruleParameter{
+			{ID: "param-1", Values: []string{"value-1"}},
+			{ID: "param-2", Values: []string{}},
+		},
+	}
+	require.Equal(t, wantInfo, newRuleInfo(ruleDetails, setParameters))
+}
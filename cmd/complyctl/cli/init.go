@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// initOptions defines options for the "init" subcommand
+type initOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+}
+
+var initExample = `
+# Create the workspace in ./complytime.
+complyctl init
+
+# Create a workspace for a framework, so the plan command can be run without arguments.
+complyctl init myframework --workspace ./myworkspace
+
+# Create a workspace assessed against several frameworks, the first one being the primary framework.
+complyctl init cis mybaseline
+`
+
+// initCmd creates a new cobra.Command for the "init" subcommand
+func initCmd(common *option.Common) *cobra.Command {
+	initOpts := &initOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:               "init [flags] [id...]",
+		Short:             "Create a workspace with a state file for the assessment artifacts",
+		Example:           initExample,
+		SilenceUsage:      true,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completeFrameworkIDsList,
+		RunE: func(_ *cobra.Command, args []string) error {
+			for _, arg := range args {
+				frameworkID := filepath.Clean(arg)
+				if slices.Contains(initOpts.complyTimeOpts.FrameworkIDs, frameworkID) {
+					return fmt.Errorf("invalid arguments: framework %s is given more than once", frameworkID)
+				}
+				initOpts.complyTimeOpts.FrameworkIDs = append(initOpts.complyTimeOpts.FrameworkIDs, frameworkID)
+			}
+			return runInit(initOpts)
+		},
+	}
+	initOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runInit(opts *initOptions) error {
+	workspace := opts.complyTimeOpts.UserWorkspace
+	_, err := complytime.ReadWorkspaceState(workspace)
+	if err == nil {
+		return fmt.Errorf("workspace %s is already initialized, see %s", workspace, complytime.WorkspaceStatePath(workspace))
+	}
+	if !errors.Is(err, complytime.ErrWorkspaceStateNotFound) {
+		return err
+	}
+
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, true)
+	if err != nil {
+		return err
+	}
+	validator := validation.NewSchemaValidator()
+	state := complytime.NewWorkspaceState()
+	state.SetFrameworks(opts.complyTimeOpts.FrameworkIDs)
+	for _, frameworkID := range opts.complyTimeOpts.FrameworkIDs {
+		if err := checkFrameworkExists(appDir, frameworkID, validator); err != nil {
+			return err
+		}
+	}
+
+	// Adopt an assessment plan written before the workspace state existed.
+	ap, err := loadOptionalPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+	if ap != nil {
+		planFrameworkIDs, err := complytime.PlanFrameworkIDs(ap)
+		if err != nil {
+			return err
+		}
+		if frameworkIDs := state.FrameworkIDs(); len(frameworkIDs) > 0 && !slices.Equal(frameworkIDs, planFrameworkIDs) {
+			return fmt.Errorf("workspace %s has an assessment plan for framework %s, not %s", workspace, strings.Join(planFrameworkIDs, ", "), strings.Join(frameworkIDs, ", "))
+		}
+		apPath := filepath.Clean(filepath.Join(workspace, assessmentPlanLocation))
+		if err := state.RecordPlan(apPath, planFrameworkIDs, nil); err != nil {
+			return err
+		}
+	}
+
+	// A plugin directory is created for each installed plugin, none when plugins are not installed yet.
+	pluginIDs, err := complytime.FindPluginIDs(appDir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn(fmt.Sprintf("No plugin directories created in the workspace: %v", err))
+		}
+		pluginIDs = nil
+	}
+	if err := complytime.CreateWorkspaceLayout(workspace, pluginIDs); err != nil {
+		return err
+	}
+	if err := writeWorkspaceState(workspace, state); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("Workspace initialized in %s", workspace))
+	return nil
+}
+
+// checkFrameworkExists returns an error if no component definition implements the framework.
+func checkFrameworkExists(appDir complytime.ApplicationDirectory, frameworkID string, validator validation.Validator) error {
+	frameworks, err := complytime.LoadFrameworks(appDir, validator)
+	if err != nil {
+		return err
+	}
+	for _, framework := range frameworks {
+		if framework.ID == frameworkID {
+			return nil
+		}
+	}
+	return fmt.Errorf("framework %s not found in %s, run \"complyctl list\" to see the available frameworks", frameworkID, appDir.BundleDir())
+}
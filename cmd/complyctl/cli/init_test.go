@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestRunInit(t *testing.T) {
+	appDir := t.TempDir()
+	require.NoError(t, os.CopyFS(appDir, os.DirFS(filepath.Join("..", "..", "..", "internal", "complytime", "testdata", "complytime"))))
+	pluginDir := filepath.Join(appDir, complytime.PluginDir)
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "openscap-plugin"), []byte("#!/bin/sh\n"), 0700))
+	manifest := `{"metadata": {"id": "openscap", "types": ["pvp"]}, "executablePath": "openscap-plugin"}`
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "c2p-openscap-manifest.json"), []byte(manifest), 0600))
+
+	workspace := filepath.Join(t.TempDir(), "complytime")
+	opts := &initOptions{
+		Common:         &option.Common{ApplicationDirectory: appDir},
+		complyTimeOpts: &option.ComplyTime{UserWorkspace: workspace, FrameworkIDs: []string{"example"}},
+	}
+	require.NoError(t, runInit(opts))
+
+	for _, path := range []string{workspace, complytime.HistoryPath(workspace), filepath.Join(workspace, "openscap")} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.True(t, info.IsDir(), path)
+		require.Equal(t, os.FileMode(0700), info.Mode().Perm(), path)
+	}
+	state, err := complytime.ReadWorkspaceState(workspace)
+	require.NoError(t, err)
+	require.Equal(t, "example", state.FrameworkID)
+
+	require.EqualError(t, runInit(opts), "workspace "+workspace+" is already initialized, see "+complytime.WorkspaceStatePath(workspace))
+}
@@ -22,6 +22,7 @@ import (
 // listOptions defines options for the "list" subcommand
 type listOptions struct {
 	*option.Common
+	option.Format
 	// print a plain table only
 	plain bool
 }
@@ -35,16 +36,20 @@ func listCmd(common *option.Common) *cobra.Command {
 		Use:          "list [flags]",
 		Short:        "List information about supported frameworks and components.",
 		SilenceUsage: true,
-		Example:      "complyctl list",
+		Example:      "complyctl list\ncomplyctl list --output json",
 		Args:         cobra.NoArgs,
 		RunE:         func(_ *cobra.Command, _ []string) error { return runList(listOpts) },
 	}
 	cmd.Flags().BoolVarP(&listOpts.plain, "plain", "p", false, "print the table with minimal formatting")
+	listOpts.Format.BindFlags(cmd.Flags())
 	return cmd
 }
 
 func runList(opts *listOptions) error {
-	appDir, err := complytime.NewApplicationDirectory(true)
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, true)
 	if err != nil {
 		return err
 	}
@@ -56,7 +61,9 @@ func runList(opts *listOptions) error {
 		return err
 	}
 
-	if opts.plain {
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, sortFrameworks(frameworks))
+	} else if opts.plain {
 		showDefinitionTable(opts.Out, frameworks)
 	} else {
 		model := showPrettyDefinitionTable(frameworks)
@@ -101,6 +108,15 @@ func showPrettyDefinitionTable(frameworks []complytime.Framework) terminal.Model
 	}
 }
 
+// sortFrameworks orders frameworks by ID for consistent output.
+func sortFrameworks(frameworks []complytime.Framework) []complytime.Framework {
+	if frameworks == nil {
+		return []complytime.Framework{}
+	}
+	sort.SliceStable(frameworks, func(i, j int) bool { return frameworks[i].ID < frameworks[j].ID })
+	return frameworks
+}
+
 // getDefinitionColumnsAndRows returns populate columns and row for printing tables.
 func getDefinitionColumnsAndRows(frameworks []complytime.Framework) ([]table.Column, []table.Row) {
 	var rows []table.Row
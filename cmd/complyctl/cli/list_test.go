@@ -130,3 +130,10 @@ Choose an option from the Framework ID column to use with complyctl plan.
 		"Example Profile (moderate)    anotherexample      My Software                   \n" +
 		"Example Profile (low)         example             My Software                   \n"
 )
+
+func TestSortFrameworks(t *testing.T) {
+	require.Equal(t, []complytime.Framework{}, sortFrameworks(nil))
+
+	frameworks := []complytime.Framework{{ID: "b"}, {ID: "a"}}
+	require.Equal(t, []complytime.Framework{{ID: "a"}, {ID: "b"}}, sortFrameworks(frameworks))
+}
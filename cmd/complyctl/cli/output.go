@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/goccy/go-yaml"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+)
+
+// writeStructured serializes the given value to the writer in the
+// requested machine-readable format.
+func writeStructured(writer io.Writer, format string, value any) error {
+	var data []byte
+	var err error
+	switch format {
+	case option.OutputFormatJSON:
+		data, err = json.MarshalIndent(value, "", "  ")
+		data = append(data, '\n')
+	case option.OutputFormatYAML:
+		data, err = yaml.Marshal(value)
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("error marshalling %s output: %w", format, err)
+	}
+	_, err = writer.Write(data)
+	return err
+}
+
+// fitColumnWidths widens the columns to fit the cells of all rows,
+// keeping a space between columns for plain output.
+func fitColumnWidths(columns []table.Column, rows []table.Row) {
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell)+1 > columns[i].Width {
+				columns[i].Width = len(cell) + 1
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestWriteStructured(t *testing.T) {
+	frameworks := []complytime.Framework{
+		{
+			ID:                  "example",
+			Title:               "Example Profile (low)",
+			SupportedComponents: []string{"My Software"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		format   string
+		wantView string
+		wantErr  string
+	}{
+		{
+			name:   "Valid/JSON",
+			format: option.OutputFormatJSON,
+			wantView: `[
+  {
+    "id": "example",
+    "title": "Example Profile (low)",
+    "supportedComponents": [
+      "My Software"
+    ]
+  }
+]
+`,
+		},
+		{
+			name:   "Valid/YAML",
+			format: option.OutputFormatYAML,
+			wantView: `- id: example
+  title: Example Profile (low)
+  supportedComponents:
+  - My Software
+`,
+		},
+		{
+			name:    "Invalid/Format",
+			format:  "xml",
+			wantErr: "unsupported output format \"xml\"",
+		},
+	}
+
+	for _, c := range tests {
+		t.Run(c.name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			err := writeStructured(out, c.format, frameworks)
+			if c.wantErr != "" {
+				require.EqualError(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.wantView, out.String())
+		})
+	}
+}
+
+func TestFormatValidate(t *testing.T) {
+	require.NoError(t, (&option.Format{}).Validate())
+	require.NoError(t, (&option.Format{OutputFormat: "json"}).Validate())
+	require.NoError(t, (&option.Format{OutputFormat: "yaml"}).Validate())
+	require.EqualError(t, (&option.Format{OutputFormat: "csv"}).Validate(),
+		"invalid output format \"csv\": must be one of \"json\" or \"yaml\"")
+}
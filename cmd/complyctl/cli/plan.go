@@ -3,11 +3,16 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/goccy/go-yaml"
 	"github.com/oscal-compass/oscal-sdk-go/transformers"
@@ -16,6 +21,7 @@ import (
 
 	"github.com/complytime/complyctl/cmd/complyctl/option"
 	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
 )
 
 const assessmentPlanLocation = "assessment-plan.json"
@@ -23,22 +29,35 @@ const assessmentPlanLocation = "assessment-plan.json"
 // PlanOptions defines options for the "plan" subcommand
 type planOptions struct {
 	*option.Common
+	option.Format
 	complyTimeOpts *option.ComplyTime
 
 	// dryRun loads the defaults and prints the config to stdout
 	dryRun bool
 
-	// WithScopeConfig "config.yml" to customize the generated assessment plan
-	withScopeConfig string
+	// WithScopeConfig "config.yml" to customize the generated assessment plan, one per framework
+	withScopeConfig []string
 
 	// Out
 	output string
+
+	// interactive edits the assessment scope in the terminal
+	interactive bool
+
+	// strict fails on scope config entries that do not match the framework
+	strict bool
+
+	// update regenerates the workspace plan, keeping its scope
+	update bool
 }
 
 var planExample = `
 # The default behavior is to prepare a default assessment plan with all defined controls within the framework in scope.
 complytime plan myframework
 
+# Prepare the assessment plan for the framework recorded in the workspace by "complyctl init myframework".
+complytime plan
+
 # To see the default contents of the assessment plan, run in dry-run mode.
 complytime plan myframework --dry-run
 
@@ -47,6 +66,21 @@ complytime plan myframework --dry-run --out config.yml
 
 # Alter the configuration and use it as input for plan customization.
 complytime plan myframework --scope-config config.yml
+
+# Fail instead of warning when the configuration has unknown control or rule IDs.
+complytime plan myframework --scope-config config.yml --strict
+
+# Plan several frameworks in the same workspace, the first one being the primary framework.
+# Shared rules are assessed once. Each scope config applies to the framework of its frameworkId.
+complytime plan cis mybaseline --scope-config cis.yml --scope-config mybaseline.yml
+
+# Regenerate the assessment plan after upgrading bundles, keeping its controls, rule exclusions
+# and parameter values. The new, removed and conflicting controls and rules are reported.
+complytime plan --update
+
+# Select the controls and rules in the terminal, then apply the scope with "a"
+# or save it to config.yml with "s".
+complytime plan myframework --interactive --out config.yml
 `
 
 // planCmd creates a new cobra.Command for the "plan" subcommand
@@ -56,10 +90,11 @@ func planCmd(common *option.Common) *cobra.Command {
 		complyTimeOpts: &option.ComplyTime{},
 	}
 	cmd := &cobra.Command{
-		Use:     "plan [flags] id",
-		Short:   "Generate a new assessment plan for a given compliance framework id.",
-		Example: planExample,
-		Args:    cobra.ExactArgs(1),
+		Use:               "plan [flags] [id...]",
+		Short:             "Generate a new assessment plan for the given compliance framework ids.",
+		Example:           planExample,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completeFrameworkIDsList,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			completePlan(planOpts, args)
 		},
@@ -71,33 +106,98 @@ func planCmd(common *option.Common) *cobra.Command {
 		},
 	}
 	cmd.Flags().BoolVar(&planOpts.dryRun, "dry-run", false, "load the defaults and print the config to stdout")
-	cmd.Flags().StringVarP(&planOpts.withScopeConfig, "scope-config", "s", "", "load config.yml to customize the generated assessment plan")
+	cmd.Flags().StringArrayVarP(&planOpts.withScopeConfig, "scope-config", "s", nil, "load config.yml to customize the generated assessment plan, once per framework")
 	cmd.Flags().StringVarP(&planOpts.output, "out", "o", "-", "path to output file. Use '-' for stdout. Default '-'.")
+	cmd.Flags().BoolVarP(&planOpts.interactive, "interactive", "i", false, "select the controls and rules of the assessment plan in the terminal")
+	cmd.Flags().BoolVar(&planOpts.strict, "strict", false, "fail when the scope config has controls or rules unknown to the framework")
+	cmd.Flags().BoolVar(&planOpts.update, "update", false, "regenerate the assessment plan from the current bundles, keeping its scope")
+	cmd.Flags().StringVar(&planOpts.OutputFormat, "output", "", "output format of the changes made by --update, one of: json, yaml")
 	planOpts.complyTimeOpts.BindFlags(cmd.Flags())
 	return cmd
 }
 
 func completePlan(opts *planOptions, args []string) {
-	if len(args) == 1 {
-		opts.complyTimeOpts.FrameworkID = filepath.Clean(args[0])
+	for _, arg := range args {
+		opts.complyTimeOpts.FrameworkIDs = append(opts.complyTimeOpts.FrameworkIDs, filepath.Clean(arg))
+	}
+	if len(opts.complyTimeOpts.FrameworkIDs) > 0 {
+		opts.complyTimeOpts.FrameworkID = opts.complyTimeOpts.FrameworkIDs[0]
 	}
 }
 
 func validatePlan(opts *planOptions) error {
-	if opts.output != "-" && !opts.dryRun {
+	if opts.interactive && opts.dryRun {
+		return errors.New("invalid command flags: \"--interactive\" cannot be used with \"--dry-run\"")
+	}
+	if opts.output != "-" && !opts.dryRun && !opts.interactive {
 		return errors.New("invalid command flags: \"--dry-run\" must be used with \"--out\"")
 	}
+	if opts.strict && len(opts.withScopeConfig) == 0 {
+		return errors.New("invalid command flags: \"--strict\" must be used with \"--scope-config\"")
+	}
+	if opts.update {
+		switch {
+		case opts.dryRun:
+			return errors.New("invalid command flags: \"--update\" cannot be used with \"--dry-run\"")
+		case opts.interactive:
+			return errors.New("invalid command flags: \"--update\" cannot be used with \"--interactive\"")
+		case len(opts.withScopeConfig) > 0:
+			return errors.New("invalid command flags: \"--update\" cannot be used with \"--scope-config\"")
+		}
+		if err := opts.Format.Validate(); err != nil {
+			return err
+		}
+	}
+	for i, frameworkID := range opts.complyTimeOpts.FrameworkIDs {
+		if slices.Contains(opts.complyTimeOpts.FrameworkIDs[:i], frameworkID) {
+			return fmt.Errorf("invalid arguments: framework %s is given more than once", frameworkID)
+		}
+	}
+	return validatePlanFrameworks(opts)
+}
+
+// validatePlanFrameworks checks the options that only apply to a single framework.
+func validatePlanFrameworks(opts *planOptions) error {
+	if len(opts.complyTimeOpts.FrameworkIDs) <= 1 {
+		return nil
+	}
+	if opts.dryRun {
+		return errors.New("invalid command flags: \"--dry-run\" must be used with a single framework")
+	}
+	if opts.interactive {
+		return errors.New("invalid command flags: \"--interactive\" must be used with a single framework")
+	}
 	return nil
 }
 
 func runPlan(cmd *cobra.Command, opts *planOptions) error {
 	// Create the application directory if it does not exist
-	appDir, err := complytime.NewApplicationDirectory(true)
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, true)
 	if err != nil {
 		return err
 	}
 	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
 
+	// Use the frameworks recorded in the workspace when none are given.
+	state, err := readWorkspaceState(opts.complyTimeOpts.UserWorkspace)
+	if err != nil {
+		return err
+	}
+	if opts.update {
+		return runPlanUpdate(cmd, opts, appDir, state)
+	}
+	if len(opts.complyTimeOpts.FrameworkIDs) == 0 {
+		opts.complyTimeOpts.FrameworkID = state.FrameworkID
+		opts.complyTimeOpts.FrameworkIDs = state.FrameworkIDs()
+	}
+	if opts.complyTimeOpts.FrameworkID == "" {
+		return errors.New("a framework id is required: pass it as an argument or initialize the workspace with \"complyctl init <id>\"")
+	}
+	if err := validatePlanFrameworks(opts); err != nil {
+		return err
+	}
+	frameworkIDs := opts.complyTimeOpts.FrameworkIDs
+
 	validator := validation.NewSchemaValidator()
 	componentDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validator)
 	if err != nil {
@@ -106,35 +206,169 @@ func runPlan(cmd *cobra.Command, opts *planOptions) error {
 
 	if opts.dryRun {
 		// Write the plan configuration to stdout
-		return planDryRun(opts.complyTimeOpts.FrameworkID, componentDefs, opts.output)
+		return planDryRun(cmd.Context(), appDir, opts.complyTimeOpts.FrameworkID, componentDefs, opts.output)
+	}
+
+	assessmentScopes, err := loadScopeConfigs(opts.withScopeConfig, frameworkIDs)
+	if err != nil {
+		return err
+	}
+	for _, frameworkID := range frameworkIDs {
+		config, found := assessmentScopes[frameworkID]
+		if !found {
+			continue
+		}
+		if err := checkScopeConfig(*config.scope, config.path, frameworkID, componentDefs, opts.strict); err != nil {
+			return err
+		}
+	}
+
+	if opts.interactive {
+		var assessmentScope *complytime.AssessmentScope
+		if config, found := assessmentScopes[opts.complyTimeOpts.FrameworkID]; found {
+			assessmentScope = config.scope
+		}
+		scope, action, err := editScope(opts, appDir, validator, componentDefs, assessmentScope)
+		if err != nil {
+			return err
+		}
+		switch action {
+		case terminal.ScopeSave:
+			return writeScopeConfig(scope, opts.output)
+		case terminal.ScopeApply:
+			assessmentScopes[opts.complyTimeOpts.FrameworkID] = scopeConfig{scope: &scope}
+		default:
+			logger.Info("Assessment plan not changed")
+			return nil
+		}
 	}
 
 	logger.Debug(fmt.Sprintf("Using bundle directory: %s for component definitions.", appDir.BundleDir()))
-	assessmentPlan, err := transformers.ComponentDefinitionsToAssessmentPlan(cmd.Context(), componentDefs, opts.complyTimeOpts.FrameworkID)
+	frameworkPlans := make([]complytime.FrameworkPlan, 0, len(frameworkIDs))
+	for _, frameworkID := range frameworkIDs {
+		frameworkPlan, err := transformers.ComponentDefinitionsToAssessmentPlan(cmd.Context(), componentDefs, frameworkID)
+		if err != nil {
+			return err
+		}
+		if config, found := assessmentScopes[frameworkID]; found {
+			config.scope.ApplyScope(frameworkPlan, logger)
+		}
+		frameworkPlans = append(frameworkPlans, complytime.FrameworkPlan{FrameworkID: frameworkID, Plan: frameworkPlan})
+	}
+	planPath, err := savePlan(opts.complyTimeOpts.UserWorkspace, state, frameworkPlans, componentDefs)
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("Assessment plan written to %s\n", planPath))
+	return nil
+}
+
+// runPlanUpdate regenerates the assessment plan of the workspace from the current component definitions,
+// carrying over the scope of the previous plan, and reports the changes for each framework.
+func runPlanUpdate(cmd *cobra.Command, opts *planOptions, appDir complytime.ApplicationDirectory, state *complytime.WorkspaceState) error {
+	validator := validation.NewSchemaValidator()
+	previousPlan, planPath, err := loadPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+	frameworkIDs, err := complytime.PlanFrameworkIDs(previousPlan)
+	if err != nil {
+		return err
+	}
+	givenIDs := opts.complyTimeOpts.FrameworkIDs
+	if len(givenIDs) > 0 && !slices.Equal(givenIDs, frameworkIDs) {
+		return fmt.Errorf("assessment plan %s is for %s: run \"complyctl plan %s\" without \"--update\" to plan other frameworks",
+			planPath, strings.Join(frameworkIDs, ", "), strings.Join(givenIDs, " "))
+	}
+
+	componentDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validator)
 	if err != nil {
 		return err
 	}
 
-	if opts.withScopeConfig != "" {
-		configBytes, err := os.ReadFile(filepath.Clean(opts.withScopeConfig))
+	frameworkPlans := make([]complytime.FrameworkPlan, 0, len(frameworkIDs))
+	updates := make([]complytime.PlanUpdate, 0, len(frameworkIDs))
+	for _, frameworkID := range frameworkIDs {
+		frameworkPlan, err := transformers.ComponentDefinitionsToAssessmentPlan(cmd.Context(), componentDefs, frameworkID)
 		if err != nil {
-			return fmt.Errorf("error reading assessment plan: %w", err)
+			return err
 		}
-		assessmentScope := complytime.AssessmentScope{}
-		if err := yaml.Unmarshal(configBytes, &assessmentScope); err != nil {
-			return fmt.Errorf("error unmarshaling assessment plan: %w", err)
+		scope, update := complytime.UpdateScope(previousPlan, frameworkPlan, frameworkID)
+		scope.ApplyScope(frameworkPlan, logger)
+		frameworkPlans = append(frameworkPlans, complytime.FrameworkPlan{FrameworkID: frameworkID, Plan: frameworkPlan})
+		updates = append(updates, update)
+	}
+	planPath, err = savePlan(opts.complyTimeOpts.UserWorkspace, state, frameworkPlans, componentDefs)
+	if err != nil {
+		return err
+	}
+	if opts.Structured() {
+		logger.Debug(fmt.Sprintf("Assessment plan written to %s", planPath))
+		return writeStructured(opts.Out, opts.OutputFormat, updates)
+	}
+	logger.Info(fmt.Sprintf("Assessment plan written to %s\n", planPath))
+	showPlanUpdates(opts.Out, updates)
+	return nil
+}
+
+// savePlan merges the plans of the frameworks, writes the assessment plan to the workspace and
+// records it in the workspace state. The path of the written plan is returned.
+func savePlan(workspace string, state *complytime.WorkspaceState, frameworkPlans []complytime.FrameworkPlan, componentDefs []oscalTypes.ComponentDefinition) (string, error) {
+	frameworkIDs := make([]string, 0, len(frameworkPlans))
+	for _, frameworkPlan := range frameworkPlans {
+		frameworkIDs = append(frameworkIDs, frameworkPlan.FrameworkID)
+	}
+	assessmentPlan := frameworkPlans[0].Plan
+	if len(frameworkPlans) > 1 {
+		var err error
+		assessmentPlan, err = complytime.MergePlans(frameworkPlans, logger)
+		if err != nil {
+			return "", err
 		}
-		assessmentScope.ApplyScope(assessmentPlan, logger)
 	}
 
-	filePath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentPlanLocation)
+	filePath := filepath.Join(workspace, assessmentPlanLocation)
 	cleanedPath := filepath.Clean(filePath)
 
-	if err := complytime.WritePlan(assessmentPlan, opts.complyTimeOpts.FrameworkID, cleanedPath); err != nil {
-		return fmt.Errorf("error writing assessment plan to %s: %w", cleanedPath, err)
+	if err := complytime.WritePlan(assessmentPlan, cleanedPath, frameworkIDs...); err != nil {
+		return "", fmt.Errorf("error writing assessment plan to %s: %w", cleanedPath, err)
+	}
+
+	bundles := complytime.BundleVersions(componentDefs, frameworkIDs...)
+	if err := state.RecordPlan(cleanedPath, frameworkIDs, bundles); err != nil {
+		return "", err
+	}
+	return cleanedPath, writeWorkspaceState(workspace, state)
+}
+
+// showPlanUpdates prints the changes of the regenerated assessment plan for each framework.
+func showPlanUpdates(writer io.Writer, updates []complytime.PlanUpdate) {
+	for i, update := range updates {
+		if i > 0 {
+			_, _ = fmt.Fprintln(writer)
+		}
+		if update.Empty() {
+			_, _ = fmt.Fprintf(writer, "Framework %s: no changes.\n", update.FrameworkID)
+			continue
+		}
+		_, _ = fmt.Fprintf(writer, "Framework %s\n", update.FrameworkID)
+		showPlanUpdateList(writer, "New controls, left out of scope", update.NewControls)
+		showPlanUpdateList(writer, "Removed controls", update.RemovedControls)
+		showPlanUpdateList(writer, "New rules", update.NewRules)
+		showPlanUpdateList(writer, "Removed rules", update.RemovedRules)
+		showPlanUpdateList(writer, "Conflicts to review", update.Conflicts)
+	}
+}
+
+// showPlanUpdateList prints a titled list of changes, if any.
+func showPlanUpdateList(writer io.Writer, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(writer, "  %s (%d):\n", title, len(items))
+	for _, item := range items {
+		_, _ = fmt.Fprintf(writer, "    - %s\n", item)
 	}
-	logger.Info(fmt.Sprintf("Assessment plan written to %s\n", cleanedPath))
-	return nil
 }
 
 // loadPlan returns the loaded assessment plan and path from the workspace.
@@ -154,13 +388,9 @@ func loadPlan(opts *option.ComplyTime, validator validation.Validator) (*oscalTy
 }
 
 // planDryRun leverages the AssessmentScope structure to populate tailoring config.
-// The config is written to stdout.
-func planDryRun(frameworkId string, cds []oscalTypes.ComponentDefinition, output string) error {
-	// Create application directory and validator to get control titles
-	appDir, err := complytime.NewApplicationDirectory(true)
-	if err != nil {
-		return fmt.Errorf("failed to initialize application directory: %w", err)
-	}
+// The config is written to stdout, with the default values of the rule parameters.
+func planDryRun(ctx context.Context, appDir complytime.ApplicationDirectory, frameworkId string, cds []oscalTypes.ComponentDefinition, output string) error {
+	// Use a validator to get control titles
 	validator := validation.NewSchemaValidator()
 
 	logger.Debug("Loading control titles for framework", "frameworkId", frameworkId)
@@ -169,6 +399,17 @@ func planDryRun(frameworkId string, cds []oscalTypes.ComponentDefinition, output
 		return fmt.Errorf("error creating assessment scope for %s: %w", frameworkId, err)
 	}
 	logger.Debug("Assessment scope created", "controls", len(scope.IncludeControls))
+
+	assessmentPlan, err := transformers.ComponentDefinitionsToAssessmentPlan(ctx, cds, frameworkId)
+	if err != nil {
+		return fmt.Errorf("error reading rule parameters for %s: %w", frameworkId, err)
+	}
+	scope.Parameters = complytime.PlanParameters(assessmentPlan)
+	return writeScopeConfig(scope, output)
+}
+
+// writeScopeConfig writes the assessment scope as YAML to output, or to stdout when output is "-".
+func writeScopeConfig(scope complytime.AssessmentScope, output string) error {
 	data, err := yaml.Marshal(&scope)
 	if err != nil {
 		return fmt.Errorf("error marshalling yaml content: %v", err)
@@ -181,3 +422,97 @@ func planDryRun(frameworkId string, cds []oscalTypes.ComponentDefinition, output
 	}
 	return nil
 }
+
+// loadScopeConfig reads an assessment scope from a config file.
+func loadScopeConfig(path string) (*complytime.AssessmentScope, error) {
+	configBytes, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading assessment plan: %w", err)
+	}
+	assessmentScope := &complytime.AssessmentScope{}
+	if err := yaml.Unmarshal(configBytes, assessmentScope); err != nil {
+		return nil, fmt.Errorf("error unmarshaling assessment plan: %w", err)
+	}
+	if err := assessmentScope.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scope config %s: %w", path, err)
+	}
+	return assessmentScope, nil
+}
+
+// scopeConfig is an assessment scope loaded from a config file.
+type scopeConfig struct {
+	path  string
+	scope *complytime.AssessmentScope
+}
+
+// loadScopeConfigs reads the scope configs at paths by framework. With a single framework, the config
+// applies to it whatever its frameworkId. Otherwise, each config applies to the framework of its
+// frameworkId, which must be one of the planned frameworks.
+func loadScopeConfigs(paths []string, frameworkIDs []string) (map[string]scopeConfig, error) {
+	scopeConfigs := make(map[string]scopeConfig, len(paths))
+	for _, path := range paths {
+		assessmentScope, err := loadScopeConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		frameworkID := frameworkIDs[0]
+		if len(frameworkIDs) > 1 {
+			frameworkID = assessmentScope.FrameworkID
+			if !slices.Contains(frameworkIDs, frameworkID) {
+				return nil, fmt.Errorf("scope config %s is for framework %s, which is not planned: expected one of %s", path, frameworkID, strings.Join(frameworkIDs, ", "))
+			}
+		}
+		if existing, found := scopeConfigs[frameworkID]; found {
+			return nil, fmt.Errorf("scope configs %s and %s are both for framework %s", existing.path, path, frameworkID)
+		}
+		scopeConfigs[frameworkID] = scopeConfig{path: path, scope: assessmentScope}
+	}
+	return scopeConfigs, nil
+}
+
+// checkScopeConfig checks the control and rule IDs of a scope config against the component definitions
+// of the framework. Entries that do not match fail in strict mode and are logged as warnings otherwise.
+func checkScopeConfig(scope complytime.AssessmentScope, path, frameworkID string, cds []oscalTypes.ComponentDefinition, strict bool) error {
+	// Without a validator, control titles are not loaded from the application directory.
+	available, err := complytime.NewAssessmentScopeFromCDs(frameworkID, complytime.ApplicationDirectory{}, nil, cds...)
+	if err != nil {
+		return fmt.Errorf("error creating assessment scope for %s: %w", frameworkID, err)
+	}
+	scopeErrors := scope.CheckControls(frameworkID, available.IncludeControls, complytime.ControlRules(frameworkID, cds...))
+	if len(scopeErrors) == 0 {
+		return nil
+	}
+	if strict {
+		messages := make([]string, 0, len(scopeErrors))
+		for _, scopeError := range scopeErrors {
+			messages = append(messages, scopeError.String())
+		}
+		return fmt.Errorf("scope config %s does not match framework %s:\n  %s", path, frameworkID, strings.Join(messages, "\n  "))
+	}
+	for _, scopeError := range scopeErrors {
+		logger.Warn(fmt.Sprintf("Scope config %s: %s", path, scopeError))
+	}
+	return nil
+}
+
+// editScope lets the user edit the assessment scope in the terminal, starting from the given
+// scope or from all the controls of the framework when scope is nil.
+func editScope(opts *planOptions, appDir complytime.ApplicationDirectory, validator validation.Validator, cds []oscalTypes.ComponentDefinition, scope *complytime.AssessmentScope) (complytime.AssessmentScope, terminal.ScopeAction, error) {
+	frameworkID := opts.complyTimeOpts.FrameworkID
+	available, err := complytime.NewAssessmentScopeFromCDs(frameworkID, appDir, validator, cds...)
+	if err != nil {
+		return complytime.AssessmentScope{}, terminal.ScopeQuit, fmt.Errorf("error creating assessment scope for %s: %w", frameworkID, err)
+	}
+	if scope == nil {
+		scope = &available
+	}
+	if scope.FrameworkID != frameworkID {
+		return complytime.AssessmentScope{}, terminal.ScopeQuit, fmt.Errorf("scope config is for framework %s, not %s", scope.FrameworkID, frameworkID)
+	}
+
+	model := terminal.NewScopeModel(*scope, available.IncludeControls, complytime.ControlRules(frameworkID, cds...))
+	if _, err := tea.NewProgram(model, tea.WithAltScreen(), tea.WithOutput(opts.Out)).Run(); err != nil {
+		return complytime.AssessmentScope{}, terminal.ScopeQuit, fmt.Errorf("failed to run the scope editor: %w", err)
+	}
+	return model.Scope(), model.Action(), nil
+}
@@ -3,13 +3,19 @@
 package cli
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 	"github.com/stretchr/testify/require"
 
 	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
 )
 
 func TestPlansInWorkspace(t *testing.T) {
@@ -59,11 +65,131 @@ func TestValidatePlan(t *testing.T) {
 			wantErr: "" +
 				"invalid command flags: \"--dry-run\" must be used with \"--out\"",
 		},
+		{
+			name: "Valid/InteractiveWithOut",
+			opts: planOptions{
+				interactive: true,
+				output:      "myconfig.yml",
+			},
+		},
+		{
+			name: "Invalid/InteractiveDryRun",
+			opts: planOptions{
+				interactive: true,
+				dryRun:      true,
+				output:      "-",
+			},
+			wantErr: "invalid command flags: \"--interactive\" cannot be used with \"--dry-run\"",
+		},
+		{
+			name: "Valid/StrictScopeConfig",
+			opts: planOptions{
+				strict:          true,
+				withScopeConfig: []string{"config.yml"},
+				output:          "-",
+			},
+		},
+		{
+			name: "Invalid/StrictNoScopeConfig",
+			opts: planOptions{
+				strict: true,
+				output: "-",
+			},
+			wantErr: "invalid command flags: \"--strict\" must be used with \"--scope-config\"",
+		},
+		{
+			name: "Valid/SeveralFrameworks",
+			opts: planOptions{
+				complyTimeOpts:  &option.ComplyTime{FrameworkIDs: []string{"cis", "baseline"}},
+				withScopeConfig: []string{"cis.yml", "baseline.yml"},
+				output:          "-",
+			},
+		},
+		{
+			name: "Invalid/DuplicateFramework",
+			opts: planOptions{
+				complyTimeOpts: &option.ComplyTime{FrameworkIDs: []string{"cis", "cis"}},
+				output:         "-",
+			},
+			wantErr: "invalid arguments: framework cis is given more than once",
+		},
+		{
+			name: "Invalid/DryRunSeveralFrameworks",
+			opts: planOptions{
+				complyTimeOpts: &option.ComplyTime{FrameworkIDs: []string{"cis", "baseline"}},
+				dryRun:         true,
+				output:         "-",
+			},
+			wantErr: "invalid command flags: \"--dry-run\" must be used with a single framework",
+		},
+		{
+			name: "Invalid/InteractiveSeveralFrameworks",
+			opts: planOptions{
+				complyTimeOpts: &option.ComplyTime{FrameworkIDs: []string{"cis", "baseline"}},
+				interactive:    true,
+				output:         "-",
+			},
+			wantErr: "invalid command flags: \"--interactive\" must be used with a single framework",
+		},
+		{
+			name: "Valid/Update",
+			opts: planOptions{
+				update: true,
+				output: "-",
+			},
+		},
+		{
+			name: "Invalid/UpdateDryRun",
+			opts: planOptions{
+				update: true,
+				dryRun: true,
+				output: "-",
+			},
+			wantErr: "invalid command flags: \"--update\" cannot be used with \"--dry-run\"",
+		},
+		{
+			name: "Invalid/UpdateInteractive",
+			opts: planOptions{
+				update:      true,
+				interactive: true,
+				output:      "-",
+			},
+			wantErr: "invalid command flags: \"--update\" cannot be used with \"--interactive\"",
+		},
+		{
+			name: "Invalid/UpdateScopeConfig",
+			opts: planOptions{
+				update:          true,
+				withScopeConfig: []string{"config.yml"},
+				output:          "-",
+			},
+			wantErr: "invalid command flags: \"--update\" cannot be used with \"--scope-config\"",
+		},
+		{
+			name: "Valid/UpdateOutput",
+			opts: planOptions{
+				update: true,
+				Format: option.Format{OutputFormat: option.OutputFormatJSON},
+				output: "-",
+			},
+		},
+		{
+			name: "Invalid/UpdateOutput",
+			opts: planOptions{
+				update: true,
+				Format: option.Format{OutputFormat: "csv"},
+				output: "-",
+			},
+			wantErr: "invalid output format \"csv\": must be one of \"json\" or \"yaml\"",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			fmt.Println(tt.opts)
+			if tt.opts.complyTimeOpts == nil {
+				tt.opts.complyTimeOpts = &option.ComplyTime{}
+			}
 			err := validatePlan(&tt.opts)
 			if tt.wantErr != "" {
 				require.EqualError(t, err, tt.wantErr)
@@ -73,3 +199,109 @@ func TestValidatePlan(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadScopeConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	config := `frameworkId: example
+includeControls:
+  - controlId: "ac-*"
+    includeRules: ["*"]
+excludeControls: ["re:^ac-1[0-9]$"]
+`
+	require.NoError(t, os.WriteFile(path, []byte(config), 0600))
+	scope, err := loadScopeConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"re:^ac-1[0-9]$"}, scope.ExcludeControls)
+
+	require.NoError(t, os.WriteFile(path, []byte(config+"globalExcludeRules: [\"re:(\"]\n"), 0600))
+	_, err = loadScopeConfig(path)
+	require.ErrorContains(t, err, `invalid pattern "re:("`)
+}
+
+func TestLoadScopeConfigs(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig := func(name, frameworkID string) string {
+		path := filepath.Join(dir, name)
+		config := "frameworkId: " + frameworkID + "\nincludeControls:\n  - controlId: ac-1\n"
+		require.NoError(t, os.WriteFile(path, []byte(config), 0600))
+		return path
+	}
+	cisPath := writeConfig("cis.yml", "cis")
+	baselinePath := writeConfig("baseline.yml", "baseline")
+
+	scopeConfigs, err := loadScopeConfigs([]string{cisPath, baselinePath}, []string{"cis", "baseline"})
+	require.NoError(t, err)
+	require.Len(t, scopeConfigs, 2)
+	require.Equal(t, baselinePath, scopeConfigs["baseline"].path)
+	require.Equal(t, "baseline", scopeConfigs["baseline"].scope.FrameworkID)
+
+	// A single framework takes the config whatever its framework.
+	scopeConfigs, err = loadScopeConfigs([]string{baselinePath}, []string{"cis"})
+	require.NoError(t, err)
+	require.Equal(t, baselinePath, scopeConfigs["cis"].path)
+
+	_, err = loadScopeConfigs([]string{cisPath, baselinePath}, []string{"cis"})
+	require.EqualError(t, err, fmt.Sprintf("scope configs %s and %s are both for framework cis", cisPath, baselinePath))
+
+	_, err = loadScopeConfigs([]string{baselinePath}, []string{"cis", "other"})
+	require.EqualError(t, err, fmt.Sprintf("scope config %s is for framework baseline, which is not planned: expected one of cis, other", baselinePath))
+}
+
+func TestCheckScopeConfig(t *testing.T) {
+	cd := oscalTypes.ComponentDefinition{
+		Components: &[]oscalTypes.DefinedComponent{
+			{
+				Title: "Component",
+				ControlImplementations: &[]oscalTypes.ControlImplementationSet{
+					{
+						Props: &[]oscalTypes.Property{
+							{Name: extensions.FrameworkProp, Value: "example", Ns: extensions.TrestleNameSpace},
+						},
+						ImplementedRequirements: []oscalTypes.ImplementedRequirementControlImplementation{
+							{
+								ControlId: "ac-1",
+								Props:     &[]oscalTypes.Property{{Name: extensions.RuleIdProp, Value: "audit_enabled"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cds := []oscalTypes.ComponentDefinition{cd}
+	scope := complytime.AssessmentScope{
+		FrameworkID:     "example",
+		IncludeControls: []complytime.ControlEntry{{ControlID: "ac-1", IncludeRules: []string{"audit_enable"}}},
+	}
+
+	require.NoError(t, checkScopeConfig(scope, "config.yml", "example", cds, false))
+	err := checkScopeConfig(scope, "config.yml", "example", cds, true)
+	require.EqualError(t, err, "scope config config.yml does not match framework example:\n"+
+		"  includeControls[0].includeRules[0]: unknown rule \"audit_enable\", did you mean \"audit_enabled\"?")
+
+	scope.IncludeControls[0].IncludeRules = []string{"*"}
+	require.NoError(t, checkScopeConfig(scope, "config.yml", "example", cds, true))
+}
+
+func TestShowPlanUpdates(t *testing.T) {
+	var out bytes.Buffer
+	showPlanUpdates(&out, []complytime.PlanUpdate{
+		{
+			FrameworkID:  "cis",
+			NewControls:  []string{"ac-2"},
+			RemovedRules: []string{"rule-4"},
+			Conflicts:    []string{"control ac-1 is no longer assessed by any rule"},
+		},
+		{FrameworkID: "baseline"},
+	})
+	require.Equal(t, `Framework cis
+  New controls, left out of scope (1):
+    - ac-2
+  Removed rules (1):
+    - rule-4
+  Conflicts to review (1):
+    - control ac-1 is no longer assessed by any rule
+
+Framework baseline: no changes.
+`, out.String())
+}
@@ -0,0 +1,308 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// pluginsOptions defines options for the "plugins" subcommands
+type pluginsOptions struct {
+	*option.Common
+	option.Format
+	complyTimeOpts   *option.ComplyTime
+	withPluginConfig string
+}
+
+// pluginInfo describes an installed plugin and the result of its verification.
+type pluginInfo struct {
+	ID             string                    `json:"id" yaml:"id"`
+	Description    string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	Version        string                    `json:"version,omitempty" yaml:"version,omitempty"`
+	Types          []string                  `json:"types,omitempty" yaml:"types,omitempty"`
+	ExecutablePath string                    `json:"executablePath,omitempty" yaml:"executablePath,omitempty"`
+	Checksum       string                    `json:"sha256,omitempty" yaml:"sha256,omitempty"`
+	Verified       bool                      `json:"verified" yaml:"verified"`
+	Error          string                    `json:"error,omitempty" yaml:"error,omitempty"`
+	Options        []complytime.PluginOption `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+var pluginsExample = `
+# List installed plugins and whether they pass verification.
+complyctl plugins list
+
+# Show the manifest metadata and resolved configuration of a plugin.
+complyctl plugins show openscap
+
+# Show the configuration resolved with user customized plugin manifests.
+complyctl plugins show openscap --plugin-config ./config.d
+
+# Verify the manifest and executable checksum of all plugins.
+complyctl plugins verify
+`
+
+// pluginsCmd creates a new cobra.Command for the "plugins" subcommand
+func pluginsCmd(common *option.Common) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "plugins",
+		Short:   "List, inspect and verify installed plugins",
+		Example: pluginsExample,
+		Args:    cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		pluginsListCmd(common),
+		pluginsShowCmd(common),
+		pluginsVerifyCmd(common),
+	)
+	return cmd
+}
+
+func newPluginsOptions(common *option.Common) *pluginsOptions {
+	return &pluginsOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+}
+
+func pluginsListCmd(common *option.Common) *cobra.Command {
+	pluginsOpts := newPluginsOptions(common)
+	cmd := &cobra.Command{
+		Use:          "list [flags]",
+		Short:        "List installed plugins",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runPluginsList(pluginsOpts)
+		},
+	}
+	pluginsOpts.Format.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func pluginsShowCmd(common *option.Common) *cobra.Command {
+	pluginsOpts := newPluginsOptions(common)
+	cmd := &cobra.Command{
+		Use:          "show [flags] id",
+		Short:        "Show the manifest metadata and resolved configuration of a plugin",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runPluginsShow(pluginsOpts, args[0])
+		},
+	}
+	cmd.Flags().StringVarP(&pluginsOpts.withPluginConfig, "plugin-config", "c", "", "Directory where user customized plugin manifests located.")
+	pluginsOpts.Format.BindFlags(cmd.Flags())
+	pluginsOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func pluginsVerifyCmd(common *option.Common) *cobra.Command {
+	pluginsOpts := newPluginsOptions(common)
+	cmd := &cobra.Command{
+		Use:          "verify [flags] [id...]",
+		Short:        "Verify plugin manifests and executable checksums",
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runPluginsVerify(pluginsOpts, args)
+		},
+	}
+	pluginsOpts.Format.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runPluginsList(opts *pluginsOptions) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, false)
+	if err != nil {
+		return err
+	}
+	ids, err := complytime.FindPluginIDs(appDir)
+	if err != nil {
+		return err
+	}
+	plugins := make([]pluginInfo, 0, len(ids))
+	for _, id := range ids {
+		info, _, _ := newPluginInfo(appDir, id)
+		plugins = append(plugins, info)
+	}
+
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, plugins)
+	}
+	if len(plugins) == 0 {
+		_, _ = fmt.Fprintf(opts.Out, "No plugins found in %s.\n", appDir.PluginManifestDir())
+		return nil
+	}
+	columns, rows := getPluginColumnsAndRows(plugins)
+	terminal.ShowPlainTable(opts.Out, columns, rows)
+	return nil
+}
+
+func runPluginsShow(opts *pluginsOptions, id string) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, false)
+	if err != nil {
+		return err
+	}
+	// Plugins failing the checksum verification are shown with the error.
+	info, manifest, err := newPluginInfo(appDir, id)
+	if err != nil && !errors.Is(err, complytime.ErrPluginChecksum) {
+		return err
+	}
+
+	selections := opts.complyTimeOpts.ToPluginOptions()
+	selections.UserConfigRoot = opts.withPluginConfig
+	// The profile is resolved from the assessment plan when the workspace has one.
+	plan, err := loadOptionalPlan(opts.complyTimeOpts, validation.NewSchemaValidator())
+	if err != nil {
+		return err
+	}
+	if plan != nil {
+		if frameworkID, err := complytime.PlanFrameworkID(plan); err == nil {
+			selections.Profile = frameworkID
+		}
+	}
+	info.Options, err = complytime.ResolvePluginOptions(manifest, selections, logger)
+	if err != nil {
+		return err
+	}
+
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, info)
+	}
+	showPluginInfo(opts.Out, info)
+	return nil
+}
+
+func runPluginsVerify(opts *pluginsOptions, ids []string) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, false)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		ids, err = complytime.FindPluginIDs(appDir)
+		if err != nil {
+			return err
+		}
+	}
+	plugins := make([]pluginInfo, 0, len(ids))
+	var failed int
+	for _, id := range ids {
+		info, _, _ := newPluginInfo(appDir, id)
+		if !info.Verified {
+			failed++
+		}
+		plugins = append(plugins, info)
+	}
+
+	if opts.Structured() {
+		if err := writeStructured(opts.Out, opts.OutputFormat, plugins); err != nil {
+			return err
+		}
+	} else {
+		for _, info := range plugins {
+			if info.Verified {
+				_, _ = fmt.Fprintf(opts.Out, "[ok]       %s: sha256 of %s matches the manifest\n", info.ID, info.ExecutablePath)
+			} else {
+				_, _ = fmt.Fprintf(opts.Out, "[fail]     %s: %s\n", info.ID, info.Error)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d plugin(s) failed verification", failed)
+	}
+	return nil
+}
+
+// newPluginInfo reads and verifies the plugin manifest for a given ID.
+// The manifest and the verification error are returned with the plugin information.
+func newPluginInfo(appDir complytime.ApplicationDirectory, id string) (pluginInfo, plugin.Manifest, error) {
+	manifest, err := complytime.VerifyPlugin(appDir, id)
+	info := pluginInfo{
+		ID:       id,
+		Verified: err == nil,
+	}
+	if err != nil {
+		info.Error = err.Error()
+	}
+	info.Description = manifest.Description
+	info.Version = manifest.Version
+	info.Types = manifest.Types
+	info.ExecutablePath = manifest.ExecutablePath
+	info.Checksum = manifest.Checksum
+	return info, manifest, err
+}
+
+// getPluginColumnsAndRows prepares columns and rows for the plugin list table.
+func getPluginColumnsAndRows(plugins []pluginInfo) ([]table.Column, []table.Row) {
+	var rows []table.Row
+	for _, info := range plugins {
+		status := "ok"
+		if !info.Verified {
+			status = "invalid"
+		}
+		rows = append(rows, table.Row{info.ID, info.Version, status, info.Description})
+	}
+	columns := []table.Column{
+		{Title: "Plugin ID", Width: 15},
+		{Title: "Version", Width: 10},
+		{Title: "Status", Width: 10},
+		{Title: "Description", Width: 40},
+	}
+	fitColumnWidths(columns, rows)
+	return columns, rows
+}
+
+// showPluginInfo prints the plugin metadata and its configuration options.
+func showPluginInfo(writer io.Writer, info pluginInfo) {
+	_, _ = fmt.Fprintf(writer, "ID:          %s\n", info.ID)
+	_, _ = fmt.Fprintf(writer, "Description: %s\n", info.Description)
+	_, _ = fmt.Fprintf(writer, "Version:     %s\n", info.Version)
+	_, _ = fmt.Fprintf(writer, "Types:       %s\n", strings.Join(info.Types, ", "))
+	_, _ = fmt.Fprintf(writer, "Executable:  %s\n", info.ExecutablePath)
+	if info.Verified {
+		_, _ = fmt.Fprintf(writer, "SHA256:      %s (verified)\n", info.Checksum)
+	} else {
+		_, _ = fmt.Fprintf(writer, "SHA256:      %s (%s)\n", info.Checksum, info.Error)
+	}
+	if len(info.Options) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(writer)
+	_, _ = fmt.Fprintln(writer, "Configuration:")
+	var rows []table.Row
+	for _, opt := range info.Options {
+		required := "no"
+		if opt.Required {
+			required = "yes"
+		}
+		rows = append(rows, table.Row{opt.Name, opt.Value, opt.Source, required})
+	}
+	columns := []table.Column{
+		{Title: "Option", Width: 15},
+		{Title: "Value", Width: 20},
+		{Title: "Source", Width: 20},
+		{Title: "Required", Width: 9},
+	}
+	fitColumnWidths(columns, rows)
+	terminal.ShowPlainTable(writer, columns, rows)
+}
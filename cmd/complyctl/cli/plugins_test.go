@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestGetPluginColumnsAndRows(t *testing.T) {
+	plugins := []pluginInfo{
+		{ID: "openscap", Version: "0.1.0", Description: "OpenSCAP plugin", Verified: true},
+		{ID: "broken", Error: "plugin manifest not found"},
+	}
+	columns, rows := getPluginColumnsAndRows(plugins)
+	require.Len(t, columns, 4)
+	require.Equal(t, []table.Row{
+		{"openscap", "0.1.0", "ok", "OpenSCAP plugin"},
+		{"broken", "", "invalid", ""},
+	}, rows)
+}
+
+func TestShowPluginInfo(t *testing.T) {
+	info := pluginInfo{
+		ID:             "openscap",
+		Description:    "OpenSCAP plugin",
+		Version:        "0.1.0",
+		Types:          []string{"pvp"},
+		ExecutablePath: "/usr/libexec/complytime/plugins/openscap-plugin",
+		Checksum:       "abc123",
+		Error:          "executable checksum mismatch",
+		Options: []complytime.PluginOption{
+			{Name: "workspace", Required: true, Value: "./complytime", Source: complytime.OptionSourceComplyctl},
+		},
+	}
+	out := bytes.NewBuffer(nil)
+	showPluginInfo(out, info)
+	require.Contains(t, out.String(), "Types:       pvp\n")
+	require.Contains(t, out.String(), "SHA256:      abc123 (executable checksum mismatch)\n")
+	require.Contains(t, out.String(), "Configuration:\n")
+	require.Contains(t, out.String(), "workspace")
+	require.Contains(t, out.String(), complytime.OptionSourceComplyctl)
+}
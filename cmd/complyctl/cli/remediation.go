@@ -0,0 +1,349 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// remediationOptions defines options for the "remediation" subcommands
+type remediationOptions struct {
+	*option.Common
+	option.Format
+	complyTimeOpts *option.ComplyTime
+
+	// resultsPath is the assessment results with the failed rules
+	resultsPath string
+
+	// remediationType is the type of the fixes to show or export
+	remediationType string
+
+	// output is the exported remediation location
+	output string
+}
+
+var remediationExample = `
+# List the failed rules of the latest results with the fixes generated by the plugins.
+complyctl remediation list
+
+# Show the bash and Ansible fixes of a failed rule.
+complyctl remediation show package_aide_installed
+
+# Export a script fixing only the failed rules to remediation-failed.sh in the workspace.
+complyctl remediation export
+
+# Export a playbook fixing only the failed rules to stdout.
+complyctl remediation export --type ansible --out -
+`
+
+// remediationLocations are the default exported remediation file names in the workspace by type.
+var remediationLocations = map[string]string{
+	complytime.RemediationBash:    "remediation-failed.sh",
+	complytime.RemediationAnsible: "remediation-failed.yml",
+}
+
+// remediationCmd creates a new cobra.Command for the "remediation" subcommand
+func remediationCmd(common *option.Common) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remediation",
+		Short:   "List, show and export the plugin remediations for failed rules",
+		Example: remediationExample,
+		Args:    cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		remediationListCmd(common),
+		remediationShowCmd(common),
+		remediationExportCmd(common),
+	)
+	return cmd
+}
+
+func newRemediationOptions(common *option.Common) *remediationOptions {
+	return &remediationOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+}
+
+// bindRemediationResultsFlag binds the flag of the assessment results and defaults it after parsing.
+func bindRemediationResultsFlag(cmd *cobra.Command, opts *remediationOptions) {
+	cmd.Flags().StringVarP(&opts.resultsPath, "results", "r", "", "path to the assessment results. Defaults to assessment-results.json in the workspace.")
+	cmd.PreRun = func(_ *cobra.Command, _ []string) {
+		if opts.resultsPath == "" {
+			opts.resultsPath = filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationJson)
+		}
+		opts.resultsPath = filepath.Clean(opts.resultsPath)
+	}
+}
+
+func remediationListCmd(common *option.Common) *cobra.Command {
+	remediationOpts := newRemediationOptions(common)
+	cmd := &cobra.Command{
+		Use:          "list [flags]",
+		Short:        "List the failed rules with the remediation artifacts fixing them",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runRemediationList(remediationOpts)
+		},
+	}
+	bindRemediationResultsFlag(cmd, remediationOpts)
+	remediationOpts.Format.BindFlags(cmd.Flags())
+	remediationOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func remediationShowCmd(common *option.Common) *cobra.Command {
+	remediationOpts := newRemediationOptions(common)
+	cmd := &cobra.Command{
+		Use:          "show [flags] rule-id",
+		Short:        "Show the fixes of a rule in the remediation artifacts",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runRemediationShow(remediationOpts, args[0])
+		},
+	}
+	bindRemediationResultsFlag(cmd, remediationOpts)
+	cmd.Flags().StringVarP(&remediationOpts.remediationType, "type", "t", "", "only show the fixes of the given type, one of: bash, ansible")
+	remediationOpts.Format.BindFlags(cmd.Flags())
+	remediationOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	_ = cmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions([]string{complytime.RemediationBash, complytime.RemediationAnsible}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func remediationExportCmd(common *option.Common) *cobra.Command {
+	remediationOpts := newRemediationOptions(common)
+	cmd := &cobra.Command{
+		Use:          "export [flags]",
+		Short:        "Export a script or playbook fixing only the failed rules",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runRemediationExport(remediationOpts)
+		},
+	}
+	bindRemediationResultsFlag(cmd, remediationOpts)
+	cmd.Flags().StringVarP(&remediationOpts.remediationType, "type", "t", complytime.RemediationBash, "remediation type, one of: bash, ansible")
+	cmd.Flags().StringVarP(&remediationOpts.output, "out", "o", "", "path to output file. Use '-' for stdout. Defaults to remediation-failed.<sh|yml> in the workspace.")
+	remediationOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	_ = cmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions([]string{complytime.RemediationBash, complytime.RemediationAnsible}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// validateRemediationType checks the type of fixes that can be shown or exported by rule.
+func validateRemediationType(remediationType string) error {
+	if _, ok := remediationLocations[remediationType]; !ok {
+		return fmt.Errorf("invalid remediation type %q: must be one of: %s, %s", remediationType, complytime.RemediationBash, complytime.RemediationAnsible)
+	}
+	return nil
+}
+
+// loadRemediationSummary summarizes the assessment results and reads the remediation
+// artifacts generated by the plugins in the workspace.
+func loadRemediationSummary(opts *remediationOptions, validator validation.Validator) (complytime.ResultsSummary, []complytime.RemediationArtifact, error) {
+	plan, err := loadOptionalPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return complytime.ResultsSummary{}, nil, err
+	}
+	assessmentResults, err := complytime.ReadAssessmentResults(opts.resultsPath, validator)
+	if err != nil {
+		return complytime.ResultsSummary{}, nil, err
+	}
+	artifacts, err := complytime.FindRemediationArtifacts(opts.complyTimeOpts.UserWorkspace)
+	if err != nil {
+		return complytime.ResultsSummary{}, nil, err
+	}
+	if len(artifacts) == 0 {
+		logger.Warn(fmt.Sprintf("No remediation artifacts found in %s. Plugins generate them with the generate command.", filepath.Join(opts.complyTimeOpts.UserWorkspace, "*", complytime.RemediationDir)))
+	}
+	return complytime.SummarizeResults(assessmentResults, plan), artifacts, nil
+}
+
+// remediationList is the structured output of the "remediation list" subcommand.
+type remediationList struct {
+	Artifacts []complytime.RemediationArtifact `json:"artifacts" yaml:"artifacts"`
+	Rules     []complytime.RuleRemediation     `json:"rules" yaml:"rules"`
+}
+
+func runRemediationList(opts *remediationOptions) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	summary, artifacts, err := loadRemediationSummary(opts, validation.NewSchemaValidator())
+	if err != nil {
+		return err
+	}
+	remediations := complytime.FailedRuleRemediations(summary, artifacts)
+	// Snippets are only shown for a single rule.
+	for i := range remediations {
+		for j := range remediations[i].Fixes {
+			remediations[i].Fixes[j].Snippet = ""
+		}
+	}
+
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, remediationList{Artifacts: artifacts, Rules: remediations})
+	}
+	showRemediationList(opts.Out, artifacts, remediations)
+	return nil
+}
+
+func runRemediationShow(opts *remediationOptions, ruleID string) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	if opts.remediationType != "" {
+		if err := validateRemediationType(opts.remediationType); err != nil {
+			return err
+		}
+	}
+	summary, artifacts, err := loadRemediationSummary(opts, validation.NewSchemaValidator())
+	if err != nil {
+		return err
+	}
+	rule, found := summary.Rule(ruleID)
+	if !found {
+		return fmt.Errorf("rule %s not found in %s", ruleID, opts.resultsPath)
+	}
+	remediation := complytime.FindRuleRemediation(rule, artifacts)
+	if opts.remediationType != "" {
+		var fixes []complytime.RemediationFix
+		for _, fix := range remediation.Fixes {
+			if fix.Type == opts.remediationType {
+				fixes = append(fixes, fix)
+			}
+		}
+		remediation.Fixes = fixes
+	}
+
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, remediation)
+	}
+	showRuleRemediation(opts.Out, remediation)
+	return nil
+}
+
+func runRemediationExport(opts *remediationOptions) error {
+	if err := validateRemediationType(opts.remediationType); err != nil {
+		return err
+	}
+	summary, artifacts, err := loadRemediationSummary(opts, validation.NewSchemaValidator())
+	if err != nil {
+		return err
+	}
+
+	var failed []complytime.RuleResult
+	for _, remediation := range complytime.FailedRuleRemediations(summary, artifacts) {
+		rule, _ := summary.Rule(remediation.RuleID)
+		failed = append(failed, rule)
+		if !hasFixOfType(remediation, opts.remediationType) {
+			logger.Warn(fmt.Sprintf("No %s fix found for the failed rule %s", opts.remediationType, remediation.RuleID))
+		}
+	}
+	if len(failed) == 0 {
+		logger.Info(fmt.Sprintf("No failed rules in %s.", opts.resultsPath))
+		return nil
+	}
+	exported, count, err := complytime.ExportRemediation(artifacts, opts.remediationType, failed)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("no %s fixes found for the %d failed rule(s)", opts.remediationType, len(failed))
+	}
+
+	output := opts.output
+	if output == "" {
+		output = filepath.Join(opts.complyTimeOpts.UserWorkspace, remediationLocations[opts.remediationType])
+	}
+	if output == "-" {
+		_, err = opts.Out.Write(exported)
+		return err
+	}
+	if err := os.WriteFile(filepath.Clean(output), exported, 0600); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("The %s remediation of %d failed rule(s) was successfully written to %s.", opts.remediationType, len(failed), output))
+	return nil
+}
+
+func hasFixOfType(remediation complytime.RuleRemediation, remediationType string) bool {
+	for _, fix := range remediation.Fixes {
+		if fix.Type == remediationType {
+			return true
+		}
+	}
+	return false
+}
+
+// showRemediationList prints the remediation artifacts and a plain table of the failed rules
+// with the types of the fixes found for each.
+func showRemediationList(writer io.Writer, artifacts []complytime.RemediationArtifact, remediations []complytime.RuleRemediation) {
+	for _, artifact := range artifacts {
+		_, _ = fmt.Fprintln(writer, renderKeyValuePair(fmt.Sprintf("%s %s", artifact.Plugin, artifact.Type), artifact.Path))
+	}
+	if len(remediations) == 0 {
+		_, _ = fmt.Fprintln(writer, "No failed rules.")
+		return
+	}
+	if len(artifacts) > 0 {
+		_, _ = fmt.Fprintln(writer)
+	}
+	columns, rows := getRemediationColumnsAndRows(remediations)
+	terminal.ShowPlainTable(writer, columns, rows)
+}
+
+// getRemediationColumnsAndRows prepares columns and rows for the table of failed rules.
+func getRemediationColumnsAndRows(remediations []complytime.RuleRemediation) ([]table.Column, []table.Row) {
+	var rows []table.Row
+	for _, remediation := range remediations {
+		var types []string
+		for _, fix := range remediation.Fixes {
+			types = append(types, fix.Type)
+		}
+		fixes := strings.Join(types, ", ")
+		if fixes == "" {
+			fixes = "none"
+		}
+		rows = append(rows, table.Row{remediation.RuleID, strings.Join(remediation.Controls, ", "), fixes})
+	}
+	columns := []table.Column{
+		{Title: "Rule ID", Width: 30},
+		{Title: "Controls", Width: colWidthControlID},
+		{Title: "Fixes", Width: 14},
+	}
+	fitColumnWidths(columns, rows)
+	return columns, rows
+}
+
+// showRuleRemediation prints a rule with the fix snippets of each remediation artifact.
+func showRuleRemediation(writer io.Writer, remediation complytime.RuleRemediation) {
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Rule ID", remediation.RuleID))
+	if remediation.Title != "" {
+		_, _ = fmt.Fprintln(writer, renderKeyValuePair("Title", remediation.Title))
+	}
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Status", remediation.Status))
+	_, _ = fmt.Fprintln(writer, renderKeyValuePair("Controls", strings.Join(remediation.Controls, ", ")))
+	if len(remediation.Fixes) == 0 {
+		_, _ = fmt.Fprintln(writer, "No fixes found in the remediation artifacts.")
+		return
+	}
+	for _, fix := range remediation.Fixes {
+		_, _ = fmt.Fprintln(writer)
+		_, _ = fmt.Fprintln(writer, renderKeyValuePair(fmt.Sprintf("%s fix", fix.Type), fix.Path))
+		_, _ = fmt.Fprint(writer, fix.Snippet)
+	}
+}
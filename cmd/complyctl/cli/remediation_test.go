@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+const testRemediationScript = `#!/usr/bin/env bash
+###############################################################################
+# BEGIN fix (1 / 1) for 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'
+###############################################################################
+systemctl enable --now auditd.service
+# END fix for 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'
+`
+
+func TestLoadRemediationSummary(t *testing.T) {
+	workspace := t.TempDir()
+	remediationDir := filepath.Join(workspace, "openscap", complytime.RemediationDir)
+	require.NoError(t, os.MkdirAll(remediationDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(remediationDir, "remediation-script.sh"), []byte(testRemediationScript), 0600))
+
+	var observations []oscalTypes.Observation
+	for _, rule := range []struct{ id, result string }{{"package_aide_installed", "pass"}, {"service_auditd_enabled", "fail"}} {
+		observations = append(observations, oscalTypes.Observation{
+			UUID:     "obs-" + rule.id,
+			Title:    "xccdf_org.ssgproject.content_rule_" + rule.id,
+			Props:    &[]oscalTypes.Property{{Name: extensions.AssessmentRuleIdProp, Value: rule.id, Ns: extensions.TrestleNameSpace}},
+			Subjects: &[]oscalTypes.SubjectReference{{Title: "Host localhost", Props: &[]oscalTypes.Property{{Name: "result", Value: rule.result}}}},
+		})
+	}
+	resultsPath := filepath.Join(workspace, assessmentResultsLocationJson)
+	require.NoError(t, complytime.WriteAssessmentResults(&oscalTypes.AssessmentResults{
+		UUID:    "7c5e9b5a-4f0e-4c3f-9c1d-2f7b8e6a1d01",
+		Results: []oscalTypes.Result{{UUID: "7c5e9b5a-4f0e-4c3f-9c1d-2f7b8e6a1d02", Observations: &observations}},
+	}, resultsPath))
+
+	opts := newRemediationOptions(&option.Common{})
+	opts.complyTimeOpts.UserWorkspace = workspace
+	opts.resultsPath = resultsPath
+	summary, artifacts, err := loadRemediationSummary(opts, validation.NoopValidator{})
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+	require.Equal(t, complytime.RemediationBash, artifacts[0].Type)
+
+	remediations := complytime.FailedRuleRemediations(summary, artifacts)
+	require.Len(t, remediations, 1)
+	out := bytes.NewBuffer(nil)
+	showRemediationList(out, artifacts, remediations)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, []string{"openscap", "bash", ":", filepath.Join(remediationDir, "remediation-script.sh")}, strings.Fields(lines[0]))
+	require.Equal(t, []string{"service_auditd_enabled", "bash"}, strings.Fields(lines[3]))
+
+	out.Reset()
+	showRuleRemediation(out, remediations[0])
+	require.Contains(t, out.String(), "fail\n")
+	require.Contains(t, out.String(), filepath.Join(remediationDir, "remediation-script.sh")+"\n###")
+	require.True(t, strings.HasSuffix(out.String(), "# END fix for 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'\n"))
+}
+
+func TestValidateRemediationType(t *testing.T) {
+	require.NoError(t, validateRemediationType(complytime.RemediationBash))
+	require.NoError(t, validateRemediationType(complytime.RemediationAnsible))
+	require.EqualError(t, validateRemediationType(complytime.RemediationBlueprint), `invalid remediation type "blueprint": must be one of: bash, ansible`)
+}
@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/version"
+)
+
+// reportOptions defines options for the "report" subcommand
+type reportOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+
+	// resultsPath is the assessment results to render
+	resultsPath string
+
+	// output is the report location
+	output string
+
+	// format is the report format
+	format string
+}
+
+const (
+	reportFormatMarkdown = "markdown"
+	reportFormatHTML     = "html"
+	reportFormatSARIF    = "sarif"
+	reportFormatJUnit    = "junit"
+)
+
+// assessmentResultsLocationSarif is the default SARIF report location in the workspace.
+const assessmentResultsLocationSarif = "assessment-results.sarif"
+
+// reportLocations are the default report file names in the workspace by format.
+var reportLocations = map[string]string{
+	reportFormatMarkdown: assessmentResultsLocationMd,
+	reportFormatHTML:     assessmentResultsLocationHtml,
+	reportFormatSARIF:    assessmentResultsLocationSarif,
+	reportFormatJUnit:    assessmentResultsLocationJUnit,
+}
+
+var reportExample = `
+# Render the assessment results in the workspace to assessment-results.md in the workspace.
+complyctl report
+
+# Render archived assessment results to a given file.
+complyctl report --results archive/assessment-results.json --out archive/assessment-results.md
+
+# Print the report to stdout.
+complyctl report --out -
+
+# Render a self-contained HTML report to assessment-results.html in the workspace.
+complyctl report --format html
+
+# Export the results as SARIF 2.1.0 for code-scanning dashboards.
+complyctl report --format sarif --out results.sarif
+
+# Export the results as JUnit XML for CI systems.
+complyctl report --format junit
+`
+
+// reportCmd creates a new cobra.Command for the "report" subcommand
+func reportCmd(common *option.Common) *cobra.Command {
+	reportOpts := &reportOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "report [flags]",
+		Short:        "Render a report from existing assessment results",
+		Example:      reportExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRun: func(_ *cobra.Command, _ []string) {
+			completeReport(reportOpts)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := validateReport(reportOpts); err != nil {
+				return err
+			}
+			return runReport(reportOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&reportOpts.resultsPath, "results", "r", "", "path to the assessment results. Defaults to assessment-results.json in the workspace.")
+	cmd.Flags().StringVarP(&reportOpts.output, "out", "o", "", "path to output file. Use '-' for stdout. Defaults to assessment-results.<md|html|sarif|junit.xml> in the workspace.")
+	cmd.Flags().StringVarP(&reportOpts.format, "format", "f", reportFormatMarkdown, "report format, one of: markdown, html, sarif, junit")
+	reportOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func completeReport(opts *reportOptions) {
+	if opts.resultsPath == "" {
+		opts.resultsPath = filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationJson)
+	}
+	opts.resultsPath = filepath.Clean(opts.resultsPath)
+	if opts.output == "" {
+		opts.output = filepath.Join(opts.complyTimeOpts.UserWorkspace, reportLocations[opts.format])
+	}
+	if opts.output != "-" {
+		opts.output = filepath.Clean(opts.output)
+	}
+}
+
+func validateReport(opts *reportOptions) error {
+	if _, ok := reportLocations[opts.format]; !ok {
+		return fmt.Errorf("invalid report format %q: must be one of: %s, %s, %s, %s", opts.format, reportFormatMarkdown, reportFormatHTML, reportFormatSARIF, reportFormatJUnit)
+	}
+	return nil
+}
+
+func runReport(opts *reportOptions) error {
+	validator := validation.NewSchemaValidator()
+	ap, _, err := loadPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+
+	assessmentResults, err := complytime.ReadAssessmentResults(opts.resultsPath, validator)
+	if err != nil {
+		return err
+	}
+
+	// Rendering only reads installed content, so the application directory is not created.
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, false)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+
+	var report []byte
+	switch opts.format {
+	case reportFormatHTML:
+		report, err = renderHTMLReport(appDir, assessmentResults, ap, validator)
+	case reportFormatSARIF:
+		report, err = complytime.GenerateSARIF(assessmentResults, ap, version.Version())
+	case reportFormatJUnit:
+		report, err = renderJUnitReport(assessmentResults, ap)
+	default:
+		report, err = renderMarkdownReport(appDir, assessmentResults, ap, validator)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.output == "-" {
+		_, err = opts.Out.Write(report)
+		return err
+	}
+	if err := os.WriteFile(opts.output, report, 0600); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("The assessment results in %s were successfully written to %v.", opts.format, opts.output))
+	return nil
+}
+
+// renderMarkdownReport renders the assessment results as a markdown posture report.
+// The catalog is resolved from the framework recorded in the assessment plan. Plans with
+// several frameworks get a posture report for each framework, with the findings of its controls.
+func renderMarkdownReport(appDir complytime.ApplicationDirectory, assessmentResults *oscalTypes.AssessmentResults, ap *oscalTypes.AssessmentPlan, validator validation.Validator) ([]byte, error) {
+	frameworkIDs, err := complytime.PlanFrameworkIDs(ap)
+	if err != nil {
+		return nil, err
+	}
+	var reports [][]byte
+	for _, frameworkID := range frameworkIDs {
+		catalog, err := complytime.LoadFrameworkCatalog(appDir, frameworkID, validator)
+		if err != nil {
+			return nil, err
+		}
+		if len(frameworkIDs) == 1 {
+			posture := framework.NewPosture(assessmentResults, catalog, ap, logger)
+			return posture.Generate(assessmentResultsLocationMd)
+		}
+		frameworkResults := complytime.FrameworkAssessmentResults(assessmentResults, ap, frameworkID)
+		posture := framework.NewPosture(frameworkResults, catalog, ap, logger)
+		report, err := posture.Generate(assessmentResultsLocationMd)
+		if err != nil {
+			return nil, fmt.Errorf("error generating the posture report for %s: %w", frameworkID, err)
+		}
+		reports = append(reports, append([]byte(fmt.Sprintf("# Framework: %s\n\n", frameworkID)), report...))
+	}
+	return bytes.Join(reports, []byte("\n")), nil
+}
+
+// renderHTMLReport renders the assessment results as a self-contained HTML posture report.
+// The catalogs are resolved from the frameworks recorded in the assessment plan. Plans with
+// several frameworks get the posture of each framework, with the control titles of its catalog.
+func renderHTMLReport(appDir complytime.ApplicationDirectory, assessmentResults *oscalTypes.AssessmentResults, ap *oscalTypes.AssessmentPlan, validator validation.Validator) ([]byte, error) {
+	frameworkIDs, err := complytime.PlanFrameworkIDs(ap)
+	if err != nil {
+		return nil, err
+	}
+	catalogs := make([]complytime.FrameworkCatalog, 0, len(frameworkIDs))
+	for _, frameworkID := range frameworkIDs {
+		catalog, err := complytime.LoadFrameworkCatalog(appDir, frameworkID, validator)
+		if err != nil {
+			return nil, err
+		}
+		catalogs = append(catalogs, complytime.FrameworkCatalog{FrameworkID: frameworkID, Catalog: catalog})
+	}
+	return complytime.GenerateHTMLReport(assessmentResults, ap, catalogs...)
+}
+
+// renderJUnitReport exports the assessment results as JUnit XML with a test suite per control.
+// The test suites are named after the frameworks recorded in the assessment plan.
+func renderJUnitReport(assessmentResults *oscalTypes.AssessmentResults, ap *oscalTypes.AssessmentPlan) ([]byte, error) {
+	frameworkIDs, err := complytime.PlanFrameworkIDs(ap)
+	if err != nil {
+		return nil, err
+	}
+	return complytime.GenerateJUnit(assessmentResults, ap, strings.Join(frameworkIDs, ","))
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+)
+
+func TestCompleteReport(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        reportOptions
+		wantResults string
+		wantOutput  string
+	}{
+		{
+			name:        "Valid/WorkspaceDefaults",
+			opts:        reportOptions{format: reportFormatMarkdown},
+			wantResults: "complytime/assessment-results.json",
+			wantOutput:  "complytime/assessment-results.md",
+		},
+		{
+			name: "Valid/CustomPaths",
+			opts: reportOptions{
+				resultsPath: "archive//assessment-results.json",
+				output:      "./archive/report.md",
+			},
+			wantResults: "archive/assessment-results.json",
+			wantOutput:  "archive/report.md",
+		},
+		{
+			name: "Valid/HTMLDefaults",
+			opts: reportOptions{
+				format: reportFormatHTML,
+			},
+			wantResults: "complytime/assessment-results.json",
+			wantOutput:  "complytime/assessment-results.html",
+		},
+		{
+			name: "Valid/Stdout",
+			opts: reportOptions{
+				output: "-",
+			},
+			wantResults: "complytime/assessment-results.json",
+			wantOutput:  "-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.complyTimeOpts = &option.ComplyTime{UserWorkspace: "complytime"}
+			completeReport(&tt.opts)
+			require.Equal(t, tt.wantResults, tt.opts.resultsPath)
+			require.Equal(t, tt.wantOutput, tt.opts.output)
+		})
+	}
+}
+
+func TestValidateReport(t *testing.T) {
+	require.NoError(t, validateReport(&reportOptions{format: reportFormatMarkdown}))
+	require.NoError(t, validateReport(&reportOptions{format: reportFormatHTML}))
+	require.NoError(t, validateReport(&reportOptions{format: reportFormatSARIF}))
+	require.NoError(t, validateReport(&reportOptions{format: reportFormatJUnit}))
+	require.EqualError(t, validateReport(&reportOptions{format: "pdf"}), "invalid report format \"pdf\": must be one of: markdown, html, sarif, junit")
+}
@@ -0,0 +1,381 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// resultsOptions defines options for the "results" subcommands
+type resultsOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+	resultsPath    string
+	status         string
+}
+
+var resultsExample = `
+# Browse the controls and rules of assessment-results.json in the workspace.
+complyctl results browse
+
+# Start with the failing controls only.
+complyctl results browse --status fail
+
+# Browse previous assessment results.
+complyctl results browse --results previous-assessment-results.json
+`
+
+// resultsStatusFilters are the status filters cycled through in the results browser.
+var resultsStatusFilters = []string{"", complytime.StatusFail, complytime.StatusError, complytime.StatusPass, complytime.StatusNotAssessed}
+
+// resultsCmd creates a new cobra.Command for the "results" subcommand
+func resultsCmd(common *option.Common) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "results",
+		Short:   "Explore assessment results",
+		Example: resultsExample,
+		Args:    cobra.NoArgs,
+	}
+	cmd.AddCommand(resultsBrowseCmd(common))
+	return cmd
+}
+
+func resultsBrowseCmd(common *option.Common) *cobra.Command {
+	resultsOpts := &resultsOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "browse [flags]",
+		Short:        "Browse the controls and rules of assessment results in the terminal",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRun: func(_ *cobra.Command, _ []string) {
+			if resultsOpts.resultsPath == "" {
+				resultsOpts.resultsPath = filepath.Join(resultsOpts.complyTimeOpts.UserWorkspace, assessmentResultsLocationJson)
+			}
+			resultsOpts.resultsPath = filepath.Clean(resultsOpts.resultsPath)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := validateResultsStatus(resultsOpts.status); err != nil {
+				return err
+			}
+			return runResultsBrowse(resultsOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&resultsOpts.resultsPath, "results", "r", "", "path to the assessment results. Defaults to assessment-results.json in the workspace.")
+	cmd.Flags().StringVarP(&resultsOpts.status, "status", "s", "", "only list the controls with the given status, one of: pass, fail, error, not-assessed")
+	resultsOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	_ = cmd.RegisterFlagCompletionFunc("status", cobra.FixedCompletions(resultsStatusFilters[1:], cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func validateResultsStatus(status string) error {
+	for _, filter := range resultsStatusFilters {
+		if status == filter {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid status %q: must be one of: %s", status, strings.Join(resultsStatusFilters[1:], ", "))
+}
+
+func runResultsBrowse(opts *resultsOptions) error {
+	validator := validation.NewSchemaValidator()
+	plan, err := loadOptionalPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+	assessmentResults, err := complytime.ReadAssessmentResults(opts.resultsPath, validator)
+	if err != nil {
+		return err
+	}
+
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, false)
+	if err != nil {
+		return err
+	}
+
+	model := newResultsModel(complytime.SummarizeResults(assessmentResults, plan), loadControlTitles(appDir, plan, validator), opts.status)
+	if _, err := tea.NewProgram(model, tea.WithAltScreen(), tea.WithOutput(opts.Out)).Run(); err != nil {
+		return fmt.Errorf("failed to browse results: %w", err)
+	}
+	return nil
+}
+
+// loadControlTitles returns the control titles of the framework catalogs recorded in the plan.
+// Titles are optional, so no titles are returned for catalogs that cannot be loaded. A control
+// in the catalogs of several frameworks takes its title from the first framework.
+func loadControlTitles(appDir complytime.ApplicationDirectory, plan *oscalTypes.AssessmentPlan, validator validation.Validator) map[string]string {
+	if plan == nil {
+		return nil
+	}
+	frameworkIDs, err := complytime.PlanFrameworkIDs(plan)
+	if err != nil {
+		return nil
+	}
+	var titles map[string]string
+	for _, frameworkID := range frameworkIDs {
+		catalog, err := complytime.LoadFrameworkCatalog(appDir, frameworkID, validator)
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Control titles not available for %s: %v", frameworkID, err))
+			continue
+		}
+		if titles == nil {
+			titles = make(map[string]string)
+		}
+		for controlID, title := range complytime.CatalogControlTitles(catalog) {
+			if _, found := titles[controlID]; !found {
+				titles[controlID] = title
+			}
+		}
+	}
+	return titles
+}
+
+const (
+	defaultResultsTableHeight = 20
+	// resultsViewLines is the number of lines around the table or details: header, borders and help.
+	resultsViewLines = 6
+)
+
+var _ tea.Model = (*resultsModel)(nil)
+
+// resultsModel is a Bubble Tea model listing the controls of assessment results
+// and showing the rule results of a selected control.
+type resultsModel struct {
+	summary  complytime.ResultsSummary
+	titles   map[string]string
+	filter   string
+	controls []complytime.ControlResult
+	table    table.Model
+	// details shows the rule results of the selected control when set.
+	details *viewport.Model
+	control complytime.ControlResult
+	width   int
+	height  int
+}
+
+// newResultsModel returns a resultsModel listing the controls with the given status, or all controls.
+func newResultsModel(summary complytime.ResultsSummary, titles map[string]string, filter string) *resultsModel {
+	m := &resultsModel{
+		summary: summary,
+		titles:  titles,
+		filter:  filter,
+		width:   90,
+		height:  defaultResultsTableHeight + resultsViewLines,
+	}
+	m.table = table.New(
+		table.WithFocused(true),
+		table.WithHeight(defaultResultsTableHeight),
+	)
+	m.table.SetStyles(table.Styles{
+		Header:   tableHeaderStyle,
+		Cell:     tableCellStyle,
+		Selected: table.DefaultStyles().Selected,
+	})
+	m.applyFilter()
+	return m
+}
+
+func (m *resultsModel) Init() tea.Cmd { return nil }
+
+func (m *resultsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.table.SetHeight(max(m.height-m.viewLines(), 3))
+		if m.details != nil {
+			m.details.Width = m.width
+			m.details.Height = max(m.height-resultsViewLines, 3)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "q", "esc":
+			if m.details == nil {
+				return m, tea.Quit
+			}
+			m.details = nil
+			return m, nil
+		case "enter":
+			if m.details == nil && len(m.controls) > 0 {
+				m.showControl(m.controls[m.table.Cursor()])
+			}
+			return m, nil
+		case "f":
+			if m.details == nil {
+				m.nextFilter()
+			}
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	if m.details != nil {
+		*m.details, cmd = m.details.Update(msg)
+	} else {
+		m.table, cmd = m.table.Update(msg)
+	}
+	return m, cmd
+}
+
+// viewLines returns the number of lines around the table, including a line for each framework.
+func (m *resultsModel) viewLines() int {
+	return resultsViewLines + len(m.summary.Frameworks)
+}
+
+// nextFilter cycles through the status filters.
+func (m *resultsModel) nextFilter() {
+	for i, filter := range resultsStatusFilters {
+		if filter == m.filter {
+			m.filter = resultsStatusFilters[(i+1)%len(resultsStatusFilters)]
+			break
+		}
+	}
+	m.applyFilter()
+}
+
+// applyFilter lists the controls matching the status filter.
+func (m *resultsModel) applyFilter() {
+	m.controls = nil
+	for _, control := range m.summary.Controls {
+		if m.filter == "" || control.Status == m.filter {
+			m.controls = append(m.controls, control)
+		}
+	}
+	columns, rows := getResultsControlColumnsAndRows(m.controls, m.titles)
+	m.table.SetRows(nil)
+	m.table.SetColumns(columns)
+	m.table.SetRows(rows)
+	m.table.SetCursor(0)
+}
+
+// showControl shows the rule results of a control in a scrollable view.
+func (m *resultsModel) showControl(control complytime.ControlResult) {
+	details := viewport.New(m.width, max(m.height-resultsViewLines, 3))
+	details.SetContent(renderControlResults(control, m.summary))
+	m.details = &details
+	m.control = control
+}
+
+func (m *resultsModel) View() string {
+	if m.details != nil {
+		header := renderKeyValuePair("Control", m.control.ControlID)
+		if title := m.titles[m.control.ControlID]; title != "" {
+			header += " " + valueStyle.Render(title)
+		}
+		header += "\n" + renderKeyValuePair("Status", m.control.Status) + " " + formatStatusCounts(m.control.Counts)
+		return header + "\n" + m.details.View() + "\n" + "↑/↓: scroll • esc: back to controls\n"
+	}
+
+	filter := m.filter
+	if filter == "" {
+		filter = "all"
+	}
+	header := fmt.Sprintf("%s %s\n", renderKeyValuePair("Controls", fmt.Sprintf("%d of %d", len(m.controls), len(m.summary.Controls))),
+		formatStatusCounts(m.summary.ControlCounts))
+	for _, framework := range m.summary.Frameworks {
+		header += fmt.Sprintf("%s %s\n", renderKeyValuePair(framework.FrameworkID, fmt.Sprintf("%d controls", len(framework.Controls))),
+			formatStatusCounts(framework.ControlCounts))
+	}
+	header += renderKeyValuePair("Status filter", filter)
+	help := "enter: show rule results • f: change status filter • q: quit"
+	if len(m.controls) == 0 {
+		help = "No controls with this status. " + help
+	}
+	return header + "\n" + lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Render(m.table.View()) + "\n" + help + "\n"
+}
+
+// formatStatusCounts returns the counts of each status in parentheses.
+func formatStatusCounts(counts complytime.StatusCounts) string {
+	return fmt.Sprintf("(pass %d, fail %d, error %d, not assessed %d)", counts.Pass, counts.Fail, counts.Error, counts.NotAssessed)
+}
+
+// formatFrameworkCounts returns the rule and control counts of a framework.
+func formatFrameworkCounts(counts complytime.FrameworkCounts) string {
+	return fmt.Sprintf("rules %s, controls %s", formatStatusCounts(counts.RuleCounts), formatStatusCounts(counts.ControlCounts))
+}
+
+// getResultsControlColumnsAndRows prepares columns and rows for the table of control results.
+func getResultsControlColumnsAndRows(controls []complytime.ControlResult, titles map[string]string) ([]table.Column, []table.Row) {
+	var rows []table.Row
+	for _, control := range controls {
+		rows = append(rows, table.Row{
+			control.ControlID,
+			titles[control.ControlID],
+			control.Status,
+			fmt.Sprint(control.Counts.Pass),
+			fmt.Sprint(control.Counts.Fail),
+			fmt.Sprint(control.Counts.Error),
+			fmt.Sprint(control.Counts.NotAssessed),
+		})
+	}
+	columns := []table.Column{
+		{Title: "Control ID", Width: colWidthControlID},
+		{Title: "Control Title", Width: colWidthControlTitle},
+		{Title: "Status", Width: 13},
+		{Title: "Pass", Width: 5},
+		{Title: "Fail", Width: 5},
+		{Title: "Error", Width: 6},
+		{Title: "Not Assessed", Width: 13},
+	}
+	fitColumnWidths(columns, rows)
+	return columns, rows
+}
+
+// renderControlResults renders the observations of each rule of a control: the checks,
+// the result and reason for each subject and the evidence links.
+func renderControlResults(control complytime.ControlResult, summary complytime.ResultsSummary) string {
+	var b strings.Builder
+	for i, ruleID := range control.Rules {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		rule, _ := summary.Rule(ruleID)
+		fmt.Fprintf(&b, "%s %s\n", renderKeyValuePair("Rule", rule.RuleID), rule.Status)
+		if rule.Title != "" && rule.Title != rule.RuleID {
+			fmt.Fprintf(&b, "  %s\n", rule.Title)
+		}
+		if len(rule.CheckIDs) > 0 {
+			fmt.Fprintf(&b, "  %s\n", renderKeyValuePair("Checks", strings.Join(rule.CheckIDs, ", ")))
+		}
+		if len(rule.Subjects) == 0 {
+			b.WriteString("  No observations recorded.\n")
+		}
+		for _, subject := range rule.Subjects {
+			name := subject.Title
+			if subject.ResourceID != "" && subject.ResourceID != subject.Title {
+				name = fmt.Sprintf("%s (%s)", subject.Title, subject.ResourceID)
+			}
+			fmt.Fprintf(&b, "  %s %s\n", renderKeyValuePair("Subject", name), subject.Result)
+			if subject.Reason != "" {
+				fmt.Fprintf(&b, "    %s\n", renderKeyValuePair("Reason", subject.Reason))
+			}
+		}
+		for _, evidence := range rule.Evidence {
+			link := evidence.Href
+			if evidence.Description != "" {
+				link += " " + evidence.Description
+			}
+			fmt.Fprintf(&b, "  %s\n", renderKeyValuePair("Evidence", link))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+var testResultsSummary = complytime.ResultsSummary{
+	Rules: []complytime.RuleResult{
+		{
+			RuleID:   "rule-1",
+			Title:    "Check one",
+			CheckIDs: []string{"check-1"},
+			Status:   complytime.StatusFail,
+			Controls: []string{"control-1"},
+			Subjects: []complytime.SubjectResult{
+				{Title: "host-1", ResourceID: "id-1", Result: complytime.StatusFail, Reason: "file is missing"},
+			},
+			Evidence: []complytime.Evidence{{Href: "file:///tmp/arf.xml", Description: "ARF results"}},
+		},
+		{RuleID: "rule-2", Status: complytime.StatusNotAssessed, Controls: []string{"control-1", "control-2"}},
+	},
+	Controls: []complytime.ControlResult{
+		{ControlID: "control-1", Status: complytime.StatusFail, Counts: complytime.StatusCounts{Fail: 1, NotAssessed: 1}, Rules: []string{"rule-1", "rule-2"}},
+		{ControlID: "control-2", Status: complytime.StatusNotAssessed, Counts: complytime.StatusCounts{NotAssessed: 1}, Rules: []string{"rule-2"}},
+	},
+	ControlCounts: complytime.StatusCounts{Fail: 1, NotAssessed: 1},
+}
+
+func TestValidateResultsStatus(t *testing.T) {
+	require.NoError(t, validateResultsStatus(""))
+	require.NoError(t, validateResultsStatus(complytime.StatusNotAssessed))
+	require.EqualError(t, validateResultsStatus("failed"), "invalid status \"failed\": must be one of: fail, error, pass, not-assessed")
+}
+
+func TestRenderControlResults(t *testing.T) {
+	content := renderControlResults(testResultsSummary.Controls[0], testResultsSummary)
+	for _, want := range []string{"rule-1", "Check one", "check-1", "host-1 (id-1)", "file is missing", "file:///tmp/arf.xml ARF results", "rule-2", "No observations recorded."} {
+		require.Contains(t, content, want)
+	}
+}
+
+func TestResultsModel(t *testing.T) {
+	model := newResultsModel(testResultsSummary, map[string]string{"control-1": "First control"}, "")
+	require.Len(t, model.controls, 2)
+	view := model.View()
+	require.Contains(t, view, "First control")
+	require.Contains(t, view, "2 of 2")
+
+	// The filters cycle from all to fail, error, pass and not-assessed.
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	require.Equal(t, complytime.StatusFail, model.filter)
+	require.Len(t, model.controls, 1)
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	require.Empty(t, model.controls)
+	require.Contains(t, model.View(), "No controls with this status.")
+
+	model = newResultsModel(testResultsSummary, nil, complytime.StatusNotAssessed)
+	require.Len(t, model.controls, 1)
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, model.details)
+	require.Contains(t, model.View(), "control-2")
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.Nil(t, model.details)
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	require.NotNil(t, cmd)
+}
@@ -3,7 +3,9 @@
 package cli
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/cobra"
@@ -14,6 +16,9 @@ import (
 
 var logger hclog.Logger
 
+// loadedConfig holds the settings loaded from the configuration files for the running command.
+var loadedConfig option.Config
+
 func init() {
 	logger = log.NewLogger(os.Stdout)
 }
@@ -28,6 +33,44 @@ func enableDebug(opts *option.Common) {
 	}
 }
 
+// applyConfig loads the configuration files and uses their values as defaults
+// for the flags of cmd that are not set on the command line. The loaded configuration
+// is returned for the settings without flags.
+func applyConfig(cmd *cobra.Command) (option.Config, error) {
+	var workspace string
+	if flag := cmd.Flags().Lookup("workspace"); flag != nil && flag.Changed {
+		workspace = flag.Value.String()
+	}
+	config, err := option.LoadConfig(workspace)
+	if err != nil {
+		return option.Config{}, err
+	}
+	loadedConfig = config
+
+	defaults := map[string]string{
+		"workspace":     config.Workspace,
+		"plugin-config": config.PluginConfig,
+		"output":        config.Output,
+	}
+	if config.Debug != nil {
+		defaults["debug"] = strconv.FormatBool(*config.Debug)
+	}
+	for name, value := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if value == "" || flag == nil || flag.Changed {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return option.Config{}, fmt.Errorf("invalid configuration value %q for %s: %w", value, name, err)
+		}
+	}
+
+	if config.LogFormat == option.LogFormatJSON {
+		logger = log.NewJSONLogger(os.Stdout)
+	}
+	return config, nil
+}
+
 // New creates a new cobra.Command root for complyctl
 func New() *cobra.Command {
 
@@ -50,11 +93,33 @@ func New() *cobra.Command {
 		versionCmd(&opts),
 		scanCmd(&opts),
 		generateCmd(&opts),
+		initCmd(&opts),
 		planCmd(&opts),
 		listCmd(&opts),
 		infoCmd(&opts),
+		diffCmd(&opts),
+		reportCmd(&opts),
+		doctorCmd(&opts),
+		pluginsCmd(&opts),
+		validateCmd(&opts),
+		bundleCmd(&opts),
+		resultsCmd(&opts),
+		historyCmd(&opts),
+		trendCmd(&opts),
+		statusCmd(&opts),
+		remediationCmd(&opts),
 	)
-	cmd.PersistentPreRun = func(_ *cobra.Command, _ []string) { enableDebug(&opts) }
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		config, err := applyConfig(cmd)
+		if err != nil {
+			// Configuration errors are not usage errors.
+			cmd.SilenceUsage = true
+			return err
+		}
+		opts.ApplicationDirectory = config.ApplicationDirectory
+		enableDebug(&opts)
+		return nil
+	}
 
 	return cmd
 }
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+)
+
+func TestApplyConfig(t *testing.T) {
+	workspace := t.TempDir()
+	config := "pluginConfig: ./config.d\napplicationDirectory: /opt/complytime\noutput: yaml\ndebug: true\n"
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, option.WorkspaceConfigFile), []byte(config), 0600))
+
+	common := &option.Common{}
+	format := &option.Format{}
+	complyTimeOpts := &option.ComplyTime{}
+	var pluginConfig string
+	cmd := &cobra.Command{Use: "test"}
+	common.BindFlags(cmd.Flags())
+	format.BindFlags(cmd.Flags())
+	complyTimeOpts.BindFlags(cmd.Flags())
+	cmd.Flags().StringVarP(&pluginConfig, "plugin-config", "c", "", "")
+	require.NoError(t, cmd.ParseFlags([]string{"--workspace", workspace, "--plugin-config", "./mine"}))
+
+	loaded, err := applyConfig(cmd)
+	require.NoError(t, err)
+	require.Equal(t, "/opt/complytime", loaded.ApplicationDirectory)
+	require.Equal(t, workspace, complyTimeOpts.UserWorkspace)
+	require.Equal(t, "./mine", pluginConfig)
+	require.Equal(t, option.OutputFormatYAML, format.OutputFormat)
+	require.True(t, common.Debug)
+}
@@ -3,15 +3,15 @@
 package cli
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework/actions"
-	"github.com/oscal-compass/oscal-sdk-go/extensions"
-	"github.com/oscal-compass/oscal-sdk-go/settings"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 	"github.com/spf13/cobra"
 
@@ -21,14 +21,29 @@ import (
 
 const assessmentResultsLocationJson = "assessment-results.json"
 const assessmentResultsLocationMd = "assessment-results.md"
+const assessmentResultsLocationHtml = "assessment-results.html"
+const assessmentResultsLocationJUnit = "assessment-results.junit.xml"
 
 // scanOptions defined options for the scan subcommand.
 type scanOptions struct {
 	*option.Common
 	complyTimeOpts   *option.ComplyTime
 	withPluginConfig string
+	thresholds       complytime.Thresholds
 }
 
+var scanExample = `
+# Scan the environment with the assessment plan in the workspace.
+complyctl scan
+
+# Exit with code 2 when any rule fails or errors.
+complyctl scan --fail-on any
+
+# Exit with code 3 when more than 5 rules fail, code 4 when less than 90% of the
+# rules pass and code 5 when less than 80% of the rules of any control pass.
+complyctl scan --max-failures 5 --min-pass-rate 90 --min-control-pass-rate 80
+`
+
 // scanCmd creates a new cobra.Command for the version subcommand.
 func scanCmd(common *option.Common) *cobra.Command {
 	scanOpts := &scanOptions{
@@ -38,15 +53,24 @@ func scanCmd(common *option.Common) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:          "scan [flags]",
 		Short:        "Scan environment with assessment plan",
-		Example:      "complyctl scan",
+		Example:      scanExample,
 		SilenceUsage: true,
 		Args:         cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := scanOpts.thresholds.Validate(); err != nil {
+				return err
+			}
 			return runScan(cmd, scanOpts)
 		},
 	}
 	cmd.Flags().StringVarP(&scanOpts.withPluginConfig, "plugin-config", "c", "", "Directory where user customized plugin manifests located.")
 	cmd.Flags().BoolP("with-md", "m", false, "If true, assessement-result markdown will be generated")
+	cmd.Flags().Bool("with-html", false, "If true, a self-contained assessment-result HTML report will be generated")
+	cmd.Flags().Bool("with-junit", false, "If true, assessment-result JUnit XML will be generated for CI systems")
+	cmd.Flags().StringVar(&scanOpts.thresholds.FailOn, "fail-on", "", "exit with code 2 when any rule has the given status, one of: fail, error, any")
+	cmd.Flags().IntVar(&scanOpts.thresholds.MaxFailures, "max-failures", -1, "exit with code 3 when more rules fail or error than the given number. Disabled when negative.")
+	cmd.Flags().Float64Var(&scanOpts.thresholds.MinPassRate, "min-pass-rate", 0, "exit with code 4 when the percentage of passing rules is lower than the given value")
+	cmd.Flags().Float64Var(&scanOpts.thresholds.MinControlPassRate, "min-control-pass-rate", 0, "exit with code 5 when the percentage of passing rules of any control is lower than the given value")
 	scanOpts.complyTimeOpts.BindFlags(cmd.Flags())
 	return cmd
 }
@@ -65,7 +89,7 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 	}
 
 	// Create the application directory if it does not exist
-	appDir, err := complytime.NewApplicationDirectory(true)
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, true)
 	if err != nil {
 		return err
 	}
@@ -85,13 +109,12 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 	}
 
 	// Determine what profile to load from framework information captured
-	// from state (assessment plan). This is required to populate complyTime required plugin options.
-	frameworkProp, valid := extensions.GetTrestleProp(extensions.FrameworkProp, *ap.Metadata.Props)
-	if !valid {
-		return fmt.Errorf("error reading framework property from assessment plan")
+	// in the workspace state. This is required to populate complyTime required plugin options.
+	state, err := loadWorkspaceState(opts.complyTimeOpts, ap, apCleanedPath)
+	if err != nil {
+		return err
 	}
-	opts.complyTimeOpts.FrameworkID = frameworkProp.Value
-	logger.Debug(fmt.Sprintf("Framework property was successfully read from the assessment plan: %v.", frameworkProp))
+	frameworkID := opts.complyTimeOpts.FrameworkID
 
 	pluginOptions := opts.complyTimeOpts.ToPluginOptions()
 	pluginOptions.UserConfigRoot = opts.withPluginConfig
@@ -115,69 +138,140 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 	if err != nil {
 		return err
 	}
+	// Each output is also recorded in the run directory of the workspace history.
+	runFiles := make(map[string][]byte)
+	assessmentResultsJson, err := complytime.MarshalAssessmentResults(assessmentResults)
+	if err != nil {
+		return err
+	}
 	arJsonPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationJson)
-	err = complytime.WriteAssessmentResults(assessmentResults, arJsonPath)
+	err = os.WriteFile(arJsonPath, assessmentResultsJson, 0600)
 	if err != nil {
 		return err
 	}
+	runFiles[assessmentResultsLocationJson] = assessmentResultsJson
 	logger.Info(fmt.Sprintf("The assessment results in JSON were successfully written to %v.", arJsonPath))
 
+	// The run history always keeps a markdown report, written to the workspace on request.
 	outputFlag, _ := cmd.Flags().GetBool("with-md")
+	assessmentResultsMd, err := renderMarkdownReport(appDir, assessmentResults, ap, validator)
+	switch {
+	case err != nil && outputFlag:
+		return err
+	case err != nil:
+		logger.Warn(fmt.Sprintf("The markdown report is not recorded in the run history: %v", err))
+	default:
+		runFiles[assessmentResultsLocationMd] = assessmentResultsMd
+	}
 	if outputFlag {
-		var profileHref string
-		compDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validator)
+		arMarkdownPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationMd)
+		err = os.WriteFile(arMarkdownPath, assessmentResultsMd, 0600)
 		if err != nil {
 			return err
 		}
-		for _, compDef := range compDefs {
-			if compDef.Components == nil {
-				continue
-			}
-			for _, component := range *compDef.Components {
-				if component.ControlImplementations == nil {
-					continue
-				}
-				for _, implementation := range *component.ControlImplementations {
-					frameworkShortName, found := settings.GetFrameworkShortName(implementation)
-					// If the framework property value match the assessment plan framework property values
-					// this is the correct control source.
-					if found && frameworkShortName == frameworkProp.Value {
-						profileHref = implementation.Source
-						break
-					}
-				}
-				if profileHref != "" {
-					break
-				}
-			}
-		}
+		logger.Info(fmt.Sprintf("The assessment results in markdown were successfully written to %v.", arMarkdownPath))
+	} else {
+		logger.Info("No assessment result in markdown will be generated.")
+	}
 
-		profile, err := complytime.LoadProfile(appDir, profileHref, validator)
+	htmlFlag, _ := cmd.Flags().GetBool("with-html")
+	if htmlFlag {
+		assessmentResultsHtml, err := renderHTMLReport(appDir, assessmentResults, ap, validator)
 		if err != nil {
 			return err
 		}
-
-		if len(profile.Imports) != 1 {
-			return errors.New("profile imports must be one")
-		}
-		catalog, err := complytime.LoadCatalogSource(appDir, profile.Imports[0].Href, validator)
+		arHtmlPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationHtml)
+		err = os.WriteFile(arHtmlPath, assessmentResultsHtml, 0600)
 		if err != nil {
 			return err
 		}
-		arMarkdownPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationMd)
+		runFiles[assessmentResultsLocationHtml] = assessmentResultsHtml
+		logger.Info(fmt.Sprintf("The assessment results in HTML were successfully written to %v.", arHtmlPath))
+	}
 
-		posture := framework.NewPosture(assessmentResults, catalog, ap, logger)
-		assessmentResultsMd, err := posture.Generate(arMarkdownPath)
+	junitFlag, _ := cmd.Flags().GetBool("with-junit")
+	if junitFlag {
+		assessmentResultsJUnit, err := complytime.GenerateJUnit(assessmentResults, ap, strings.Join(opts.complyTimeOpts.FrameworkIDs, ","))
 		if err != nil {
 			return err
 		}
-		err = os.WriteFile(arMarkdownPath, assessmentResultsMd, 0600)
+		arJUnitPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationJUnit)
+		err = os.WriteFile(arJUnitPath, assessmentResultsJUnit, 0600)
 		if err != nil {
 			return err
 		}
-		logger.Info(fmt.Sprintf("The assessment results in markdown were successfully written to %v.", arMarkdownPath))
-	} else {
-		logger.Info("No assessment result in markdown will be generated.")
+		runFiles[assessmentResultsLocationJUnit] = assessmentResultsJUnit
+		logger.Info(fmt.Sprintf("The assessment results in JUnit XML were successfully written to %v.", arJUnitPath))
+	}
+
+	summary := complytime.SummarizeResults(assessmentResults, ap)
+	if err := recordScanRun(opts.complyTimeOpts.UserWorkspace, frameworkID, apCleanedPath, summary, assessmentResults, runFiles); err != nil {
+		return err
+	}
+
+	scanned := time.Now().UTC()
+	state.LastScan = &scanned
+	if err := writeWorkspaceState(opts.complyTimeOpts.UserWorkspace, state); err != nil {
+		return err
+	}
+
+	return checkThresholds(opts.thresholds, summary)
+}
+
+// recordScanRun records the outputs of a scan in a new run directory of the workspace history
+// and prunes the runs exceeding the configured retention.
+func recordScanRun(workspace, frameworkID, planPath string, summary complytime.ResultsSummary, assessmentResults *oscalTypes.AssessmentResults, files map[string][]byte) error {
+	planDigest, err := complytime.PlanDigest(planPath)
+	if err != nil {
+		return err
+	}
+	run := complytime.Run{
+		FrameworkID:   frameworkID,
+		PlanDigest:    planDigest,
+		RuleCounts:    summary.RuleCounts,
+		ControlCounts: summary.ControlCounts,
+		Artifacts:     complytime.RunArtifacts(assessmentResults),
+	}
+	for _, framework := range summary.Frameworks {
+		run.Frameworks = append(run.Frameworks, complytime.FrameworkCounts{
+			FrameworkID:   framework.FrameworkID,
+			RuleCounts:    framework.RuleCounts,
+			ControlCounts: framework.ControlCounts,
+		})
+	}
+	now := time.Now()
+	run, err = complytime.RecordRun(workspace, run, files, now)
+	if err != nil {
+		return fmt.Errorf("error recording scan run: %w", err)
+	}
+	logger.Info(fmt.Sprintf("The scan run %s was recorded in %v.", run.ID, complytime.RunPath(workspace, run.ID)))
+
+	retention, err := loadedConfig.History.Retention()
+	if err != nil {
+		return err
+	}
+	pruned, err := complytime.PruneRuns(workspace, retention, now, false)
+	if err != nil {
+		return fmt.Errorf("error pruning scan runs: %w", err)
+	}
+	for _, prunedRun := range pruned {
+		logger.Debug(fmt.Sprintf("Pruned scan run %s", prunedRun.ID))
 	}
 	return nil
 }
+
+// checkThresholds evaluates the thresholds against the results summary and returns an
+// ExitError with the exit code of the first breached threshold.
+func checkThresholds(thresholds complytime.Thresholds, summary complytime.ResultsSummary) error {
+	breaches := thresholds.Evaluate(summary)
+	if len(breaches) == 0 {
+		return nil
+	}
+	for _, breach := range breaches {
+		logger.Warn(fmt.Sprintf("Threshold %s breached: %s", breach.Kind, breach.Message))
+	}
+	return &ExitError{
+		Code: breachExitCodes[breaches[0].Kind],
+		Err:  fmt.Errorf("threshold %s breached: %s", breaches[0].Kind, breaches[0].Message),
+	}
+}
@@ -0,0 +1,307 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// statusOptions defines options for the "status" subcommand
+type statusOptions struct {
+	*option.Common
+	option.Format
+	complyTimeOpts *option.ComplyTime
+	plain          bool
+}
+
+var statusExample = `
+# Summarize the posture of the workspace from the assessment plan and the latest results.
+complyctl status
+
+# Print the summary with minimal formatting.
+complyctl status --plain
+
+# Print the summary as JSON.
+complyctl status --output json
+`
+
+// Style for warnings about stale plans and results
+var warningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+// workspaceStatus is the compliance posture of a workspace.
+type workspaceStatus struct {
+	Workspace   string `json:"workspace" yaml:"workspace"`
+	FrameworkID string `json:"framework" yaml:"framework"`
+	// Frameworks count the results of each framework for workspaces assessed against several frameworks.
+	Frameworks []complytime.FrameworkCounts `json:"frameworks,omitempty" yaml:"frameworks,omitempty"`
+	// PlanTime is the last modification time of the assessment plan.
+	PlanTime time.Time `json:"planTime" yaml:"planTime"`
+	// ResultsTime is the time the latest results were collected, unset without results.
+	ResultsTime   *time.Time              `json:"resultsTime,omitempty" yaml:"resultsTime,omitempty"`
+	RuleCounts    complytime.StatusCounts `json:"ruleCounts" yaml:"ruleCounts"`
+	ControlCounts complytime.StatusCounts `json:"controlCounts" yaml:"controlCounts"`
+	Controls      []controlStatus         `json:"controls" yaml:"controls"`
+	Warnings      []string                `json:"warnings" yaml:"warnings"`
+}
+
+// controlStatus is the status of a control in the latest results.
+type controlStatus struct {
+	ID     string                  `json:"id" yaml:"id"`
+	Title  string                  `json:"title,omitempty" yaml:"title,omitempty"`
+	Status string                  `json:"status" yaml:"status"`
+	Counts complytime.StatusCounts `json:"counts" yaml:"counts"`
+}
+
+// statusCmd creates a new cobra.Command for the "status" subcommand
+func statusCmd(common *option.Common) *cobra.Command {
+	statusOpts := &statusOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "status [flags]",
+		Short:        "Summarize the compliance posture of the workspace",
+		Example:      statusExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runStatus(statusOpts)
+		},
+	}
+	cmd.Flags().BoolVarP(&statusOpts.plain, "plain", "p", false, "print the summary with minimal formatting")
+	statusOpts.Format.BindFlags(cmd.Flags())
+	statusOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runStatus(opts *statusOptions) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, false)
+	if err != nil {
+		return err
+	}
+	status, err := loadWorkspaceStatus(appDir, opts.complyTimeOpts, validation.NewSchemaValidator())
+	if err != nil {
+		return err
+	}
+
+	if opts.Structured() {
+		return writeStructured(opts.Out, opts.OutputFormat, status)
+	} else if opts.plain {
+		showPlainStatus(opts.Out, status, time.Now())
+		return nil
+	}
+	return runBubbleTeaProgram(newStatusModel(status, time.Now()), opts.Out)
+}
+
+// loadWorkspaceStatus summarizes the latest results in the workspace against the assessment plan
+// and warns about results older than the plan and about bundles changed since the plan was written.
+func loadWorkspaceStatus(appDir complytime.ApplicationDirectory, opts *option.ComplyTime, validator validation.Validator) (workspaceStatus, error) {
+	ap, apPath, err := loadPlan(opts, validator)
+	if err != nil {
+		return workspaceStatus{}, err
+	}
+	planInfo, err := os.Stat(apPath)
+	if err != nil {
+		return workspaceStatus{}, err
+	}
+	state, err := readWorkspaceState(opts.UserWorkspace)
+	if err != nil {
+		return workspaceStatus{}, err
+	}
+	frameworkIDs := state.FrameworkIDs()
+	if len(frameworkIDs) == 0 {
+		frameworkIDs, err = complytime.PlanFrameworkIDs(ap)
+		if err != nil {
+			return workspaceStatus{}, err
+		}
+	}
+
+	status := workspaceStatus{
+		Workspace:   opts.UserWorkspace,
+		FrameworkID: frameworkIDs[0],
+		PlanTime:    planInfo.ModTime().UTC().Truncate(time.Second),
+		Controls:    []controlStatus{},
+		Warnings:    []string{},
+	}
+	if state.PlanDigest != "" {
+		if err := state.VerifyPlan(apPath); err != nil {
+			if !errors.Is(err, complytime.ErrPlanModified) {
+				return workspaceStatus{}, err
+			}
+			status.Warnings = append(status.Warnings, "The assessment plan was modified outside of complyctl. Run the plan command to regenerate it.")
+		}
+	}
+
+	// Without results, all the rules in scope of the plan are not assessed.
+	assessmentResults := &oscalTypes.AssessmentResults{}
+	resultsPath := filepath.Join(opts.UserWorkspace, assessmentResultsLocationJson)
+	resultsInfo, err := os.Stat(resultsPath)
+	switch {
+	case err == nil:
+		assessmentResults, err = complytime.ReadAssessmentResults(resultsPath, validator)
+		if err != nil {
+			return workspaceStatus{}, err
+		}
+		resultsTime := complytime.ResultsTime(assessmentResults)
+		if resultsTime.IsZero() {
+			resultsTime = resultsInfo.ModTime()
+		}
+		resultsTime = resultsTime.UTC()
+		status.ResultsTime = &resultsTime
+		if status.PlanTime.After(resultsTime) {
+			status.Warnings = append(status.Warnings, "The assessment plan is newer than the latest results. Run the scan command to assess it.")
+		}
+	case errors.Is(err, os.ErrNotExist):
+		status.Warnings = append(status.Warnings, "No assessment results found. Run the scan command to assess the workspace.")
+	default:
+		return workspaceStatus{}, err
+	}
+
+	if changes, err := bundleChanges(appDir, state, frameworkIDs, validator); err != nil {
+		logger.Debug(fmt.Sprintf("Bundle changes not checked: %v", err))
+	} else if len(changes) > 0 {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("The bundles changed since the plan was written: %s. Run the plan command to update it.", strings.Join(changes, ", ")))
+	}
+
+	summary := complytime.SummarizeResults(assessmentResults, ap)
+	status.RuleCounts = summary.RuleCounts
+	status.ControlCounts = summary.ControlCounts
+	for _, framework := range summary.Frameworks {
+		status.Frameworks = append(status.Frameworks, complytime.FrameworkCounts{
+			FrameworkID:   framework.FrameworkID,
+			RuleCounts:    framework.RuleCounts,
+			ControlCounts: framework.ControlCounts,
+		})
+	}
+	titles := loadControlTitles(appDir, ap, validator)
+	for _, control := range summary.Controls {
+		status.Controls = append(status.Controls, controlStatus{
+			ID:     control.ControlID,
+			Title:  titles[control.ControlID],
+			Status: control.Status,
+			Counts: control.Counts,
+		})
+	}
+	return status, nil
+}
+
+// bundleChanges returns the changes of the installed component definitions implementing
+// the frameworks since the plan was written.
+func bundleChanges(appDir complytime.ApplicationDirectory, state *complytime.WorkspaceState, frameworkIDs []string, validator validation.Validator) ([]string, error) {
+	componentDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validator)
+	if err != nil {
+		return nil, err
+	}
+	return state.BundleChanges(complytime.BundleVersions(componentDefs, frameworkIDs...)), nil
+}
+
+// formatAge returns a time with how long ago it was.
+func formatAge(t time.Time, now time.Time) string {
+	age := now.Sub(t)
+	var ago string
+	switch {
+	case age < time.Minute:
+		ago = "just now"
+	case age < time.Hour:
+		ago = fmt.Sprintf("%d minute(s) ago", int(age.Minutes()))
+	case age < 48*time.Hour:
+		ago = fmt.Sprintf("%d hour(s) ago", int(age.Hours()))
+	default:
+		ago = fmt.Sprintf("%d days ago", int(age.Hours()/24))
+	}
+	return fmt.Sprintf("%s (%s)", t.Format(time.RFC3339), ago)
+}
+
+// statusFields returns the summary fields of the workspace status as keys and values.
+func statusFields(status workspaceStatus, now time.Time) [][2]string {
+	results := "none"
+	if status.ResultsTime != nil {
+		results = formatAge(*status.ResultsTime, now)
+	}
+	fields := [][2]string{
+		{"Workspace", status.Workspace},
+		{"Framework", status.FrameworkID},
+		{"Plan", formatAge(status.PlanTime, now)},
+		{"Results", results},
+		{"Rules", formatStatusCounts(status.RuleCounts)},
+		{"Controls", formatStatusCounts(status.ControlCounts)},
+	}
+	for _, framework := range status.Frameworks {
+		fields = append(fields, [2]string{framework.FrameworkID, formatFrameworkCounts(framework)})
+	}
+	return fields
+}
+
+// getStatusColumnsAndRows prepares columns and rows for the table of control statuses.
+func getStatusColumnsAndRows(status workspaceStatus) ([]table.Column, []table.Row) {
+	controls := make([]complytime.ControlResult, 0, len(status.Controls))
+	titles := make(map[string]string, len(status.Controls))
+	for _, control := range status.Controls {
+		controls = append(controls, complytime.ControlResult{ControlID: control.ID, Status: control.Status, Counts: control.Counts})
+		titles[control.ID] = control.Title
+	}
+	return getResultsControlColumnsAndRows(controls, titles)
+}
+
+// showPlainStatus prints the workspace status with minimal formatting.
+func showPlainStatus(writer io.Writer, status workspaceStatus, now time.Time) {
+	for _, field := range statusFields(status, now) {
+		_, _ = fmt.Fprintf(writer, "%s: %s\n", field[0], field[1])
+	}
+	for _, warning := range status.Warnings {
+		_, _ = fmt.Fprintf(writer, "Warning: %s\n", warning)
+	}
+	if len(status.Controls) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(writer)
+	columns, rows := getStatusColumnsAndRows(status)
+	terminal.ShowPlainTable(writer, columns, rows)
+}
+
+// newStatusModel creates a Bubble Tea model displaying the workspace status.
+func newStatusModel(status workspaceStatus, now time.Time) terminal.Model {
+	var fields []string
+	for _, field := range statusFields(status, now) {
+		fields = append(fields, renderKeyValuePair(field[0], field[1]))
+	}
+	header := infoContainerStyle.Render(strings.Join(fields, "\n"))
+	for _, warning := range status.Warnings {
+		header += "\n" + warningStyle.Render("Warning: "+warning)
+	}
+
+	columns, rows := getStatusColumnsAndRows(status)
+	tbl := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(len(rows)+1),
+	)
+	tbl.SetStyles(table.Styles{
+		Header: tableHeaderStyle,
+		Cell:   tableCellStyle,
+	})
+	return terminal.Model{
+		Table:     tbl,
+		HeaderMsg: header,
+	}
+}
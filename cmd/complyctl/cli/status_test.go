@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestLoadWorkspaceStatus(t *testing.T) {
+	appDirPath := t.TempDir()
+	require.NoError(t, os.CopyFS(appDirPath, os.DirFS(filepath.Join("..", "..", "..", "internal", "complytime", "testdata", "bundle"))))
+	appDir, err := complytime.NewApplicationDirectory(appDirPath, false)
+	require.NoError(t, err)
+
+	workspace := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("testdata", assessmentPlanLocation))
+	require.NoError(t, err)
+	apPath := filepath.Join(workspace, assessmentPlanLocation)
+	require.NoError(t, os.WriteFile(apPath, data, 0600))
+	state := complytime.NewWorkspaceState()
+	require.NoError(t, state.RecordPlan(apPath, []string{"example"}, []complytime.BundleVersion{{Title: "Old bundle", Version: "0.0.1"}}))
+	require.NoError(t, writeWorkspaceState(workspace, state))
+
+	opts := &option.ComplyTime{UserWorkspace: workspace}
+	status, err := loadWorkspaceStatus(appDir, opts, validation.NoopValidator{})
+	require.NoError(t, err)
+	require.Equal(t, "example", status.FrameworkID)
+	require.Nil(t, status.ResultsTime)
+	require.Len(t, status.Warnings, 2)
+	require.Equal(t, "No assessment results found. Run the scan command to assess the workspace.", status.Warnings[0])
+	require.Equal(t, "The bundles changed since the plan was written: My sample component definition. 0.1.0 was added, Old bundle 0.0.1 was removed. Run the plan command to update it.", status.Warnings[1])
+
+	// Results collected before the plan was written.
+	collected := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, complytime.WriteAssessmentResults(&oscalTypes.AssessmentResults{
+		UUID:    "7c5e9b5a-4f0e-4c3f-9c1d-2f7b8e6a1d01",
+		Results: []oscalTypes.Result{{UUID: "7c5e9b5a-4f0e-4c3f-9c1d-2f7b8e6a1d02", Start: collected}},
+	}, filepath.Join(workspace, assessmentResultsLocationJson)))
+	state.Bundles = nil
+	require.NoError(t, writeWorkspaceState(workspace, state))
+	status, err = loadWorkspaceStatus(appDir, opts, validation.NoopValidator{})
+	require.NoError(t, err)
+	require.Equal(t, collected, *status.ResultsTime)
+	require.Equal(t, []string{"The assessment plan is newer than the latest results. Run the scan command to assess it."}, status.Warnings)
+}
+
+func TestShowPlainStatus(t *testing.T) {
+	now := time.Date(2025, 5, 4, 12, 0, 0, 0, time.UTC)
+	resultsTime := now.Add(-3 * time.Hour)
+	status := workspaceStatus{
+		Workspace:     "complytime",
+		FrameworkID:   "example",
+		PlanTime:      now.Add(-72 * time.Hour),
+		ResultsTime:   &resultsTime,
+		RuleCounts:    complytime.StatusCounts{Pass: 2, Fail: 1},
+		ControlCounts: complytime.StatusCounts{Fail: 1},
+		Controls:      []controlStatus{{ID: "control-1", Title: "First control", Status: complytime.StatusFail, Counts: complytime.StatusCounts{Pass: 2, Fail: 1}}},
+		Warnings:      []string{"Something is stale."},
+	}
+	out := bytes.NewBuffer(nil)
+	showPlainStatus(out, status, now)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 10)
+	require.Equal(t, []string{
+		"Workspace: complytime",
+		"Framework: example",
+		"Plan: 2025-05-01T12:00:00Z (3 days ago)",
+		"Results: 2025-05-04T09:00:00Z (3 hour(s) ago)",
+		"Rules: (pass 2, fail 1, error 0, not assessed 0)",
+		"Controls: (pass 0, fail 1, error 0, not assessed 0)",
+		"Warning: Something is stale.",
+		"",
+	}, lines[:8])
+	require.Equal(t, []string{"control-1", "First", "control", "fail", "2", "1", "0", "0"}, strings.Fields(lines[9]))
+}
+
+func TestFormatAge(t *testing.T) {
+	now := time.Date(2025, 5, 4, 12, 0, 0, 0, time.UTC)
+	require.Equal(t, "2025-05-04T11:59:30Z (just now)", formatAge(now.Add(-30*time.Second), now))
+	require.Equal(t, "2025-05-04T11:15:00Z (45 minute(s) ago)", formatAge(now.Add(-45*time.Minute), now))
+	require.Equal(t, "2025-05-02T13:00:00Z (47 hour(s) ago)", formatAge(now.Add(-47*time.Hour), now))
+	require.Equal(t, "2025-04-24T12:00:00Z (10 days ago)", formatAge(now.Add(-240*time.Hour), now))
+}
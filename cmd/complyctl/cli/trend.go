@@ -0,0 +1,337 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// outputFormatCSV writes the trend as CSV rows for plotting.
+const outputFormatCSV = "csv"
+
+// trendOptions defines options for the "trend" subcommand
+type trendOptions struct {
+	*option.Common
+	option.Format
+	complyTimeOpts *option.ComplyTime
+	paths          []string
+}
+
+var trendExample = `
+# Show the compliance trend of the scan runs recorded in the workspace history.
+complyctl trend
+
+# Show the trend of archived assessment results, from files or directories of JSON files.
+complyctl trend archive/ q3-assessment-results.json
+
+# Export the pass rates of the framework and of each control over time for plotting.
+complyctl trend --output csv > trend.csv
+`
+
+// trendCmd creates a new cobra.Command for the "trend" subcommand
+func trendCmd(common *option.Common) *cobra.Command {
+	trendOpts := &trendOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "trend [flags] [file|dir]...",
+		Short:        "Report the compliance trend across a series of assessment results",
+		Example:      trendExample,
+		SilenceUsage: true,
+		PreRun: func(_ *cobra.Command, args []string) {
+			for _, path := range args {
+				trendOpts.paths = append(trendOpts.paths, filepath.Clean(path))
+			}
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runTrend(trendOpts)
+		},
+	}
+	cmd.Flags().StringVar(&trendOpts.OutputFormat, "output", "", "output format, one of: json, yaml, csv")
+	trendOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runTrend(opts *trendOptions) error {
+	switch opts.OutputFormat {
+	case "", option.OutputFormatJSON, option.OutputFormatYAML, outputFormatCSV:
+	default:
+		return fmt.Errorf("invalid output format %q: must be one of %q, %q or %q", opts.OutputFormat, option.OutputFormatJSON, option.OutputFormatYAML, outputFormatCSV)
+	}
+	validator := validation.NewSchemaValidator()
+
+	plan, err := loadOptionalPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+
+	paths := opts.paths
+	if len(paths) == 0 {
+		historyDir := complytime.HistoryPath(opts.complyTimeOpts.UserWorkspace)
+		if _, err := os.Stat(historyDir); err != nil {
+			return fmt.Errorf("no scan runs recorded in %s: run a scan or give assessment results files", historyDir)
+		}
+		paths = []string{historyDir}
+	}
+	inputs, err := loadTrendInputs(paths, plan, validator)
+	if err != nil {
+		return err
+	}
+	if len(inputs) == 0 {
+		return fmt.Errorf("no assessment results found in %s", strings.Join(paths, ", "))
+	}
+	trend := complytime.ComputeTrend(inputs)
+
+	switch {
+	case opts.OutputFormat == outputFormatCSV:
+		return complytime.WriteTrendCSV(opts.Out, trend)
+	case opts.Structured():
+		return writeStructured(opts.Out, opts.OutputFormat, trend)
+	}
+	showTrendTables(opts.Out, trend)
+	return nil
+}
+
+// loadTrendInputs reads the assessment results at the given paths. Directories are searched for
+// JSON files and for subdirectories with an assessment-results.json, such as scan run directories.
+// Files found in directories that are not assessment results are skipped.
+func loadTrendInputs(paths []string, plan *oscalTypes.AssessmentPlan, validator validation.Validator) ([]complytime.TrendInput, error) {
+	var planFrameworkIDs []string
+	if plan != nil {
+		frameworkIDs, err := complytime.PlanFrameworkIDs(plan)
+		if err != nil {
+			return nil, err
+		}
+		planFrameworkIDs = frameworkIDs
+	}
+
+	var inputs []complytime.TrendInput
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			fileInputs, err := loadTrendInput(path, plan, planFrameworkIDs, validator)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, fileInputs...)
+			continue
+		}
+
+		files, err := findResultsFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			fileInputs, err := loadTrendInput(file, plan, planFrameworkIDs, validator)
+			if err != nil {
+				logger.Warn(fmt.Sprintf("Skipping %s: %v", file, err))
+				continue
+			}
+			inputs = append(inputs, fileInputs...)
+		}
+	}
+	return inputs, nil
+}
+
+// findResultsFiles returns the JSON files of a directory and the assessment-results.json
+// files of its subdirectories.
+func findResultsFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() {
+			if filepath.Ext(entry.Name()) == ".json" {
+				files = append(files, path)
+			}
+			continue
+		}
+		resultsPath := filepath.Join(path, assessmentResultsLocationJson)
+		if _, err := os.Stat(resultsPath); err == nil {
+			files = append(files, resultsPath)
+		}
+	}
+	return files, nil
+}
+
+// loadTrendInput reads and summarizes assessment results, with an input for each framework.
+// Results recorded in a scan run directory take their frameworks from the run manifest, others
+// from the workspace plan. Results of plans with several frameworks are split by framework.
+func loadTrendInput(path string, plan *oscalTypes.AssessmentPlan, planFrameworkIDs []string, validator validation.Validator) ([]complytime.TrendInput, error) {
+	assessmentResults, err := complytime.ReadAssessmentResults(path, validator)
+	if err != nil {
+		return nil, err
+	}
+	source := path
+	resultsTime := complytime.ResultsTime(assessmentResults)
+	frameworkIDs := planFrameworkIDs
+	run, err := complytime.ReadRunDir(filepath.Dir(path))
+	switch {
+	case err == nil:
+		source = run.ID
+		frameworkIDs = runFrameworkIDs(run)
+		if resultsTime.IsZero() {
+			resultsTime = run.Time
+		}
+	case !errors.Is(err, complytime.ErrRunNotFound):
+		return nil, err
+	}
+	if len(frameworkIDs) == 0 {
+		frameworkIDs = []string{""}
+	}
+
+	inputs := make([]complytime.TrendInput, 0, len(frameworkIDs))
+	for _, frameworkID := range frameworkIDs {
+		input := complytime.TrendInput{
+			Source:      source,
+			Time:        resultsTime,
+			FrameworkID: frameworkID,
+		}
+		switch {
+		case !slices.Contains(planFrameworkIDs, frameworkID):
+			// The plan only maps the rules of its own frameworks to controls.
+			input.Summary = complytime.SummarizeResults(assessmentResults, nil)
+		case len(frameworkIDs) == 1 && len(planFrameworkIDs) == 1:
+			input.Summary = complytime.SummarizeResults(assessmentResults, plan)
+		default:
+			input.Summary = complytime.SummarizeFrameworkResults(assessmentResults, plan, frameworkID)
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs, nil
+}
+
+// showTrendTables prints a table of the results over time and a table of the
+// control pass rate changes for each framework.
+func showTrendTables(writer io.Writer, trend complytime.Trend) {
+	for i, frameworkTrend := range trend.Frameworks {
+		if i > 0 {
+			_, _ = fmt.Fprintln(writer)
+		}
+		frameworkID := frameworkTrend.FrameworkID
+		if frameworkID == "" {
+			frameworkID = "unknown framework"
+		}
+		_, _ = fmt.Fprintf(writer, "%s: %d assessment results, %s\n", frameworkID, len(frameworkTrend.Points), formatRemediation(frameworkTrend))
+		columns, rows := getTrendColumnsAndRows(frameworkTrend.Points)
+		terminal.ShowPlainTable(writer, columns, rows)
+
+		_, _ = fmt.Fprintln(writer)
+		_, _ = fmt.Fprintln(writer, "Control pass rates")
+		columns, rows = getControlTrendColumnsAndRows(frameworkTrend.Points)
+		terminal.ShowPlainTable(writer, columns, rows)
+	}
+}
+
+// formatRemediation describes the remediations and the mean time to remediate.
+func formatRemediation(frameworkTrend complytime.FrameworkTrend) string {
+	if frameworkTrend.Remediations == 0 {
+		return "no rules fixed"
+	}
+	meanTime := time.Duration(frameworkTrend.MeanTimeToRemediateHours * float64(time.Hour)).Round(time.Minute)
+	return fmt.Sprintf("%d rule(s) fixed with a mean time to remediate of %s", frameworkTrend.Remediations, meanTime)
+}
+
+// getTrendColumnsAndRows prepares columns and rows for the table of results over time.
+func getTrendColumnsAndRows(points []complytime.TrendPoint) ([]table.Column, []table.Row) {
+	var rows []table.Row
+	for _, point := range points {
+		rows = append(rows, table.Row{
+			point.Time.UTC().Format(time.RFC3339),
+			point.Source,
+			formatPassRate(point.PassRate),
+			fmt.Sprint(point.RuleCounts.Pass),
+			fmt.Sprint(point.RuleCounts.Fail),
+			fmt.Sprint(point.RuleCounts.Error),
+			fmt.Sprint(point.RuleCounts.NotAssessed),
+			fmt.Sprint(len(point.NewlyFailing)),
+			fmt.Sprint(len(point.NewlyFixed)),
+		})
+	}
+	columns := []table.Column{
+		{Title: "Time", Width: 21},
+		{Title: "Source", Width: 20},
+		{Title: "Pass Rate", Width: 10},
+		{Title: "Pass", Width: 6},
+		{Title: "Fail", Width: 6},
+		{Title: "Error", Width: 6},
+		{Title: "Not Assessed", Width: 13},
+		{Title: "Newly Failing", Width: 14},
+		{Title: "Fixed", Width: 6},
+	}
+	fitColumnWidths(columns, rows)
+	return columns, rows
+}
+
+// getControlTrendColumnsAndRows prepares columns and rows comparing the pass rate of
+// each control in the first and the latest results.
+func getControlTrendColumnsAndRows(points []complytime.TrendPoint) ([]table.Column, []table.Row) {
+	first := make(map[string]float64)
+	latest := make(map[string]float64)
+	var controlIDs []string
+	for _, control := range points[0].Controls {
+		first[control.ControlID] = control.PassRate
+		controlIDs = append(controlIDs, control.ControlID)
+	}
+	for _, control := range points[len(points)-1].Controls {
+		latest[control.ControlID] = control.PassRate
+		if _, found := first[control.ControlID]; !found {
+			controlIDs = append(controlIDs, control.ControlID)
+		}
+	}
+	sort.Strings(controlIDs)
+
+	var rows []table.Row
+	for _, controlID := range controlIDs {
+		firstRate, inFirst := first[controlID]
+		latestRate, inLatest := latest[controlID]
+		change := "-"
+		if inFirst && inLatest {
+			change = fmt.Sprintf("%+.1f", latestRate-firstRate)
+		}
+		rows = append(rows, table.Row{controlID, formatOptionalPassRate(firstRate, inFirst), formatOptionalPassRate(latestRate, inLatest), change})
+	}
+	columns := []table.Column{
+		{Title: "Control ID", Width: colWidthControlID},
+		{Title: "First", Width: 8},
+		{Title: "Latest", Width: 8},
+		{Title: "Change", Width: 8},
+	}
+	fitColumnWidths(columns, rows)
+	return columns, rows
+}
+
+func formatPassRate(passRate float64) string {
+	return fmt.Sprintf("%.1f%%", passRate)
+}
+
+func formatOptionalPassRate(passRate float64, found bool) string {
+	if !found {
+		return "-"
+	}
+	return formatPassRate(passRate)
+}
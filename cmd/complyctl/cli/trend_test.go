@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestLoadTrendInputs(t *testing.T) {
+	workspace := t.TempDir()
+	start := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	results, err := complytime.MarshalAssessmentResults(&oscalTypes.AssessmentResults{
+		UUID:    "7c5e9b5a-4f0e-4c3f-9c1d-2f7b8e6a1d01",
+		Results: []oscalTypes.Result{{UUID: "7c5e9b5a-4f0e-4c3f-9c1d-2f7b8e6a1d02", Start: start}},
+	})
+	require.NoError(t, err)
+	run, err := complytime.RecordRun(workspace, complytime.Run{FrameworkID: "example"}, map[string][]byte{assessmentResultsLocationJson: results}, start.Add(time.Minute))
+	require.NoError(t, err)
+
+	archive := filepath.Join(workspace, "archive")
+	require.NoError(t, os.MkdirAll(archive, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(archive, "q1.json"), results, 0600))
+	// Files in directories that are not assessment results are skipped.
+	require.NoError(t, os.WriteFile(filepath.Join(archive, "notes.json"), []byte("not json"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(archive, "notes.txt"), []byte("notes"), 0600))
+
+	inputs, err := loadTrendInputs([]string{complytime.HistoryPath(workspace), archive}, nil, validation.NoopValidator{})
+	require.NoError(t, err)
+	require.Len(t, inputs, 2)
+	require.Equal(t, run.ID, inputs[0].Source)
+	require.Equal(t, "example", inputs[0].FrameworkID)
+	require.Equal(t, start, inputs[0].Time)
+	require.Equal(t, filepath.Join(archive, "q1.json"), inputs[1].Source)
+	require.Empty(t, inputs[1].FrameworkID)
+
+	// Files given explicitly must be assessment results.
+	_, err = loadTrendInputs([]string{filepath.Join(archive, "notes.json")}, nil, validation.NoopValidator{})
+	require.Error(t, err)
+}
+
+func TestLoadTrendInputsFrameworks(t *testing.T) {
+	workspace := t.TempDir()
+	start := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	results, err := complytime.MarshalAssessmentResults(&oscalTypes.AssessmentResults{
+		UUID:    "7c5e9b5a-4f0e-4c3f-9c1d-2f7b8e6a1d01",
+		Results: []oscalTypes.Result{{UUID: "7c5e9b5a-4f0e-4c3f-9c1d-2f7b8e6a1d02", Start: start}},
+	})
+	require.NoError(t, err)
+	run := complytime.Run{
+		FrameworkID: "cis",
+		Frameworks:  []complytime.FrameworkCounts{{FrameworkID: "cis"}, {FrameworkID: "baseline"}},
+	}
+	run, err = complytime.RecordRun(workspace, run, map[string][]byte{assessmentResultsLocationJson: results}, start.Add(time.Minute))
+	require.NoError(t, err)
+
+	// Runs of plans with several frameworks have an input for each framework.
+	inputs, err := loadTrendInputs([]string{complytime.HistoryPath(workspace)}, nil, validation.NoopValidator{})
+	require.NoError(t, err)
+	require.Len(t, inputs, 2)
+	require.Equal(t, "cis", inputs[0].FrameworkID)
+	require.Equal(t, "baseline", inputs[1].FrameworkID)
+	for _, input := range inputs {
+		require.Equal(t, run.ID, input.Source)
+		require.Equal(t, start, input.Time)
+	}
+}
+
+func TestGetControlTrendColumnsAndRows(t *testing.T) {
+	points := []complytime.TrendPoint{
+		{Controls: []complytime.ControlTrendPoint{{ControlID: "control-1", PassRate: 50}, {ControlID: "control-2", PassRate: 100}}},
+		{Controls: []complytime.ControlTrendPoint{{ControlID: "control-1", PassRate: 75}, {ControlID: "control-3", PassRate: 0}}},
+	}
+	_, rows := getControlTrendColumnsAndRows(points)
+	require.Len(t, rows, 3)
+	require.Equal(t, []string{"control-1", "50.0%", "75.0%", "+25.0"}, []string(rows[0]))
+	require.Equal(t, []string{"control-2", "100.0%", "-", "-"}, []string(rows[1]))
+	require.Equal(t, []string{"control-3", "-", "0.0%", "-"}, []string(rows[2]))
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// validateOptions defines options for the "validate" subcommand
+type validateOptions struct {
+	*option.Common
+	option.Format
+}
+
+var validateExample = `
+# Validate the component definitions, profiles and catalogs in the application directory.
+complyctl validate
+
+# Validate content before installing it. Control sources are resolved from the given
+# files first, then from the application directory.
+complyctl validate ./bundles ./controls
+
+# Print all problems as JSON.
+complyctl validate ./bundles/my-component-definition.json --output json
+`
+
+// validateCmd creates a new cobra.Command for the "validate" subcommand
+func validateCmd(common *option.Common) *cobra.Command {
+	validateOpts := &validateOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:          "validate [flags] [path...]",
+		Short:        "Validate OSCAL content against the schema and check its cross-references",
+		Example:      validateExample,
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runValidate(validateOpts, args)
+		},
+	}
+	validateOpts.Format.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runValidate(opts *validateOptions, paths []string) error {
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+	appDir, err := complytime.NewApplicationDirectory(opts.ApplicationDirectory, false)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	if len(paths) == 0 {
+		logger.Debug(fmt.Sprintf("Validating content in application directory: %s", appDir.AppDir()))
+		files, err = complytime.ContentFiles(appDir)
+	} else {
+		files, err = complytime.FindContentFiles(paths)
+	}
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no OSCAL content files found")
+	}
+
+	report := complytime.ValidateContent(appDir, files)
+	if opts.Structured() {
+		if err := writeStructured(opts.Out, opts.OutputFormat, report); err != nil {
+			return err
+		}
+	} else {
+		showContentReport(opts.Out, report)
+	}
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("validation found %d error(s)", len(report.Errors))
+	}
+	return nil
+}
+
+// showContentReport prints each problem on its own line followed by a summary.
+func showContentReport(writer io.Writer, report complytime.ContentReport) {
+	for _, contentError := range report.Errors {
+		_, _ = fmt.Fprintln(writer, contentError.String())
+	}
+	_, _ = fmt.Fprintf(writer, "%d file(s) validated, %d error(s) found\n", len(report.Files), len(report.Errors))
+}
@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestShowContentReport(t *testing.T) {
+	report := complytime.ContentReport{
+		Files: []string{"bundles/my-component-definition.json", "controls/catalog.json"},
+		Errors: []complytime.ContentError{
+			{File: "bundles/my-component-definition.json", Message: "invalid JSON at offset 1: unexpected end of JSON input"},
+			{File: "controls/catalog.json", Path: "/catalog/params", Message: "minItems: got 0, want 1"},
+		},
+	}
+	out := bytes.NewBuffer(nil)
+	showContentReport(out, report)
+	want := "bundles/my-component-definition.json: invalid JSON at offset 1: unexpected end of JSON input\n" +
+		"controls/catalog.json: /catalog/params: minItems: got 0, want 1\n" +
+		"2 file(s) validated, 2 error(s) found\n"
+	require.Equal(t, want, out.String())
+}
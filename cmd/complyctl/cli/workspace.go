@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// readWorkspaceState returns the workspace state, or a new state if the workspace
+// has not been initialized.
+func readWorkspaceState(workspace string) (*complytime.WorkspaceState, error) {
+	state, err := complytime.ReadWorkspaceState(workspace)
+	if errors.Is(err, complytime.ErrWorkspaceStateNotFound) {
+		logger.Debug(fmt.Sprintf("No workspace state found in %s, a new one will be created.", workspace))
+		return complytime.NewWorkspaceState(), nil
+	}
+	return state, err
+}
+
+// loadWorkspaceState returns the workspace state for the assessment plan at apPath and sets
+// the framework IDs from it. Workspaces without a recorded framework, such as those created
+// before the state file existed, and plans modified since they were recorded take the
+// frameworks from the assessment plan.
+func loadWorkspaceState(opts *option.ComplyTime, ap *oscalTypes.AssessmentPlan, apPath string) (*complytime.WorkspaceState, error) {
+	state, err := readWorkspaceState(opts.UserWorkspace)
+	if err != nil {
+		return nil, err
+	}
+	frameworkIDs := state.FrameworkIDs()
+	if len(frameworkIDs) == 0 {
+		frameworkIDs, err = complytime.PlanFrameworkIDs(ap)
+		if err != nil {
+			return nil, err
+		}
+		if err := state.RecordPlan(apPath, frameworkIDs, nil); err != nil {
+			return nil, err
+		}
+		logger.Debug(fmt.Sprintf("Framework property was successfully read from the assessment plan: %v.", strings.Join(frameworkIDs, ", ")))
+	} else if err := state.VerifyPlan(apPath); err != nil {
+		if !errors.Is(err, complytime.ErrPlanModified) {
+			return nil, err
+		}
+		// The recorded frameworks may not be the ones of the modified plan.
+		var planErr error
+		frameworkIDs, planErr = complytime.PlanFrameworkIDs(ap)
+		if planErr != nil {
+			return nil, fmt.Errorf("%w and the plan has no framework property: run the plan command to regenerate it", err)
+		}
+		logger.Warn(fmt.Sprintf("%v. Using the frameworks of the plan, %s. Run the plan command to regenerate it.", err, strings.Join(frameworkIDs, ", ")))
+	}
+	opts.FrameworkID = frameworkIDs[0]
+	opts.FrameworkIDs = frameworkIDs
+	return state, nil
+}
+
+// writeWorkspaceState writes the workspace state.
+func writeWorkspaceState(workspace string, state *complytime.WorkspaceState) error {
+	if err := complytime.WriteWorkspaceState(workspace, state); err != nil {
+		return fmt.Errorf("error writing workspace state: %w", err)
+	}
+	logger.Debug(fmt.Sprintf("Workspace state written to %s.", complytime.WorkspaceStatePath(workspace)))
+	return nil
+}
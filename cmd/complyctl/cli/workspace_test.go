@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestLoadWorkspaceState(t *testing.T) {
+	workspace := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("testdata", assessmentPlanLocation))
+	require.NoError(t, err)
+	apPath := filepath.Join(workspace, assessmentPlanLocation)
+	require.NoError(t, os.WriteFile(apPath, data, 0600))
+
+	opts := &option.ComplyTime{UserWorkspace: workspace}
+	ap, _, err := loadPlan(opts, validation.NoopValidator{})
+	require.NoError(t, err)
+
+	// The test plan has no framework property to fall back to.
+	_, err = loadWorkspaceState(opts, ap, apPath)
+	require.EqualError(t, err, "error reading framework property from assessment plan")
+
+	state := complytime.NewWorkspaceState()
+	require.NoError(t, state.RecordPlan(apPath, []string{"example"}, nil))
+	require.NoError(t, writeWorkspaceState(workspace, state))
+	got, err := loadWorkspaceState(opts, ap, apPath)
+	require.NoError(t, err)
+	require.Equal(t, state, got)
+	require.Equal(t, "example", opts.FrameworkID)
+	require.Equal(t, []string{"example"}, opts.FrameworkIDs)
+
+	// A modified plan without a framework property must be regenerated.
+	require.NoError(t, os.WriteFile(apPath, append(data, '\n'), 0600))
+	_, err = loadWorkspaceState(opts, ap, apPath)
+	require.ErrorIs(t, err, complytime.ErrPlanModified)
+	require.ErrorContains(t, err, "the plan has no framework property: run the plan command to regenerate it")
+
+	// A modified plan is used with a warning, for the frameworks of the plan.
+	require.NoError(t, complytime.WritePlan(ap, apPath, "other", "another"))
+	ap, _, err = loadPlan(opts, validation.NoopValidator{})
+	require.NoError(t, err)
+	got, err = loadWorkspaceState(opts, ap, apPath)
+	require.NoError(t, err)
+	require.Equal(t, state, got)
+	require.Equal(t, "other", opts.FrameworkID)
+	require.Equal(t, []string{"other", "another"}, opts.FrameworkIDs)
+}
@@ -17,6 +17,6 @@ func main() {
 	complyctl := cli.New()
 	if err := complyctl.ExecuteContext(ctx); err != nil {
 		cli.Error(fmt.Sprintf("error running complyctl: %v", err))
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
@@ -14,6 +14,9 @@ import (
 // Common options for the complytctl CLI.
 type Common struct {
 	Debug bool
+	// ApplicationDirectory is the application directory set by the configuration files.
+	// When empty, the system or development application directory is used.
+	ApplicationDirectory string
 	Output
 }
 
@@ -30,6 +33,9 @@ func (o *Common) BindFlags(fs *pflag.FlagSet) {
 	fs.BoolVarP(&o.Debug, "debug", "d", false, "output debug logs")
 }
 
+// DefaultWorkspace is the workspace used when it is not set by flags or configuration files.
+const DefaultWorkspace = "./complytime"
+
 // ComplyTime options are configurations needed for the complyctl CLI to run.
 // They are less generic the Common options and would only be used in a subset of
 // commands.
@@ -38,13 +44,17 @@ type ComplyTime struct {
 	// by flags.
 	UserWorkspace string
 	// FrameworkID representing the compliance framework identifier associated with the artifacts in the workspace.
-	// It is set by workspace state or command positional arguments.
+	// It is set by workspace state or command positional arguments. In workspaces assessed against
+	// several frameworks, it is the primary framework.
 	FrameworkID string
+	// FrameworkIDs are all the frameworks associated with the artifacts in the workspace, starting with
+	// FrameworkID.
+	FrameworkIDs []string
 }
 
 // BindFlags populate ComplyTime options from user-specified flags.
 func (o *ComplyTime) BindFlags(fs *pflag.FlagSet) {
-	fs.StringVarP(&o.UserWorkspace, "workspace", "w", "./complytime", "workspace to use for artifact generation")
+	fs.StringVarP(&o.UserWorkspace, "workspace", "w", DefaultWorkspace, "workspace to use for artifact generation")
 }
 
 // ToPluginOptions returns global PluginOptions based on complytime Options.
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+	"github.com/goccy/go-yaml"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+const (
+	// SystemConfigFile is the system-wide configuration file.
+	SystemConfigFile = "/etc/complytime/complyctl.yaml"
+	// WorkspaceConfigFile is the name of the configuration file in a workspace.
+	WorkspaceConfigFile = "complyctl.yaml"
+
+	// LogFormatText writes log messages as styled text.
+	LogFormatText = "text"
+	// LogFormatJSON writes log messages as JSON objects.
+	LogFormatJSON = "json"
+)
+
+// Config holds the defaults for command line flags and settings read from
+// the configuration files. Flags set on the command line take precedence.
+type Config struct {
+	// Workspace is the default for the --workspace flag.
+	Workspace string `yaml:"workspace,omitempty"`
+	// PluginConfig is the default for the --plugin-config flag.
+	PluginConfig string `yaml:"pluginConfig,omitempty"`
+	// ApplicationDirectory is the directory containing the plugins, bundles and controls.
+	ApplicationDirectory string `yaml:"applicationDirectory,omitempty"`
+	// LogFormat is the format of the log messages, one of: text, json.
+	LogFormat string `yaml:"logFormat,omitempty"`
+	// Output is the default for the --output flag of the commands supporting it.
+	Output string `yaml:"output,omitempty"`
+	// Debug is the default for the --debug flag.
+	Debug *bool `yaml:"debug,omitempty"`
+	// History is the retention of the scan runs recorded in the workspace history.
+	History HistoryConfig `yaml:"history,omitempty"`
+}
+
+// HistoryConfig limits the scan runs kept in the workspace history.
+// Runs exceeding the limits are pruned after each scan.
+type HistoryConfig struct {
+	// Keep is the number of most recent runs to keep.
+	Keep int `yaml:"keep,omitempty"`
+	// MaxAge is the age after which runs are pruned, such as "30d" or "12h".
+	MaxAge string `yaml:"maxAge,omitempty"`
+}
+
+// Retention returns the retention of the workspace history.
+func (h HistoryConfig) Retention() (complytime.Retention, error) {
+	retention := complytime.Retention{Keep: h.Keep}
+	if h.Keep < 0 {
+		return retention, fmt.Errorf("invalid history keep %d: must not be negative", h.Keep)
+	}
+	if h.MaxAge != "" {
+		maxAge, err := complytime.ParseAge(h.MaxAge)
+		if err != nil {
+			return retention, fmt.Errorf("invalid history max age: %w", err)
+		}
+		retention.MaxAge = maxAge
+	}
+	return retention, nil
+}
+
+// UserConfigFile returns the path of the user configuration file.
+func UserConfigFile() string {
+	return filepath.Join(xdg.ConfigHome, "complytime", "config.yaml")
+}
+
+// LoadConfig reads the system, user and workspace configuration files,
+// with each file overriding the values of the previous ones.
+//
+// The workspace configuration file is read from the given workspace or, when
+// it is empty, from the configured or default workspace.
+func LoadConfig(workspace string) (Config, error) {
+	return loadConfig([]string{SystemConfigFile, UserConfigFile()}, workspace)
+}
+
+func loadConfig(files []string, workspace string) (Config, error) {
+	var config Config
+	for _, file := range files {
+		fileConfig, err := ReadConfig(file)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Merge(fileConfig)
+	}
+
+	if workspace == "" {
+		workspace = DefaultWorkspace
+		if config.Workspace != "" {
+			workspace = config.Workspace
+		}
+	}
+	workspaceFile := filepath.Join(workspace, WorkspaceConfigFile)
+	workspaceConfig, err := ReadConfig(workspaceFile)
+	if err != nil {
+		return Config{}, err
+	}
+	if workspaceConfig.Workspace != "" {
+		return Config{}, fmt.Errorf("invalid configuration file %s: the workspace cannot be set in a workspace configuration file", workspaceFile)
+	}
+	config.Merge(workspaceConfig)
+	return config, nil
+}
+
+// ReadConfig reads and validates a configuration file.
+// An empty Config is returned if the file does not exist.
+func ReadConfig(path string) (Config, error) {
+	var config Config
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return config, nil
+		}
+		return config, err
+	}
+	if err := yaml.UnmarshalWithOptions(data, &config, yaml.Strict()); err != nil {
+		return Config{}, fmt.Errorf("error reading configuration file %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Validate ensures the configured log format is supported and the history retention is valid.
+// The output format is validated by the commands, as they do not support the same formats.
+func (c *Config) Validate() error {
+	switch c.LogFormat {
+	case "", LogFormatText, LogFormatJSON:
+	default:
+		return fmt.Errorf("invalid log format %q: must be one of %q or %q", c.LogFormat, LogFormatText, LogFormatJSON)
+	}
+	_, err := c.History.Retention()
+	return err
+}
+
+// Merge overrides the values of the configuration with the values set in other.
+func (c *Config) Merge(other Config) {
+	if other.Workspace != "" {
+		c.Workspace = other.Workspace
+	}
+	if other.PluginConfig != "" {
+		c.PluginConfig = other.PluginConfig
+	}
+	if other.ApplicationDirectory != "" {
+		c.ApplicationDirectory = other.ApplicationDirectory
+	}
+	if other.LogFormat != "" {
+		c.LogFormat = other.LogFormat
+	}
+	if other.Output != "" {
+		c.Output = other.Output
+	}
+	if other.Debug != nil {
+		c.Debug = other.Debug
+	}
+	if other.History.Keep != 0 {
+		c.History.Keep = other.History.Keep
+	}
+	if other.History.MaxAge != "" {
+		c.History.MaxAge = other.History.MaxAge
+	}
+}
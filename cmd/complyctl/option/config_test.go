@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	systemFile := writeTestConfig(t, filepath.Join(tmpDir, "system.yaml"), "workspace: ./system\nlogFormat: json\noutput: yaml\n")
+	userFile := writeTestConfig(t, filepath.Join(tmpDir, "user.yaml"), "workspace: "+filepath.Join(tmpDir, "workspace")+"\npluginConfig: ./config.d\ndebug: false\n")
+	writeTestConfig(t, filepath.Join(tmpDir, "workspace", WorkspaceConfigFile), "output: json\ndebug: true\n")
+	missingFile := filepath.Join(tmpDir, "missing.yaml")
+
+	config, err := loadConfig([]string{systemFile, userFile, missingFile}, "")
+	require.NoError(t, err)
+	debug := true
+	require.Equal(t, Config{
+		Workspace:    filepath.Join(tmpDir, "workspace"),
+		PluginConfig: "./config.d",
+		LogFormat:    LogFormatJSON,
+		Output:       OutputFormatJSON,
+		Debug:        &debug,
+	}, config)
+
+	// The workspace given on the command line selects the workspace configuration file.
+	config, err = loadConfig([]string{systemFile, userFile}, filepath.Join(tmpDir, "other"))
+	require.NoError(t, err)
+	require.Equal(t, OutputFormatYAML, config.Output)
+	require.False(t, *config.Debug)
+}
+
+func TestReadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "Valid/AllFields",
+			content: "workspace: ./ws\npluginConfig: ./config.d\napplicationDirectory: /opt/complytime\nlogFormat: text\noutput: yaml\ndebug: true\nhistory:\n  keep: 10\n  maxAge: 30d\n",
+		},
+		{
+			name:    "Invalid/UnknownField",
+			content: "workspaces: ./ws\n",
+			wantErr: "unknown field \"workspaces\"",
+		},
+		{
+			name:    "Invalid/LogFormat",
+			content: "logFormat: xml\n",
+			wantErr: "invalid log format \"xml\"",
+		},
+		{
+			name:    "Invalid/HistoryKeep",
+			content: "history:\n  keep: -1\n",
+			wantErr: "invalid history keep -1",
+		},
+		{
+			name:    "Invalid/HistoryMaxAge",
+			content: "history:\n  maxAge: 1w\n",
+			wantErr: "invalid history max age",
+		},
+		{
+			// Output formats are validated by the commands, which support different formats.
+			name:    "Valid/CommandOutput",
+			content: "output: csv\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestConfig(t, filepath.Join(t.TempDir(), "config.yaml"), tt.content)
+			_, err := ReadConfig(path)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLoadConfigWorkspaceInWorkspaceFile(t *testing.T) {
+	workspace := t.TempDir()
+	writeTestConfig(t, filepath.Join(workspace, WorkspaceConfigFile), "workspace: ./other\n")
+	_, err := loadConfig(nil, workspace)
+	require.ErrorContains(t, err, "the workspace cannot be set in a workspace configuration file")
+}
+
+func writeTestConfig(t *testing.T, path, content string) string {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// OutputFormatJSON writes command output as indented JSON.
+	OutputFormatJSON = "json"
+	// OutputFormatYAML writes command output as YAML.
+	OutputFormatYAML = "yaml"
+)
+
+// Format options for commands that support machine-readable output.
+type Format struct {
+	// OutputFormat is the serialization format for command output.
+	// When empty, the command renders a table for the terminal.
+	OutputFormat string
+}
+
+// BindFlags populate Format options from user-specified flags.
+func (o *Format) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.OutputFormat, "output", "", "output format, one of: json, yaml")
+}
+
+// Validate ensures the selected output format is supported.
+func (o *Format) Validate() error {
+	switch o.OutputFormat {
+	case "", OutputFormatJSON, OutputFormatYAML:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q: must be one of %q or %q", o.OutputFormat, OutputFormatJSON, OutputFormatYAML)
+	}
+}
+
+// Structured returns true when a machine-readable output format is selected.
+func (o *Format) Structured() bool {
+	return o.OutputFormat != ""
+}
@@ -0,0 +1,488 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+)
+
+const (
+	// BundleRegistryFile is the file in the application directory that tracks installed bundles.
+	BundleRegistryFile = "installed-bundles.yaml"
+	// BundleManifestFile is the optional file in the root of a bundle with its name and version.
+	BundleManifestFile = "bundle.yaml"
+	// maxBundleFileSize limits the size of each file extracted from a bundle archive.
+	maxBundleFileSize = 100 << 20
+)
+
+// ErrBundleNotInstalled is returned when a bundle is not found in the bundle registry.
+var ErrBundleNotInstalled = errors.New("bundle not installed")
+
+// BundleManifest is the optional metadata file in the root of a bundle.
+type BundleManifest struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// InstalledBundle is a bundle installed into the application directory.
+type InstalledBundle struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+	// Frameworks are the framework IDs implemented by the bundle component definitions.
+	Frameworks []string `json:"frameworks" yaml:"frameworks"`
+	// Files are the installed file paths relative to the application directory.
+	Files       []string  `json:"files" yaml:"files"`
+	InstalledAt time.Time `json:"installedAt" yaml:"installedAt"`
+}
+
+// BundleValidationError is returned when the content of a bundle is not valid.
+type BundleValidationError struct {
+	Report ContentReport
+}
+
+func (e *BundleValidationError) Error() string {
+	return fmt.Sprintf("bundle content is not valid: %d error(s) found", len(e.Report.Errors))
+}
+
+// bundleRegistry is the content of the bundle registry file.
+type bundleRegistry struct {
+	Bundles []InstalledBundle `yaml:"bundles"`
+}
+
+// ListBundles returns the installed bundles sorted by name.
+func ListBundles(appDir ApplicationDirectory) ([]InstalledBundle, error) {
+	registry, err := readBundleRegistry(appDir)
+	if err != nil {
+		return nil, err
+	}
+	return registry.Bundles, nil
+}
+
+// InstallBundle installs the bundle from a tar.gz archive or a directory into the application directory.
+// The bundle contains component definitions under "bundles" and profiles and catalogs under "controls".
+// The content is validated before it is copied. Installing a bundle with the name of an installed
+// bundle replaces it. The installation is refused if the bundle implements a framework provided by
+// other content or would overwrite files that are not part of the bundle.
+func InstallBundle(appDir ApplicationDirectory, source string) (InstalledBundle, error) {
+	root, cleanup, err := openBundleSource(source)
+	if cleanup != nil {
+		defer cleanup()
+	}
+	if err != nil {
+		return InstalledBundle{}, err
+	}
+	bundle, err := readBundle(root, source)
+	if err != nil {
+		return InstalledBundle{}, err
+	}
+
+	stagedFiles := make([]string, 0, len(bundle.Files))
+	for _, file := range bundle.Files {
+		stagedFiles = append(stagedFiles, filepath.Join(root, file))
+	}
+	report := ValidateContent(appDir, stagedFiles)
+	if len(report.Errors) > 0 {
+		return InstalledBundle{}, &BundleValidationError{Report: report}
+	}
+
+	registry, err := readBundleRegistry(appDir)
+	if err != nil {
+		return InstalledBundle{}, err
+	}
+	previous, _ := registry.find(bundle.Name)
+	if err := checkBundleConflicts(appDir, registry, bundle, previous); err != nil {
+		return InstalledBundle{}, err
+	}
+
+	if previous != nil {
+		// Remove the files of the replaced version that are no longer part of the bundle.
+		if err := removeBundleFiles(appDir, excludeFiles(previous.Files, bundle.Files)); err != nil {
+			return InstalledBundle{}, err
+		}
+	}
+	for _, file := range bundle.Files {
+		if err := copyBundleFile(filepath.Join(root, file), filepath.Join(appDir.AppDir(), file)); err != nil {
+			return InstalledBundle{}, err
+		}
+	}
+
+	bundle.InstalledAt = time.Now().UTC()
+	registry.put(bundle)
+	return bundle, writeBundleRegistry(appDir, registry)
+}
+
+// RemoveBundle removes the files of an installed bundle from the application directory.
+func RemoveBundle(appDir ApplicationDirectory, name string) (InstalledBundle, error) {
+	registry, err := readBundleRegistry(appDir)
+	if err != nil {
+		return InstalledBundle{}, err
+	}
+	bundle, index := registry.find(name)
+	if bundle == nil {
+		return InstalledBundle{}, fmt.Errorf("%w: %s", ErrBundleNotInstalled, name)
+	}
+	removed := *bundle
+	if err := removeBundleFiles(appDir, removed.Files); err != nil {
+		return InstalledBundle{}, err
+	}
+	registry.Bundles = append(registry.Bundles[:index], registry.Bundles[index+1:]...)
+	return removed, writeBundleRegistry(appDir, registry)
+}
+
+// openBundleSource returns the root directory of a bundle source. Archives are extracted
+// to a temporary directory, which is removed by the returned cleanup function.
+func openBundleSource(source string) (string, func(), error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", nil, err
+	}
+	root := source
+	var cleanup func()
+	if !info.IsDir() {
+		if !strings.HasSuffix(source, ".tar.gz") && !strings.HasSuffix(source, ".tgz") {
+			return "", nil, fmt.Errorf("unsupported bundle %s: must be a directory or a .tar.gz archive", source)
+		}
+		root, err = os.MkdirTemp("", "complytime-bundle-")
+		if err != nil {
+			return "", nil, err
+		}
+		tempDir := root
+		cleanup = func() { _ = os.RemoveAll(tempDir) }
+		if err := extractBundleArchive(source, root); err != nil {
+			return "", cleanup, fmt.Errorf("error extracting bundle %s: %w", source, err)
+		}
+	}
+
+	// Archives commonly wrap the content in a single top-level directory.
+	if !isDir(filepath.Join(root, BundlesDir)) {
+		items, err := os.ReadDir(root)
+		if err != nil {
+			return "", cleanup, err
+		}
+		if len(items) == 1 && items[0].IsDir() {
+			root = filepath.Join(root, items[0].Name())
+		}
+	}
+	return root, cleanup, nil
+}
+
+// extractBundleArchive extracts the directories and regular files of a tar.gz archive
+// into dest. Entries outside of dest, links and other file types are refused.
+func extractBundleArchive(archive, dest string) error {
+	file, err := os.Open(filepath.Clean(archive))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(header.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("archive entry %s is outside of the bundle", header.Name)
+		}
+		target := filepath.Join(dest, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+				return err
+			}
+			if err := writeLimited(target, tarReader); err != nil {
+				return fmt.Errorf("archive entry %s: %w", header.Name, err)
+			}
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			return fmt.Errorf("archive entry %s is not a regular file or directory", header.Name)
+		}
+	}
+}
+
+// writeLimited writes the content of reader to path, failing if it exceeds maxBundleFileSize.
+func writeLimited(path string, reader io.Reader) error {
+	out, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	written, err := io.Copy(out, io.LimitReader(reader, maxBundleFileSize+1))
+	if err != nil {
+		return err
+	}
+	if written > maxBundleFileSize {
+		return fmt.Errorf("file is larger than %d bytes", maxBundleFileSize)
+	}
+	return nil
+}
+
+// readBundle returns the bundle metadata and content files found in root. The name and version
+// are read from the bundle manifest if present. Otherwise, the name is derived from the source
+// and the version is read from the component definition metadata.
+func readBundle(root, source string) (InstalledBundle, error) {
+	var bundle InstalledBundle
+	manifestData, err := os.ReadFile(filepath.Join(root, BundleManifestFile))
+	switch {
+	case err == nil:
+		var manifest BundleManifest
+		if err := yaml.Unmarshal(manifestData, &manifest); err != nil {
+			return bundle, fmt.Errorf("error reading %s: %w", BundleManifestFile, err)
+		}
+		bundle.Name = manifest.Name
+		bundle.Version = manifest.Version
+	case !errors.Is(err, os.ErrNotExist):
+		return bundle, err
+	}
+	if bundle.Name == "" {
+		bundle.Name = strings.TrimSuffix(strings.TrimSuffix(filepath.Base(filepath.Clean(source)), ".tgz"), ".tar.gz")
+	}
+	if strings.ContainsAny(bundle.Name, `/\`) || bundle.Name == "." || bundle.Name == ".." {
+		return bundle, fmt.Errorf("invalid bundle name %q", bundle.Name)
+	}
+
+	bundleDir := filepath.Join(root, BundlesDir)
+	items, err := os.ReadDir(bundleDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return bundle, err
+	}
+	var frameworks []string
+	for _, item := range items {
+		if item.IsDir() || !strings.HasSuffix(item.Name(), compDefSuffix) {
+			continue
+		}
+		compDef, err := readComponentDefinition(filepath.Join(bundleDir, item.Name()), validation.NoopValidator{})
+		if err != nil {
+			return bundle, err
+		}
+		if bundle.Version == "" {
+			bundle.Version = compDef.Metadata.Version
+		}
+		frameworks = append(frameworks, componentDefinitionFrameworks(*compDef)...)
+		bundle.Files = append(bundle.Files, filepath.Join(BundlesDir, item.Name()))
+	}
+	if len(bundle.Files) == 0 {
+		return bundle, fmt.Errorf("directory %s: %w", bundleDir, ErrNoComponentDefinitionsFound)
+	}
+	bundle.Frameworks = uniqueSorted(frameworks)
+
+	controlDir := filepath.Join(root, ControlsDir)
+	if isDir(controlDir) {
+		controlFiles, err := FindContentFiles([]string{controlDir})
+		if err != nil {
+			return bundle, err
+		}
+		for _, file := range controlFiles {
+			relPath, err := filepath.Rel(root, file)
+			if err != nil {
+				return bundle, err
+			}
+			bundle.Files = append(bundle.Files, relPath)
+		}
+	}
+	return bundle, nil
+}
+
+// checkBundleConflicts returns an error if the bundle implements a framework provided by a
+// component definition of another bundle or installed manually, or if a bundle file would
+// overwrite a file that is not part of the previously installed version of the bundle.
+func checkBundleConflicts(appDir ApplicationDirectory, registry *bundleRegistry, bundle InstalledBundle, previous *InstalledBundle) error {
+	owned := make(map[string]bool)
+	if previous != nil {
+		for _, file := range previous.Files {
+			owned[file] = true
+		}
+	}
+
+	items, err := os.ReadDir(appDir.BundleDir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, item := range items {
+		file := filepath.Join(BundlesDir, item.Name())
+		if item.IsDir() || !strings.HasSuffix(item.Name(), compDefSuffix) || owned[file] {
+			continue
+		}
+		compDef, err := readComponentDefinition(filepath.Join(appDir.AppDir(), file), validation.NoopValidator{})
+		if err != nil {
+			// Unreadable component definitions do not provide any framework.
+			continue
+		}
+		for _, framework := range componentDefinitionFrameworks(*compDef) {
+			if !slices.Contains(bundle.Frameworks, framework) {
+				continue
+			}
+			owner := "manually installed component definition " + file
+			if ownerBundle := registry.owner(file); ownerBundle != "" {
+				owner = "bundle " + ownerBundle
+			}
+			return fmt.Errorf("framework %s is already provided by %s", framework, owner)
+		}
+	}
+
+	for _, file := range bundle.Files {
+		if owned[file] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(appDir.AppDir(), file)); err == nil {
+			return fmt.Errorf("file %s already exists in %s and is not part of bundle %s", file, appDir.AppDir(), bundle.Name)
+		}
+	}
+	return nil
+}
+
+// componentDefinitionFrameworks returns the framework IDs of the control implementations in a component definition.
+func componentDefinitionFrameworks(compDef oscalTypes.ComponentDefinition) []string {
+	var frameworks []string
+	if compDef.Components == nil {
+		return nil
+	}
+	for _, component := range *compDef.Components {
+		if component.ControlImplementations == nil {
+			continue
+		}
+		for _, implementation := range *component.ControlImplementations {
+			if frameworkShortName, found := settings.GetFrameworkShortName(implementation); found {
+				frameworks = append(frameworks, frameworkShortName)
+			}
+		}
+	}
+	return uniqueSorted(frameworks)
+}
+
+// copyBundleFile copies a file into the application directory, creating parent directories as needed.
+func copyBundleFile(source, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+		return err
+	}
+	in, err := os.Open(filepath.Clean(source))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := writeLimited(dest, in); err != nil {
+		return fmt.Errorf("error installing %s: %w", dest, err)
+	}
+	return nil
+}
+
+// removeBundleFiles removes the given files relative to the application directory.
+// Files that no longer exist are ignored.
+func removeBundleFiles(appDir ApplicationDirectory, files []string) error {
+	for _, file := range files {
+		if err := os.Remove(filepath.Join(appDir.AppDir(), file)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func bundleRegistryPath(appDir ApplicationDirectory) string {
+	return filepath.Join(appDir.AppDir(), BundleRegistryFile)
+}
+
+// readBundleRegistry reads the bundle registry, which is empty if no bundle was installed.
+func readBundleRegistry(appDir ApplicationDirectory) (*bundleRegistry, error) {
+	registry := &bundleRegistry{}
+	data, err := os.ReadFile(bundleRegistryPath(appDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return registry, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, registry); err != nil {
+		return nil, fmt.Errorf("error reading bundle registry %s: %w", bundleRegistryPath(appDir), err)
+	}
+	return registry, nil
+}
+
+func writeBundleRegistry(appDir ApplicationDirectory, registry *bundleRegistry) error {
+	data, err := yaml.Marshal(registry)
+	if err != nil {
+		return fmt.Errorf("error marshalling bundle registry: %w", err)
+	}
+	return os.WriteFile(bundleRegistryPath(appDir), data, 0600)
+}
+
+// find returns the installed bundle with the given name and its index.
+func (r *bundleRegistry) find(name string) (*InstalledBundle, int) {
+	for i := range r.Bundles {
+		if r.Bundles[i].Name == name {
+			return &r.Bundles[i], i
+		}
+	}
+	return nil, -1
+}
+
+// put adds or replaces a bundle, keeping the bundles sorted by name.
+func (r *bundleRegistry) put(bundle InstalledBundle) {
+	if existing, _ := r.find(bundle.Name); existing != nil {
+		*existing = bundle
+		return
+	}
+	r.Bundles = append(r.Bundles, bundle)
+	sort.Slice(r.Bundles, func(i, j int) bool { return r.Bundles[i].Name < r.Bundles[j].Name })
+}
+
+// owner returns the name of the installed bundle with the given file.
+func (r *bundleRegistry) owner(file string) string {
+	for _, bundle := range r.Bundles {
+		if slices.Contains(bundle.Files, file) {
+			return bundle.Name
+		}
+	}
+	return ""
+}
+
+// excludeFiles returns the files not in exclude.
+func excludeFiles(files, exclude []string) []string {
+	var remaining []string
+	for _, file := range files {
+		if !slices.Contains(exclude, file) {
+			remaining = append(remaining, file)
+		}
+	}
+	return remaining
+}
+
+// uniqueSorted returns the sorted values without duplicates.
+func uniqueSorted(values []string) []string {
+	unique := slices.Clone(values)
+	slices.Sort(unique)
+	return slices.Compact(unique)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
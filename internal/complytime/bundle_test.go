@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testBundleDir = filepath.Join("testdata", "bundle")
+
+func TestInstallBundle(t *testing.T) {
+	appDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+
+	bundle, err := InstallBundle(appDir, testBundleDir)
+	require.NoError(t, err)
+	require.Equal(t, "example", bundle.Name)
+	require.Equal(t, "1.0.0", bundle.Version)
+	require.Equal(t, []string{"example"}, bundle.Frameworks)
+	wantFiles := []string{
+		filepath.Join(BundlesDir, "example-component-definition.json"),
+		filepath.Join(ControlsDir, "sample-catalog.json"),
+		filepath.Join(ControlsDir, "sample-profile.json"),
+	}
+	require.Equal(t, wantFiles, bundle.Files)
+	for _, file := range wantFiles {
+		require.FileExists(t, filepath.Join(appDir.AppDir(), file))
+	}
+
+	// Installing the same bundle again replaces it.
+	_, err = InstallBundle(appDir, testBundleDir)
+	require.NoError(t, err)
+	bundles, err := ListBundles(appDir)
+	require.NoError(t, err)
+	require.Len(t, bundles, 1)
+
+	// Another bundle cannot provide the same framework.
+	otherBundleDir := copyTestBundle(t)
+	require.NoError(t, os.WriteFile(filepath.Join(otherBundleDir, BundleManifestFile), []byte("name: other\nversion: 2.0.0\n"), 0600))
+	_, err = InstallBundle(appDir, otherBundleDir)
+	require.EqualError(t, err, "framework example is already provided by bundle example")
+
+	removed, err := RemoveBundle(appDir, "example")
+	require.NoError(t, err)
+	require.Equal(t, wantFiles, removed.Files)
+	for _, file := range wantFiles {
+		require.NoFileExists(t, filepath.Join(appDir.AppDir(), file))
+	}
+	bundles, err = ListBundles(appDir)
+	require.NoError(t, err)
+	require.Empty(t, bundles)
+
+	_, err = RemoveBundle(appDir, "example")
+	require.ErrorIs(t, err, ErrBundleNotInstalled)
+}
+
+func TestInstallBundleConflicts(t *testing.T) {
+	appDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+
+	// A manually installed component definition with the same framework.
+	compDef, err := os.ReadFile(filepath.Join(testBundleDir, BundlesDir, "example-component-definition.json"))
+	require.NoError(t, err)
+	manualCompDef := filepath.Join(appDir.BundleDir(), "manual-component-definition.json")
+	require.NoError(t, os.WriteFile(manualCompDef, compDef, 0600))
+	_, err = InstallBundle(appDir, testBundleDir)
+	require.EqualError(t, err, "framework example is already provided by manually installed component definition bundles/manual-component-definition.json")
+	require.NoError(t, os.Remove(manualCompDef))
+
+	// A file that is not part of the bundle is not overwritten.
+	require.NoError(t, os.WriteFile(filepath.Join(appDir.ControlDir(), "sample-catalog.json"), []byte("{}"), 0600))
+	_, err = InstallBundle(appDir, testBundleDir)
+	require.ErrorContains(t, err, "file controls/sample-catalog.json already exists")
+}
+
+func TestInstallBundleInvalidContent(t *testing.T) {
+	appDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+
+	bundleDir := copyTestBundle(t)
+	catalog, err := os.ReadFile(filepath.Join("testdata", "complytime", ControlsDir, "sample-catalog.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, ControlsDir, "sample-catalog.json"), catalog, 0600))
+
+	_, err = InstallBundle(appDir, bundleDir)
+	var validationErr *BundleValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.NotEmpty(t, validationErr.Report.Errors)
+	require.NoFileExists(t, filepath.Join(appDir.BundleDir(), "example-component-definition.json"))
+}
+
+func TestInstallBundleArchive(t *testing.T) {
+	appDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+
+	// The archive content is wrapped in a top-level directory and has no bundle manifest.
+	entries := make(map[string]string)
+	for _, file := range []string{
+		filepath.Join(BundlesDir, "example-component-definition.json"),
+		filepath.Join(ControlsDir, "sample-catalog.json"),
+		filepath.Join(ControlsDir, "sample-profile.json"),
+	} {
+		data, err := os.ReadFile(filepath.Join(testBundleDir, file))
+		require.NoError(t, err)
+		entries["example-content/"+filepath.ToSlash(file)] = string(data)
+	}
+	archive := writeTestArchive(t, "example-content.tar.gz", entries)
+
+	bundle, err := InstallBundle(appDir, archive)
+	require.NoError(t, err)
+	require.Equal(t, "example-content", bundle.Name)
+	require.Equal(t, "0.1.0", bundle.Version)
+	require.Len(t, bundle.Files, 3)
+
+	archive = writeTestArchive(t, "unsafe.tar.gz", map[string]string{"../evil.json": "{}"})
+	_, err = InstallBundle(appDir, archive)
+	require.ErrorContains(t, err, "archive entry ../evil.json is outside of the bundle")
+
+	_, err = InstallBundle(appDir, filepath.Join(testBundleDir, BundleManifestFile))
+	require.ErrorContains(t, err, "must be a directory or a .tar.gz archive")
+}
+
+// copyTestBundle copies the test bundle into a temporary directory.
+func copyTestBundle(t *testing.T) string {
+	dir := t.TempDir()
+	err := os.CopyFS(dir, os.DirFS(testBundleDir))
+	require.NoError(t, err)
+	return dir
+}
+
+// writeTestArchive writes a tar.gz archive with the given file names and content.
+func writeTestArchive(t *testing.T, name string, entries map[string]string) string {
+	path := filepath.Join(t.TempDir(), name)
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for entryName, content := range entries {
+		header := &tar.Header{Name: entryName, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		require.NoError(t, tarWriter.WriteHeader(header))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return path
+}
@@ -3,8 +3,12 @@
 package complytime
 
 import (
+	"errors"
+	"fmt"
+
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 )
 
@@ -18,3 +22,47 @@ func LoadCatalogSource(appDir ApplicationDirectory, catalogSource string, valida
 	defer sourceFile.Close()
 	return models.NewCatalog(sourceFile, validator)
 }
+
+// LoadFrameworkCatalog returns the OSCAL catalog for a given framework ID.
+// The framework profile is found from the component definitions in the application directory
+// and the catalog is loaded from the profile import.
+func LoadFrameworkCatalog(appDir ApplicationDirectory, frameworkID string, validator validation.Validator) (*oscalTypes.Catalog, error) {
+	compDefs, err := FindComponentDefinitions(appDir.BundleDir(), validator)
+	if err != nil {
+		return nil, err
+	}
+	profileHref := frameworkProfileHref(compDefs, frameworkID)
+	if profileHref == "" {
+		return nil, fmt.Errorf("no control source found for framework %s", frameworkID)
+	}
+	profile, err := LoadProfile(appDir, profileHref, validator)
+	if err != nil {
+		return nil, err
+	}
+	if len(profile.Imports) != 1 {
+		return nil, errors.New("profile imports must be one")
+	}
+	return LoadCatalogSource(appDir, profile.Imports[0].Href, validator)
+}
+
+// frameworkProfileHref returns the control source of the first control implementation
+// with a framework property matching the given framework ID.
+func frameworkProfileHref(compDefs []oscalTypes.ComponentDefinition, frameworkID string) string {
+	for _, compDef := range compDefs {
+		if compDef.Components == nil {
+			continue
+		}
+		for _, component := range *compDef.Components {
+			if component.ControlImplementations == nil {
+				continue
+			}
+			for _, implementation := range *component.ControlImplementations {
+				frameworkShortName, found := settings.GetFrameworkShortName(implementation)
+				if found && frameworkShortName == frameworkID {
+					return implementation.Source
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -50,10 +50,15 @@ type ApplicationDirectory struct {
 
 // NewApplicationDirectory returns a new ApplicationDirectory.
 //
+// The top-level directory is appDir, with plugin binaries in its plugin manifest
+// directory. When appDir is empty, the system or development directories are used.
 // Creation of the directories is optional using the `create` input.
 // If the application directories exist, this will not overwrite what is
 // existing.
-func NewApplicationDirectory(create bool) (ApplicationDirectory, error) {
+func NewApplicationDirectory(appDir string, create bool) (ApplicationDirectory, error) {
+	if appDir != "" {
+		return applicationDirectoryAt(filepath.Clean(appDir), "", create)
+	}
 	// When running local built complytime for development
 	if os.Getenv("COMPLYTIME_DEV_MODE") == "1" {
 		return newApplicationDirectory(xdg.DataHome, create)
@@ -67,14 +72,24 @@ func NewApplicationDirectory(create bool) (ApplicationDirectory, error) {
 // `create` input. If the application directories exist, this will not overwrite what is
 // existing.
 func newApplicationDirectory(rootDir string, create bool) (ApplicationDirectory, error) {
+	var pluginDir string
+	if rootDir == DataRootDir {
+		pluginDir = filepath.Join(PluginBinaryRootDir, ApplicationDir, PluginDir)
+	}
+	return applicationDirectoryAt(filepath.Join(rootDir, ApplicationDir), pluginDir, create)
+}
+
+// applicationDirectoryAt returns a new ApplicationDirectory with the given
+// top-level directory. When pluginDir is empty, plugin binaries are located
+// in the plugin manifest directory.
+func applicationDirectoryAt(appDir, pluginDir string, create bool) (ApplicationDirectory, error) {
 	applicationDir := ApplicationDirectory{
-		appDir: filepath.Join(rootDir, ApplicationDir),
+		appDir: appDir,
 	}
 	// Drop-in configuration to be supported in CPLYTM-716
 	applicationDir.pluginManifestDir = filepath.Join(applicationDir.appDir, PluginDir)
-	if rootDir == DataRootDir {
-		applicationDir.pluginDir = filepath.Join(PluginBinaryRootDir, ApplicationDir, PluginDir)
-	} else {
+	applicationDir.pluginDir = pluginDir
+	if pluginDir == "" {
 		applicationDir.pluginDir = applicationDir.pluginManifestDir
 	}
 	applicationDir.bundleDir = filepath.Join(applicationDir.appDir, BundlesDir)
@@ -41,6 +41,16 @@ func TestApplicationDirectory(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestNewApplicationDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir, err := NewApplicationDirectory(tmpDir, true)
+	require.NoError(t, err)
+	require.Equal(t, tmpDir, appDir.AppDir())
+	require.Equal(t, filepath.Join(tmpDir, "plugins"), appDir.PluginDir())
+	require.Equal(t, filepath.Join(tmpDir, "bundles"), appDir.BundleDir())
+	require.DirExists(t, appDir.ControlDir())
+}
+
 func TestFindComponentDefinitions(t *testing.T) {
 	compDefs, err := FindComponentDefinitions("testdata/complytime/bundles", validation.NoopValidator{})
 	require.NoError(t, err)
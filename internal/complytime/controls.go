@@ -21,12 +21,12 @@ import (
 type Framework struct {
 	// ID is the short-name identifier that is used to consistently
 	// represent a framework.
-	ID string
+	ID string `json:"id" yaml:"id"`
 	// Title is the human-readable name for a framework
-	Title string
+	Title string `json:"title" yaml:"title"`
 	// SupportedComponents define the component titles that implement the
 	// framework.
-	SupportedComponents []string
+	SupportedComponents []string `json:"supportedComponents" yaml:"supportedComponents"`
 }
 
 // LoadFrameworks returns all loaded framework information from a given application directory.
@@ -108,10 +108,23 @@ func LoadProfile(appDir ApplicationDirectory, controlSource string, validator va
 
 // findControlSource returns the correct control source file from the given control source or imported source.
 func findControlSource(appDir ApplicationDirectory, controlSource string) (io.ReadCloser, error) {
-	uri, err := url.ParseRequestURI(controlSource)
+	path, err := controlSourcePath(appDir, controlSource)
+	if err != nil {
+		return nil, err
+	}
+	sourceFile, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	return sourceFile, nil
+}
+
+// controlSourcePath returns the cleaned file path of a control source.
+func controlSourcePath(appDir ApplicationDirectory, controlSource string) (string, error) {
+	uri, err := url.ParseRequestURI(controlSource)
+	if err != nil {
+		return "", err
+	}
 
 	path := uri.Host + uri.Path
 	appDirPath := appDir.AppDir()
@@ -121,14 +134,9 @@ func findControlSource(appDir ApplicationDirectory, controlSource string) (io.Re
 	// A path relative to the root of the complytime application directory
 	if !filepath.IsAbs(path) {
 		if !strings.HasPrefix(path, ControlsDir+string(os.PathSeparator)) {
-			return nil, fmt.Errorf("got path %s, control source is expected to be under path %s", path, appDir.ControlDir())
+			return "", fmt.Errorf("got path %s, control source is expected to be under path %s", path, appDir.ControlDir())
 		}
 		path = filepath.Join(appDirPath, path)
 	}
-	cleanedPath := filepath.Clean(path)
-	sourceFile, err := os.Open(cleanedPath)
-	if err != nil {
-		return nil, err
-	}
-	return sourceFile, nil
+	return filepath.Clean(path), nil
 }
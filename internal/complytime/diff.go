@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import "sort"
+
+// Change values describe how the status of a rule or control changed between
+// two assessment results.
+const (
+	// ChangeNewFailure indicates an item that now fails or errors and did not before.
+	ChangeNewFailure = "new-failure"
+	// ChangeFixed indicates a failing or erroring item that now passes.
+	ChangeFixed = "fixed"
+	// ChangeNewlyPassing indicates an item that now passes and was not assessed or absent before.
+	ChangeNewlyPassing = "newly-passing"
+	// ChangeNotAssessed indicates an item that was assessed before but is no longer assessed.
+	ChangeNotAssessed = "not-assessed"
+	// ChangeRemoved indicates an item that is absent from the later result.
+	ChangeRemoved = "removed"
+	// ChangeOther indicates any other status change, such as fail to error.
+	ChangeOther = "changed"
+)
+
+// StatusChange records the status of a rule or control in two assessment results.
+// An empty status means the item is absent from that result.
+type StatusChange struct {
+	ID     string `json:"id" yaml:"id"`
+	Before string `json:"before" yaml:"before"`
+	After  string `json:"after" yaml:"after"`
+	Change string `json:"change" yaml:"change"`
+}
+
+// ResultsDiff contains the rules and controls that changed status between
+// two assessment results.
+type ResultsDiff struct {
+	Rules    []StatusChange `json:"rules" yaml:"rules"`
+	Controls []StatusChange `json:"controls" yaml:"controls"`
+}
+
+// Empty returns true when no status changes were found.
+func (d ResultsDiff) Empty() bool {
+	return len(d.Rules) == 0 && len(d.Controls) == 0
+}
+
+// DiffResults compares two summarized assessment results and returns the
+// status changes for rules and controls, sorted by ID.
+func DiffResults(before, after ResultsSummary) ResultsDiff {
+	beforeRules := make(map[string]string, len(before.Rules))
+	for _, rule := range before.Rules {
+		beforeRules[rule.RuleID] = rule.Status
+	}
+	afterRules := make(map[string]string, len(after.Rules))
+	for _, rule := range after.Rules {
+		afterRules[rule.RuleID] = rule.Status
+	}
+
+	beforeControls := make(map[string]string, len(before.Controls))
+	for _, control := range before.Controls {
+		beforeControls[control.ControlID] = control.Status
+	}
+	afterControls := make(map[string]string, len(after.Controls))
+	for _, control := range after.Controls {
+		afterControls[control.ControlID] = control.Status
+	}
+
+	return ResultsDiff{
+		Rules:    diffStatuses(beforeRules, afterRules),
+		Controls: diffStatuses(beforeControls, afterControls),
+	}
+}
+
+// diffStatuses returns the changed statuses between two sets of statuses indexed by ID.
+func diffStatuses(before, after map[string]string) []StatusChange {
+	ids := make(map[string]struct{})
+	for id := range before {
+		ids[id] = struct{}{}
+	}
+	for id := range after {
+		ids[id] = struct{}{}
+	}
+
+	changes := []StatusChange{}
+	for id := range ids {
+		beforeStatus, afterStatus := before[id], after[id]
+		if beforeStatus == afterStatus {
+			continue
+		}
+		changes = append(changes, StatusChange{
+			ID:     id,
+			Before: beforeStatus,
+			After:  afterStatus,
+			Change: classifyChange(beforeStatus, afterStatus),
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ID < changes[j].ID
+	})
+	return changes
+}
+
+// classifyChange returns the kind of change between two different statuses.
+func classifyChange(before, after string) string {
+	switch {
+	case after == "":
+		return ChangeRemoved
+	case IsFailing(after) && !IsFailing(before):
+		return ChangeNewFailure
+	case IsFailing(before) && after == StatusPass:
+		return ChangeFixed
+	case after == StatusPass:
+		return ChangeNewlyPassing
+	case after == StatusNotAssessed && (before == StatusPass || IsFailing(before)):
+		return ChangeNotAssessed
+	default:
+		return ChangeOther
+	}
+}
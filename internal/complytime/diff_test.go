@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffResults(t *testing.T) {
+	plan := testResultsPlan()
+	before := SummarizeResults(testResults(StatusFail, StatusPass), plan)
+	after := SummarizeResults(testResults(StatusPass, StatusFail), plan)
+
+	diff := DiffResults(before, after)
+	require.False(t, diff.Empty())
+	require.Equal(t, []StatusChange{
+		{ID: "rule-1", Before: StatusFail, After: StatusPass, Change: ChangeFixed},
+		{ID: "rule-2", Before: StatusPass, After: StatusFail, Change: ChangeNewFailure},
+	}, diff.Rules)
+	require.Equal(t, []StatusChange{
+		{ID: "control-2", Before: StatusPass, After: StatusFail, Change: ChangeNewFailure},
+	}, diff.Controls)
+
+	require.True(t, DiffResults(after, after).Empty())
+}
+
+func TestClassifyChange(t *testing.T) {
+	tests := []struct {
+		before string
+		after  string
+		want   string
+	}{
+		{before: StatusPass, after: StatusFail, want: ChangeNewFailure},
+		{before: "", after: StatusError, want: ChangeNewFailure},
+		{before: StatusNotAssessed, after: StatusFail, want: ChangeNewFailure},
+		{before: StatusError, after: StatusPass, want: ChangeFixed},
+		{before: StatusPass, after: StatusNotAssessed, want: ChangeNotAssessed},
+		{before: StatusFail, after: StatusError, want: ChangeOther},
+		{before: StatusNotAssessed, after: StatusPass, want: ChangeNewlyPassing},
+		{before: "", after: StatusPass, want: ChangeNewlyPassing},
+		{before: "", after: StatusFail, want: ChangeNewFailure},
+		{before: "", after: StatusNotAssessed, want: ChangeOther},
+		{before: StatusFail, after: "", want: ChangeRemoved},
+		{before: StatusError, after: "", want: ChangeRemoved},
+		{before: StatusPass, after: "", want: ChangeRemoved},
+		{before: StatusNotAssessed, after: "", want: ChangeRemoved},
+	}
+	for _, tt := range tests {
+		t.Run(tt.before+"->"+tt.after, func(t *testing.T) {
+			require.Equal(t, tt.want, classifyChange(tt.before, tt.after))
+		})
+	}
+}
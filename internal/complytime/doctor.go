@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/oscal-compass/oscal-sdk-go/models/components"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+)
+
+// Diagnostic status values.
+const (
+	DiagnosticOK      = "ok"
+	DiagnosticWarning = "warning"
+	DiagnosticFailed  = "fail"
+)
+
+// Diagnostic is the result of a single environment check with a suggested
+// fix when the check did not pass.
+type Diagnostic struct {
+	Name    string `json:"name" yaml:"name"`
+	Status  string `json:"status" yaml:"status"`
+	Message string `json:"message" yaml:"message"`
+	Fix     string `json:"fix,omitempty" yaml:"fix,omitempty"`
+}
+
+// DiagnoseDevMode reports which application directory is used based on the
+// value of the COMPLYTIME_DEV_MODE environment variable.
+func DiagnoseDevMode(value string, appDir ApplicationDirectory) Diagnostic {
+	diagnostic := Diagnostic{Name: "COMPLYTIME_DEV_MODE", Status: DiagnosticOK}
+	switch value {
+	case "1":
+		diagnostic.Message = fmt.Sprintf("development mode is enabled, using %s", appDir.AppDir())
+	case "":
+		diagnostic.Message = fmt.Sprintf("development mode is disabled, using %s and plugins from %s", appDir.AppDir(), appDir.PluginDir())
+	default:
+		diagnostic.Status = DiagnosticWarning
+		diagnostic.Message = fmt.Sprintf("COMPLYTIME_DEV_MODE is set to %q, only \"1\" enables development mode, using %s", value, appDir.AppDir())
+		diagnostic.Fix = "Run \"export COMPLYTIME_DEV_MODE=1\" to use the development directories or \"unset COMPLYTIME_DEV_MODE\" to use the system directories."
+	}
+	return diagnostic
+}
+
+// DiagnoseDirectories checks that all application directories exist.
+func DiagnoseDirectories(appDir ApplicationDirectory) []Diagnostic {
+	var diagnostics []Diagnostic
+	seen := make(map[string]bool)
+	for _, dir := range appDir.Dirs() {
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		diagnostic := Diagnostic{Name: "directory " + dir}
+		info, err := os.Stat(dir)
+		switch {
+		case err != nil:
+			diagnostic.Status = DiagnosticFailed
+			diagnostic.Message = fmt.Sprintf("unable to access directory: %v", err)
+			diagnostic.Fix = fmt.Sprintf("Create the directory with \"mkdir -p %s\" or install the complyctl packages that provide it.", dir)
+		case !info.IsDir():
+			diagnostic.Status = DiagnosticFailed
+			diagnostic.Message = "path exists but is not a directory"
+			diagnostic.Fix = fmt.Sprintf("Remove %s and create it as a directory.", dir)
+		default:
+			diagnostic.Status = DiagnosticOK
+			diagnostic.Message = "directory exists"
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// DiagnoseContent validates every component definition in the bundle directory and the
+// profiles and catalogs they reference. The valid component definitions are returned
+// for further checks.
+func DiagnoseContent(appDir ApplicationDirectory, validator validation.Validator) ([]Diagnostic, []oscalTypes.ComponentDefinition) {
+	items, err := os.ReadDir(appDir.BundleDir())
+	if err != nil {
+		return []Diagnostic{{
+			Name:    "component definitions",
+			Status:  DiagnosticFailed,
+			Message: fmt.Sprintf("unable to read bundle directory: %v", err),
+			Fix:     fmt.Sprintf("Install a content bundle into %s.", appDir.BundleDir()),
+		}}, nil
+	}
+
+	var diagnostics []Diagnostic
+	var compDefs []oscalTypes.ComponentDefinition
+	for _, item := range items {
+		if !strings.HasSuffix(item.Name(), compDefSuffix) {
+			continue
+		}
+		compDefPath := filepath.Join(appDir.BundleDir(), item.Name())
+		diagnostic := Diagnostic{Name: "component definition " + compDefPath}
+		compDef, err := readComponentDefinition(compDefPath, validator)
+		if err != nil {
+			diagnostic.Status = DiagnosticFailed
+			diagnostic.Message = err.Error()
+			diagnostic.Fix = fmt.Sprintf("Reinstall the content bundle or fix the OSCAL errors in %s.", compDefPath)
+		} else {
+			diagnostic.Status = DiagnosticOK
+			diagnostic.Message = "valid OSCAL component definition"
+			compDefs = append(compDefs, *compDef)
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	if len(diagnostics) == 0 {
+		return []Diagnostic{{
+			Name:    "component definitions",
+			Status:  DiagnosticFailed,
+			Message: fmt.Sprintf("no files named *-%s found in %s", compDefSuffix, appDir.BundleDir()),
+			Fix:     fmt.Sprintf("Install a content bundle with component definitions into %s.", appDir.BundleDir()),
+		}}, nil
+	}
+
+	for _, source := range controlSources(compDefs) {
+		profileDiagnostic := Diagnostic{Name: "profile " + source}
+		profile, err := LoadProfile(appDir, source, validator)
+		if err != nil {
+			profileDiagnostic.Status = DiagnosticFailed
+			profileDiagnostic.Message = err.Error()
+			profileDiagnostic.Fix = fmt.Sprintf("Install the profile under %s or correct the control implementation source in the component definition.", appDir.ControlDir())
+			diagnostics = append(diagnostics, profileDiagnostic)
+			continue
+		}
+		profileDiagnostic.Status = DiagnosticOK
+		profileDiagnostic.Message = "valid OSCAL profile"
+		diagnostics = append(diagnostics, profileDiagnostic)
+
+		for _, imp := range profile.Imports {
+			catalogDiagnostic := Diagnostic{Name: "catalog " + imp.Href}
+			if _, err := LoadCatalogSource(appDir, imp.Href, validator); err != nil {
+				catalogDiagnostic.Status = DiagnosticFailed
+				catalogDiagnostic.Message = err.Error()
+				catalogDiagnostic.Fix = fmt.Sprintf("Install the catalog under %s or correct the import in profile %s.", appDir.ControlDir(), source)
+			} else {
+				catalogDiagnostic.Status = DiagnosticOK
+				catalogDiagnostic.Message = "valid OSCAL catalog"
+			}
+			diagnostics = append(diagnostics, catalogDiagnostic)
+		}
+	}
+	return diagnostics, compDefs
+}
+
+// DiagnosePlugins checks that each plugin named by a validation component has a
+// manifest and an executable matching the manifest checksum.
+func DiagnosePlugins(appDir ApplicationDirectory, compDefs []oscalTypes.ComponentDefinition) []Diagnostic {
+	pluginIDs := ValidationPluginIDs(compDefs)
+	if len(pluginIDs) == 0 {
+		return []Diagnostic{{
+			Name:    "plugins",
+			Status:  DiagnosticWarning,
+			Message: "no validation components found in the component definitions",
+			Fix:     "Install a content bundle with a validation component for each plugin.",
+		}}
+	}
+
+	var diagnostics []Diagnostic
+	for _, pluginID := range pluginIDs {
+		diagnostic := Diagnostic{Name: "plugin " + pluginID}
+		manifest, err := VerifyPlugin(appDir, pluginID)
+		if err != nil {
+			diagnostic.Status = DiagnosticFailed
+			diagnostic.Message = err.Error()
+			diagnostic.Fix = pluginFix(appDir, pluginID, manifest.ExecutablePath, err)
+		} else {
+			diagnostic.Status = DiagnosticOK
+			diagnostic.Message = fmt.Sprintf("manifest and executable %s are valid", manifest.ExecutablePath)
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// ValidationPluginIDs returns the sorted plugin IDs named by the validation components
+// in the given component definitions.
+func ValidationPluginIDs(compDefs []oscalTypes.ComponentDefinition) []string {
+	ids := make(map[string]struct{})
+	for _, compDef := range compDefs {
+		if compDef.Components == nil {
+			continue
+		}
+		for _, component := range *compDef.Components {
+			if component.Type != string(components.Validation) {
+				continue
+			}
+			ids[strings.ToLower(strings.TrimSpace(component.Title))] = struct{}{}
+		}
+	}
+	pluginIDs := make([]string, 0, len(ids))
+	for id := range ids {
+		pluginIDs = append(pluginIDs, id)
+	}
+	sort.Strings(pluginIDs)
+	return pluginIDs
+}
+
+// pluginFix returns a suggested fix for a plugin verification error.
+func pluginFix(appDir ApplicationDirectory, pluginID, executablePath string, err error) string {
+	manifestDir := appDir.PluginManifestDir()
+	switch {
+	case errors.Is(err, ErrPluginManifestNotFound):
+		return fmt.Sprintf("Install the %s plugin or add its manifest to %s.", pluginID, manifestDir)
+	case errors.Is(err, ErrPluginManifestInvalid):
+		return fmt.Sprintf("Reinstall the %s plugin or fix its manifest in %s.", pluginID, manifestDir)
+	case errors.Is(err, ErrPluginExecutable):
+		return fmt.Sprintf("Install the plugin executable under %s and make it executable with \"chmod +x\".", appDir.PluginDir())
+	case errors.Is(err, ErrPluginChecksum):
+		return fmt.Sprintf("Reinstall the %s plugin or update \"sha256\" in its manifest in %s with the output of \"sha256sum %s\".", pluginID, manifestDir, executablePath)
+	default:
+		return fmt.Sprintf("Check the permissions of %s and %s.", manifestDir, appDir.PluginDir())
+	}
+}
+
+// controlSources returns the sorted unique control sources referenced by the
+// control implementations of the given component definitions.
+func controlSources(compDefs []oscalTypes.ComponentDefinition) []string {
+	sources := make(map[string]struct{})
+	for _, compDef := range compDefs {
+		if compDef.Components == nil {
+			continue
+		}
+		for _, component := range *compDef.Components {
+			if component.ControlImplementations == nil {
+				continue
+			}
+			for _, implementation := range *component.ControlImplementations {
+				sources[implementation.Source] = struct{}{}
+			}
+		}
+	}
+	sorted := make([]string, 0, len(sources))
+	for source := range sources {
+		sorted = append(sorted, source)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// readComponentDefinition reads and validates a single component definition file.
+func readComponentDefinition(path string, validator validation.Validator) (*oscalTypes.ComponentDefinition, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	compDef, err := models.NewComponentDefinition(file, validator)
+	if err != nil {
+		return nil, err
+	}
+	if compDef == nil {
+		return nil, fmt.Errorf("could not load component definition from %s", path)
+	}
+	return compDef, nil
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseDevMode(t *testing.T) {
+	appDir, err := newApplicationDirectory("testdata", false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		value      string
+		wantStatus string
+	}{
+		{value: "", wantStatus: DiagnosticOK},
+		{value: "1", wantStatus: DiagnosticOK},
+		{value: "true", wantStatus: DiagnosticWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			diagnostic := DiagnoseDevMode(tt.value, appDir)
+			require.Equal(t, tt.wantStatus, diagnostic.Status)
+			require.Contains(t, diagnostic.Message, appDir.AppDir())
+			if tt.wantStatus == DiagnosticOK {
+				require.Empty(t, diagnostic.Fix)
+			} else {
+				require.NotEmpty(t, diagnostic.Fix)
+			}
+		})
+	}
+}
+
+func TestDiagnoseDirectories(t *testing.T) {
+	appDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+
+	diagnostics := DiagnoseDirectories(appDir)
+	// The plugin and plugin manifest directories are the same outside of the system install.
+	require.Len(t, diagnostics, 4)
+	for _, diagnostic := range diagnostics {
+		require.Equal(t, DiagnosticOK, diagnostic.Status)
+	}
+
+	require.NoError(t, os.RemoveAll(appDir.BundleDir()))
+	require.NoError(t, os.WriteFile(appDir.ControlDir()+"-file", nil, 0600))
+	require.NoError(t, os.RemoveAll(appDir.ControlDir()))
+	require.NoError(t, os.Rename(appDir.ControlDir()+"-file", appDir.ControlDir()))
+
+	diagnostics = DiagnoseDirectories(appDir)
+	require.Equal(t, DiagnosticFailed, diagnostics[2].Status)
+	require.Contains(t, diagnostics[2].Fix, "mkdir -p "+appDir.BundleDir())
+	require.Equal(t, DiagnosticFailed, diagnostics[3].Status)
+	require.Equal(t, "path exists but is not a directory", diagnostics[3].Message)
+}
+
+func TestDiagnoseContent(t *testing.T) {
+	appDir, err := newApplicationDirectory("testdata", false)
+	require.NoError(t, err)
+
+	diagnostics, compDefs := DiagnoseContent(appDir, validation.NoopValidator{})
+	require.Len(t, compDefs, 1)
+	require.Equal(t, []string{
+		"component definition " + filepath.Join("testdata", "complytime", "bundles", "example-component-definition.json"),
+		"profile file://controls/sample-profile.json",
+		"catalog file://controls/sample-catalog.json",
+	}, diagnosticNames(diagnostics))
+	for _, diagnostic := range diagnostics {
+		require.Equal(t, DiagnosticOK, diagnostic.Status)
+	}
+
+	emptyDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+	diagnostics, compDefs = DiagnoseContent(emptyDir, validation.NoopValidator{})
+	require.Empty(t, compDefs)
+	require.Len(t, diagnostics, 1)
+	require.Equal(t, DiagnosticFailed, diagnostics[0].Status)
+
+	require.NoError(t, os.WriteFile(filepath.Join(emptyDir.BundleDir(), "bad-component-definition.json"), []byte("{"), 0600))
+	diagnostics, _ = DiagnoseContent(emptyDir, validation.NoopValidator{})
+	require.Len(t, diagnostics, 1)
+	require.Equal(t, DiagnosticFailed, diagnostics[0].Status)
+	require.Contains(t, diagnostics[0].Fix, "bad-component-definition.json")
+}
+
+func TestDiagnosePlugins(t *testing.T) {
+	appDir, err := newApplicationDirectory("testdata", false)
+	require.NoError(t, err)
+
+	diagnostics := DiagnosePlugins(appDir, nil)
+	require.Len(t, diagnostics, 1)
+	require.Equal(t, DiagnosticWarning, diagnostics[0].Status)
+
+	_, compDefs := DiagnoseContent(appDir, validation.NoopValidator{})
+	diagnostics = DiagnosePlugins(appDir, compDefs)
+	require.Len(t, diagnostics, 1)
+	require.Equal(t, "plugin myplugin", diagnostics[0].Name)
+	require.Equal(t, DiagnosticFailed, diagnostics[0].Status)
+	require.Contains(t, diagnostics[0].Fix, "Install the myplugin plugin")
+}
+
+func TestValidationPluginIDs(t *testing.T) {
+	compDefs := []oscalTypes.ComponentDefinition{
+		{
+			Components: &[]oscalTypes.DefinedComponent{
+				{Type: "validation", Title: "OpenSCAP "},
+				{Type: "software", Title: "RHEL"},
+			},
+		},
+		{
+			Components: &[]oscalTypes.DefinedComponent{
+				{Type: "validation", Title: "ampel"},
+				{Type: "validation", Title: "openscap"},
+			},
+		},
+		{},
+	}
+	require.Equal(t, []string{"ampel", "openscap"}, ValidationPluginIDs(compDefs))
+}
+
+func diagnosticNames(diagnostics []Diagnostic) []string {
+	var names []string
+	for _, diagnostic := range diagnostics {
+		names = append(names, diagnostic.Name)
+	}
+	return names
+}
@@ -0,0 +1,431 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// FrameworkPlan is an assessment plan generated for a single framework.
+type FrameworkPlan struct {
+	FrameworkID string
+	Plan        *oscalTypes.AssessmentPlan
+}
+
+// FrameworkResult summarizes the rule results for the controls of a single framework
+// of an assessment plan with several frameworks.
+type FrameworkResult struct {
+	FrameworkID string `json:"framework" yaml:"framework"`
+	// Controls are the control results of the framework sorted by control ID.
+	Controls []ControlResult `json:"controls" yaml:"controls"`
+	// RuleCounts counts the results of the rules assessing the framework by status.
+	RuleCounts StatusCounts `json:"ruleCounts" yaml:"ruleCounts"`
+	// ControlCounts counts the control results of the framework by status.
+	ControlCounts StatusCounts `json:"controlCounts" yaml:"controlCounts"`
+}
+
+// MergePlans merges the assessment plans of several frameworks into a single plan assessing all of them.
+//
+// The control selections of each plan are tagged with the framework property. Activities for the same
+// rule are merged into the activity of the first plan, with the control selections of all the frameworks,
+// so that the rules shared by the frameworks are assessed once. The metadata of the first plan is kept,
+// and its tasks, subjects and components are extended with those of the other plans.
+func MergePlans(plans []FrameworkPlan, logger hclog.Logger) (*oscalTypes.AssessmentPlan, error) {
+	if len(plans) == 0 {
+		return nil, errors.New("no assessment plans to merge")
+	}
+	for _, frameworkPlan := range plans {
+		tagPlanFramework(frameworkPlan.Plan, frameworkPlan.FrameworkID)
+	}
+
+	merged := plans[0].Plan
+	if merged.LocalDefinitions == nil {
+		merged.LocalDefinitions = &oscalTypes.LocalDefinitions{}
+	}
+	if merged.LocalDefinitions.Activities == nil {
+		merged.LocalDefinitions.Activities = &[]oscalTypes.Activity{}
+	}
+	activityIndex := make(map[string]int)
+	for i, activity := range *merged.LocalDefinitions.Activities {
+		if activity.Title != "" {
+			activityIndex[activity.Title] = i
+		}
+	}
+
+	for _, frameworkPlan := range plans[1:] {
+		plan := frameworkPlan.Plan
+		merged.ReviewedControls.ControlSelections = append(merged.ReviewedControls.ControlSelections, plan.ReviewedControls.ControlSelections...)
+
+		// mergedUUIDs maps the UUIDs of merged activities to the UUIDs of the activities they were merged into.
+		mergedUUIDs := make(map[string]string)
+		if plan.LocalDefinitions != nil && plan.LocalDefinitions.Activities != nil {
+			for _, activity := range *plan.LocalDefinitions.Activities {
+				i, found := activityIndex[activity.Title]
+				if !found || activity.Title == "" {
+					if activity.Title != "" {
+						activityIndex[activity.Title] = len(*merged.LocalDefinitions.Activities)
+					}
+					*merged.LocalDefinitions.Activities = append(*merged.LocalDefinitions.Activities, activity)
+					continue
+				}
+				existing := &(*merged.LocalDefinitions.Activities)[i]
+				mergeActivity(existing, activity, frameworkPlan.FrameworkID, logger)
+				mergedUUIDs[activity.UUID] = existing.UUID
+			}
+		}
+		if plan.LocalDefinitions != nil && plan.LocalDefinitions.Components != nil {
+			merged.LocalDefinitions.Components = mergeComponents(merged.LocalDefinitions.Components, *plan.LocalDefinitions.Components)
+		}
+
+		if plan.AssessmentAssets != nil {
+			if merged.AssessmentAssets == nil {
+				merged.AssessmentAssets = &oscalTypes.AssessmentAssets{}
+			}
+			if plan.AssessmentAssets.Components != nil {
+				merged.AssessmentAssets.Components = mergeComponents(merged.AssessmentAssets.Components, *plan.AssessmentAssets.Components)
+			}
+			mergePlatforms(merged.AssessmentAssets, plan.AssessmentAssets.AssessmentPlatforms)
+		}
+		if plan.AssessmentSubjects != nil {
+			if merged.AssessmentSubjects == nil {
+				merged.AssessmentSubjects = &[]oscalTypes.AssessmentSubject{}
+			}
+			*merged.AssessmentSubjects = mergeSubjects(*merged.AssessmentSubjects, *plan.AssessmentSubjects)
+		}
+		if plan.Tasks != nil {
+			if merged.Tasks == nil {
+				merged.Tasks = &[]oscalTypes.Task{}
+			}
+			mergeTasks(merged.Tasks, *plan.Tasks, mergedUUIDs)
+		}
+	}
+	return merged, nil
+}
+
+// tagPlanFramework sets the framework property on the control selections of the plan and its activities.
+func tagPlanFramework(plan *oscalTypes.AssessmentPlan, frameworkID string) {
+	tagSelections(plan.ReviewedControls.ControlSelections, frameworkID)
+	if plan.LocalDefinitions == nil || plan.LocalDefinitions.Activities == nil {
+		return
+	}
+	for _, activity := range *plan.LocalDefinitions.Activities {
+		if activity.RelatedControls != nil {
+			tagSelections(activity.RelatedControls.ControlSelections, frameworkID)
+		}
+		if activity.Steps == nil {
+			continue
+		}
+		for _, step := range *activity.Steps {
+			if step.ReviewedControls != nil {
+				tagSelections(step.ReviewedControls.ControlSelections, frameworkID)
+			}
+		}
+	}
+}
+
+func tagSelections(selections []oscalTypes.AssessedControls, frameworkID string) {
+	for i := range selections {
+		selection := &selections[i]
+		if selection.Props == nil {
+			selection.Props = &[]oscalTypes.Property{}
+		}
+		*selection.Props = append(*selection.Props, oscalTypes.Property{
+			Name:  extensions.FrameworkProp,
+			Value: frameworkID,
+			Ns:    extensions.TrestleNameSpace,
+		})
+	}
+}
+
+// selectionInFramework returns whether a control selection belongs to the framework. Selections of
+// single framework plans have no framework property and belong to any framework. All selections
+// belong to an empty framework ID.
+func selectionInFramework(selection oscalTypes.AssessedControls, frameworkID string) bool {
+	if frameworkID == "" || selection.Props == nil {
+		return true
+	}
+	prop, found := extensions.GetTrestleProp(extensions.FrameworkProp, *selection.Props)
+	return !found || prop.Value == frameworkID
+}
+
+// activityInScope returns whether an activity selects controls. Skipped activities do not.
+func activityInScope(activity oscalTypes.Activity) bool {
+	if activity.RelatedControls == nil {
+		return false
+	}
+	for _, selection := range activity.RelatedControls.ControlSelections {
+		if selection.IncludeControls != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeActivity merges the control selections of an activity for the same rule into an existing activity.
+// The activity is in scope as soon as one of the frameworks selects controls for it, and takes its
+// parameter values from the first framework it is in scope for.
+func mergeActivity(existing *oscalTypes.Activity, activity oscalTypes.Activity, frameworkID string, logger hclog.Logger) {
+	if !activityInScope(activity) {
+		return
+	}
+	if !activityInScope(*existing) {
+		existing.RelatedControls = activity.RelatedControls
+		existing.Steps = activity.Steps
+		existing.Props = activity.Props
+		return
+	}
+	for _, parameter := range activityParameters(activity) {
+		for _, existingParameter := range activityParameters(*existing) {
+			if existingParameter.Name == parameter.Name && existingParameter.Value != parameter.Value {
+				logger.Warn("Conflicting values for rule parameter, keeping the first one", "parameter", parameter.Name, "rule", activity.Title, "framework", frameworkID, "value", existingParameter.Value)
+			}
+		}
+	}
+	for _, selection := range activity.RelatedControls.ControlSelections {
+		if selection.IncludeControls != nil {
+			existing.RelatedControls.ControlSelections = append(existing.RelatedControls.ControlSelections, selection)
+		}
+	}
+	if existing.Steps == nil || activity.Steps == nil {
+		return
+	}
+	for _, step := range *activity.Steps {
+		if step.ReviewedControls == nil {
+			continue
+		}
+		i := slices.IndexFunc(*existing.Steps, func(s oscalTypes.Step) bool { return s.Title == step.Title })
+		if i < 0 || (*existing.Steps)[i].ReviewedControls == nil {
+			continue
+		}
+		target := (*existing.Steps)[i].ReviewedControls
+		target.ControlSelections = append(target.ControlSelections, step.ReviewedControls.ControlSelections...)
+	}
+}
+
+// mergeComponents adds the components missing from the existing components, by UUID.
+func mergeComponents(existing *[]oscalTypes.SystemComponent, components []oscalTypes.SystemComponent) *[]oscalTypes.SystemComponent {
+	if existing == nil {
+		existing = &[]oscalTypes.SystemComponent{}
+	}
+	for _, component := range components {
+		if !slices.ContainsFunc(*existing, func(c oscalTypes.SystemComponent) bool { return c.UUID == component.UUID }) {
+			*existing = append(*existing, component)
+		}
+	}
+	return existing
+}
+
+// mergePlatforms adds the components used by the platforms to the first platform of the assets.
+func mergePlatforms(assets *oscalTypes.AssessmentAssets, platforms []oscalTypes.AssessmentPlatform) {
+	for _, platform := range platforms {
+		if len(assets.AssessmentPlatforms) == 0 {
+			assets.AssessmentPlatforms = append(assets.AssessmentPlatforms, platform)
+			continue
+		}
+		if platform.UsesComponents == nil {
+			continue
+		}
+		target := &assets.AssessmentPlatforms[0]
+		if target.UsesComponents == nil {
+			target.UsesComponents = &[]oscalTypes.UsesComponent{}
+		}
+		for _, component := range *platform.UsesComponents {
+			if !slices.ContainsFunc(*target.UsesComponents, func(c oscalTypes.UsesComponent) bool { return c.ComponentUuid == component.ComponentUuid }) {
+				*target.UsesComponents = append(*target.UsesComponents, component)
+			}
+		}
+	}
+}
+
+// mergeSubjects adds the subjects included by the given subjects to the existing subjects of the same type.
+func mergeSubjects(existing []oscalTypes.AssessmentSubject, subjects []oscalTypes.AssessmentSubject) []oscalTypes.AssessmentSubject {
+	for _, subject := range subjects {
+		i := slices.IndexFunc(existing, func(s oscalTypes.AssessmentSubject) bool { return s.Type == subject.Type })
+		if i < 0 {
+			existing = append(existing, subject)
+			continue
+		}
+		if subject.IncludeSubjects == nil {
+			continue
+		}
+		target := &existing[i]
+		if target.IncludeSubjects == nil {
+			target.IncludeSubjects = &[]oscalTypes.SelectSubjectById{}
+		}
+		for _, include := range *subject.IncludeSubjects {
+			if !slices.ContainsFunc(*target.IncludeSubjects, func(s oscalTypes.SelectSubjectById) bool { return s.SubjectUuid == include.SubjectUuid }) {
+				*target.IncludeSubjects = append(*target.IncludeSubjects, include)
+			}
+		}
+	}
+	return existing
+}
+
+// mergeTasks merges the tasks into the existing tasks with the same title. Associated activities
+// refer to the activities they were merged into, given by mergedUUIDs.
+func mergeTasks(existing *[]oscalTypes.Task, tasks []oscalTypes.Task, mergedUUIDs map[string]string) {
+	for _, task := range tasks {
+		if task.AssociatedActivities != nil {
+			for i := range *task.AssociatedActivities {
+				associated := &(*task.AssociatedActivities)[i]
+				if uuid, found := mergedUUIDs[associated.ActivityUuid]; found {
+					associated.ActivityUuid = uuid
+				}
+			}
+		}
+		i := slices.IndexFunc(*existing, func(t oscalTypes.Task) bool { return t.Title == task.Title })
+		if i < 0 {
+			*existing = append(*existing, task)
+			continue
+		}
+		target := &(*existing)[i]
+		if task.Subjects != nil {
+			if target.Subjects == nil {
+				target.Subjects = &[]oscalTypes.AssessmentSubject{}
+			}
+			*target.Subjects = mergeSubjects(*target.Subjects, *task.Subjects)
+		}
+		if task.AssociatedActivities == nil {
+			continue
+		}
+		if target.AssociatedActivities == nil {
+			target.AssociatedActivities = &[]oscalTypes.AssociatedActivity{}
+		}
+		for _, associated := range *task.AssociatedActivities {
+			j := slices.IndexFunc(*target.AssociatedActivities, func(a oscalTypes.AssociatedActivity) bool { return a.ActivityUuid == associated.ActivityUuid })
+			if j < 0 {
+				*target.AssociatedActivities = append(*target.AssociatedActivities, associated)
+				continue
+			}
+			(*target.AssociatedActivities)[j].Subjects = mergeSubjects((*target.AssociatedActivities)[j].Subjects, associated.Subjects)
+		}
+	}
+}
+
+// frameworkControl is a control of a framework. Frameworks tailored from the same catalog,
+// such as a company baseline tailored from CIS, have controls with the same ID.
+type frameworkControl struct {
+	frameworkID string
+	controlID   string
+}
+
+// planFrameworkControls returns the controls selected for each rule in the assessment plan for
+// the given frameworks, using the framework property of the control selections.
+func planFrameworkControls(plan *oscalTypes.AssessmentPlan, frameworkIDs []string) map[string][]frameworkControl {
+	ruleControls := make(map[string][]frameworkControl)
+	for _, frameworkID := range frameworkIDs {
+		frameworkRuleControls, _ := planRuleMappings(plan, frameworkID)
+		for ruleID, controlIDs := range frameworkRuleControls {
+			for _, controlID := range controlIDs {
+				ruleControls[ruleID] = append(ruleControls[ruleID], frameworkControl{frameworkID: frameworkID, controlID: controlID})
+			}
+		}
+	}
+	return ruleControls
+}
+
+// summarizeFrameworks breaks down the results summary by framework, mapping
+// the rules to the controls selected for each framework in the plan.
+func summarizeFrameworks(summary ResultsSummary, plan *oscalTypes.AssessmentPlan, frameworkIDs []string) []FrameworkResult {
+	ruleControls := planFrameworkControls(plan, frameworkIDs)
+	frameworks := make([]FrameworkResult, len(frameworkIDs))
+	frameworkIndex := make(map[string]int)
+	for i, frameworkID := range frameworkIDs {
+		frameworks[i] = FrameworkResult{FrameworkID: frameworkID, Controls: []ControlResult{}}
+		frameworkIndex[frameworkID] = i
+	}
+
+	controlsByKey := make(map[frameworkControl]*ControlResult)
+	for _, rule := range summary.Rules {
+		counted := make(map[string]bool)
+		for _, key := range ruleControls[rule.RuleID] {
+			if !counted[key.frameworkID] {
+				frameworks[frameworkIndex[key.frameworkID]].RuleCounts.Add(rule.Status)
+				counted[key.frameworkID] = true
+			}
+			control, ok := controlsByKey[key]
+			if !ok {
+				control = &ControlResult{ControlID: key.controlID, Status: StatusNotAssessed}
+				controlsByKey[key] = control
+			}
+			control.Rules = append(control.Rules, rule.RuleID)
+			control.Counts.Add(rule.Status)
+			control.Status = worseStatus(control.Status, rule.Status)
+		}
+	}
+	for key, control := range controlsByKey {
+		framework := &frameworks[frameworkIndex[key.frameworkID]]
+		framework.ControlCounts.Add(control.Status)
+		framework.Controls = append(framework.Controls, *control)
+	}
+	for _, framework := range frameworks {
+		sort.Slice(framework.Controls, func(i, j int) bool {
+			return framework.Controls[i].ControlID < framework.Controls[j].ControlID
+		})
+	}
+	return frameworks
+}
+
+// FrameworkAssessmentResults returns a copy of the assessment results with only the observations of
+// the rules in scope of the framework, and the findings for the controls selected for the framework
+// in the assessment plan. Findings keep the related observations of the rules mapped to their control
+// in the framework, as frameworks may share control IDs.
+func FrameworkAssessmentResults(assessmentResults *oscalTypes.AssessmentResults, plan *oscalTypes.AssessmentPlan, frameworkID string) *oscalTypes.AssessmentResults {
+	ruleControls := planFrameworkControls(plan, []string{frameworkID})
+	_, checkRules := planRuleMappings(plan, frameworkID)
+	controls := make(map[frameworkControl]bool)
+	for _, keys := range ruleControls {
+		for _, key := range keys {
+			controls[key] = true
+		}
+	}
+
+	filtered := *assessmentResults
+	filtered.Results = make([]oscalTypes.Result, 0, len(assessmentResults.Results))
+	for _, result := range assessmentResults.Results {
+		observationRules := make(map[string]string)
+		if result.Observations != nil {
+			var observations []oscalTypes.Observation
+			for _, observation := range *result.Observations {
+				ruleID, _ := observationRuleID(observation, checkRules)
+				if _, inScope := ruleControls[ruleID]; inScope {
+					observations = append(observations, observation)
+					observationRules[observation.UUID] = ruleID
+				}
+			}
+			result.Observations = &observations
+		}
+		if result.Findings != nil {
+			var findings []oscalTypes.Finding
+			for _, finding := range *result.Findings {
+				key := frameworkControl{frameworkID: frameworkID, controlID: strings.TrimSuffix(finding.Target.TargetId, "_smt")}
+				if !controls[key] {
+					continue
+				}
+				if finding.RelatedObservations != nil {
+					var related []oscalTypes.RelatedObservation
+					for _, observation := range *finding.RelatedObservations {
+						if slices.Contains(ruleControls[observationRules[observation.ObservationUuid]], key) {
+							related = append(related, observation)
+						}
+					}
+					if len(related) == 0 {
+						continue
+					}
+					finding.RelatedObservations = &related
+				}
+				findings = append(findings, finding)
+			}
+			result.Findings = &findings
+		}
+		filtered.Results = append(filtered.Results, result)
+	}
+	return &filtered
+}
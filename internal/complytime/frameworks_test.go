@@ -0,0 +1,307 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"bytes"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+// testFrameworkPlan returns a plan with the given activities, a task associated with all of them
+// and the given components.
+func testFrameworkPlan(activities []oscalTypes.Activity, components ...string) *oscalTypes.AssessmentPlan {
+	var associated []oscalTypes.AssociatedActivity
+	for _, activity := range activities {
+		associated = append(associated, oscalTypes.AssociatedActivity{
+			ActivityUuid: activity.UUID,
+			Subjects: []oscalTypes.AssessmentSubject{
+				{Type: "component", IncludeSubjects: &[]oscalTypes.SelectSubjectById{{SubjectUuid: components[0], Type: "component"}}},
+			},
+		})
+	}
+	var systemComponents []oscalTypes.SystemComponent
+	var usesComponents []oscalTypes.UsesComponent
+	for _, component := range components {
+		systemComponents = append(systemComponents, oscalTypes.SystemComponent{UUID: component, Title: component})
+		usesComponents = append(usesComponents, oscalTypes.UsesComponent{ComponentUuid: component})
+	}
+	var selected []oscalTypes.AssessedControlsSelectControlById
+	for _, activity := range activities {
+		for _, controlID := range planActivityControls(activity) {
+			selected = append(selected, oscalTypes.AssessedControlsSelectControlById{ControlId: controlID})
+		}
+	}
+	return &oscalTypes.AssessmentPlan{
+		ReviewedControls: oscalTypes.ReviewedControls{
+			ControlSelections: []oscalTypes.AssessedControls{{IncludeControls: &selected}},
+		},
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			Activities: &activities,
+			Components: &systemComponents,
+		},
+		AssessmentAssets: &oscalTypes.AssessmentAssets{
+			AssessmentPlatforms: []oscalTypes.AssessmentPlatform{{Title: "platform", UsesComponents: &usesComponents}},
+			Components:          &systemComponents,
+		},
+		Tasks: &[]oscalTypes.Task{{Title: "Automated Assessment", Type: "action", AssociatedActivities: &associated}},
+	}
+}
+
+// testFrameworkActivity returns an activity for a rule selecting the given controls, or a skipped
+// activity without controls, with an optional parameter value.
+func testFrameworkActivity(uuid, ruleID, parameter string, controls ...string) oscalTypes.Activity {
+	activity := testActivity(ruleID, "check-"+ruleID, controls...)
+	activity.UUID = uuid
+	activity.Props = &[]oscalTypes.Property{}
+	if len(controls) == 0 {
+		*activity.Props = append(*activity.Props, oscalTypes.Property{Name: "skipped", Value: "true", Ns: extensions.TrestleNameSpace})
+	}
+	if parameter != "" {
+		*activity.Props = append(*activity.Props, oscalTypes.Property{
+			Name:  "param-1",
+			Value: parameter,
+			Ns:    extensions.TrestleNameSpace,
+			Class: extensions.TestParameterClass,
+		})
+	}
+	return activity
+}
+
+// planActivityControls returns the control IDs selected by an activity.
+func planActivityControls(activity oscalTypes.Activity) []string {
+	var controls []string
+	if activity.RelatedControls == nil {
+		return nil
+	}
+	for _, selection := range activity.RelatedControls.ControlSelections {
+		if selection.IncludeControls == nil {
+			continue
+		}
+		for _, control := range *selection.IncludeControls {
+			controls = append(controls, control.ControlId)
+		}
+	}
+	return controls
+}
+
+// testMergedPlan returns the merged plan of a "cis" framework mapping rule-1 to control-1,
+// rule-2 to control-2 and skipping rule-3, and of a "baseline" framework mapping rule-2,
+// rule-3 and rule-4 to b-1, b-2 and b-3.
+func testMergedPlan(t *testing.T, logger hclog.Logger) *oscalTypes.AssessmentPlan {
+	cis := testFrameworkPlan([]oscalTypes.Activity{
+		testFrameworkActivity("a1", "rule-1", "", "control-1"),
+		testFrameworkActivity("a2", "rule-2", "value-1", "control-2"),
+		testFrameworkActivity("a3", "rule-3", "value-1"),
+	}, "comp-1")
+	baseline := testFrameworkPlan([]oscalTypes.Activity{
+		testFrameworkActivity("b2", "rule-2", "value-2", "b-1"),
+		testFrameworkActivity("b3", "rule-3", "value-3", "b-2"),
+		testFrameworkActivity("b4", "rule-4", "", "b-3"),
+	}, "comp-1", "comp-2")
+
+	merged, err := MergePlans([]FrameworkPlan{{FrameworkID: "cis", Plan: cis}, {FrameworkID: "baseline", Plan: baseline}}, logger)
+	require.NoError(t, err)
+	merged.Metadata.Props = &[]oscalTypes.Property{
+		{Name: extensions.FrameworkProp, Value: "cis", Ns: extensions.TrestleNameSpace},
+		{Name: extensions.FrameworkProp, Value: "baseline", Ns: extensions.TrestleNameSpace},
+	}
+	return merged
+}
+
+func TestMergePlans(t *testing.T) {
+	var logs bytes.Buffer
+	merged := testMergedPlan(t, hclog.New(&hclog.LoggerOptions{Output: &logs}))
+
+	activities := *merged.LocalDefinitions.Activities
+	require.Len(t, activities, 4)
+	var titles []string
+	for _, activity := range activities {
+		titles = append(titles, activity.Title)
+	}
+	require.Equal(t, []string{"rule-1", "rule-2", "rule-3", "rule-4"}, titles)
+
+	// Shared rules keep a single activity selecting the controls of both frameworks.
+	require.Equal(t, "a2", activities[1].UUID)
+	require.Equal(t, []string{"control-2", "b-1"}, planActivityControls(activities[1]))
+	require.Equal(t, []oscalTypes.Property{{Name: "param-1", Value: "value-1", Ns: extensions.TrestleNameSpace, Class: extensions.TestParameterClass}}, *activities[1].Props)
+	require.Contains(t, logs.String(), "keeping the first one: parameter=param-1 rule=rule-2")
+	require.NotContains(t, logs.String(), "rule=rule-3")
+
+	// Rules skipped by a framework are assessed for the other frameworks, with their parameters.
+	require.Equal(t, "a3", activities[2].UUID)
+	require.Equal(t, []string{"b-2"}, planActivityControls(activities[2]))
+	require.Equal(t, []oscalTypes.Property{{Name: "param-1", Value: "value-3", Ns: extensions.TrestleNameSpace, Class: extensions.TestParameterClass}}, *activities[2].Props)
+
+	require.Equal(t, "b4", activities[3].UUID)
+
+	tasks := *merged.Tasks
+	require.Len(t, tasks, 1)
+	var associated []string
+	for _, activity := range *tasks[0].AssociatedActivities {
+		associated = append(associated, activity.ActivityUuid)
+	}
+	require.Equal(t, []string{"a1", "a2", "a3", "b4"}, associated)
+
+	require.Len(t, *merged.LocalDefinitions.Components, 2)
+	require.Len(t, *merged.AssessmentAssets.Components, 2)
+	require.Len(t, merged.AssessmentAssets.AssessmentPlatforms, 1)
+	require.Len(t, *merged.AssessmentAssets.AssessmentPlatforms[0].UsesComponents, 2)
+
+	require.Len(t, merged.ReviewedControls.ControlSelections, 2)
+	for i, frameworkID := range []string{"cis", "baseline"} {
+		prop, found := extensions.GetTrestleProp(extensions.FrameworkProp, *merged.ReviewedControls.ControlSelections[i].Props)
+		require.True(t, found)
+		require.Equal(t, frameworkID, prop.Value)
+	}
+
+	ruleControls, _ := planRuleMappings(merged, "cis")
+	require.Equal(t, map[string][]string{"rule-1": {"control-1"}, "rule-2": {"control-2"}}, ruleControls)
+	ruleControls, _ = planRuleMappings(merged, "baseline")
+	require.Equal(t, map[string][]string{"rule-2": {"b-1"}, "rule-3": {"b-2"}, "rule-4": {"b-3"}}, ruleControls)
+
+	_, err := MergePlans(nil, hclog.NewNullLogger())
+	require.EqualError(t, err, "no assessment plans to merge")
+}
+
+// testFrameworkResults returns assessment results where rule-1 passes and rule-2 fails.
+func testFrameworkResults() *oscalTypes.AssessmentResults {
+	observations := []oscalTypes.Observation{
+		testObservation("obs-1", "rule-1", "check-rule-1", StatusPass),
+		testObservation("obs-2", "rule-2", "check-rule-2", StatusFail),
+	}
+	findings := []oscalTypes.Finding{
+		{Target: oscalTypes.FindingTarget{TargetId: "control-2_smt"}, RelatedObservations: &[]oscalTypes.RelatedObservation{{ObservationUuid: "obs-2"}}},
+		{Target: oscalTypes.FindingTarget{TargetId: "b-1_smt"}, RelatedObservations: &[]oscalTypes.RelatedObservation{{ObservationUuid: "obs-2"}}},
+	}
+	return &oscalTypes.AssessmentResults{
+		Results: []oscalTypes.Result{{Observations: &observations, Findings: &findings}},
+	}
+}
+
+func TestSummarizeResultsFrameworks(t *testing.T) {
+	summary := SummarizeResults(testFrameworkResults(), testMergedPlan(t, hclog.NewNullLogger()))
+	require.Len(t, summary.Rules, 4)
+	require.Equal(t, StatusCounts{Pass: 1, Fail: 1, NotAssessed: 2}, summary.RuleCounts)
+	require.Len(t, summary.Frameworks, 2)
+
+	cis := summary.Frameworks[0]
+	require.Equal(t, "cis", cis.FrameworkID)
+	require.Equal(t, StatusCounts{Pass: 1, Fail: 1}, cis.RuleCounts)
+	require.Equal(t, StatusCounts{Pass: 1, Fail: 1}, cis.ControlCounts)
+	require.Equal(t, []ControlResult{
+		{ControlID: "control-1", Status: StatusPass, Counts: StatusCounts{Pass: 1}, Rules: []string{"rule-1"}},
+		{ControlID: "control-2", Status: StatusFail, Counts: StatusCounts{Fail: 1}, Rules: []string{"rule-2"}},
+	}, cis.Controls)
+
+	baseline := summary.Frameworks[1]
+	require.Equal(t, "baseline", baseline.FrameworkID)
+	require.Equal(t, StatusCounts{Fail: 1, NotAssessed: 2}, baseline.RuleCounts)
+	require.Equal(t, StatusCounts{Fail: 1, NotAssessed: 2}, baseline.ControlCounts)
+	require.Len(t, baseline.Controls, 3)
+
+	// Plans with a single framework have no breakdown.
+	require.Empty(t, SummarizeResults(testResults(StatusPass, StatusFail), testResultsPlan()).Frameworks)
+}
+
+func TestSummarizeFrameworkResults(t *testing.T) {
+	plan := testMergedPlan(t, hclog.NewNullLogger())
+	results := testFrameworkResults()
+
+	cis := SummarizeFrameworkResults(results, plan, "cis")
+	require.Equal(t, []string{"rule-1", "rule-2"}, summaryRuleIDs(cis))
+	require.Equal(t, StatusCounts{Pass: 1, Fail: 1}, cis.RuleCounts)
+	require.Equal(t, StatusCounts{Pass: 1, Fail: 1}, cis.ControlCounts)
+	require.Equal(t, []string{"control-2"}, cis.Rules[1].Controls)
+	require.Empty(t, cis.Frameworks)
+
+	baseline := SummarizeFrameworkResults(results, plan, "baseline")
+	require.Equal(t, []string{"rule-2", "rule-3", "rule-4"}, summaryRuleIDs(baseline))
+	require.Equal(t, StatusCounts{Fail: 1, NotAssessed: 2}, baseline.RuleCounts)
+	require.Equal(t, []ControlResult{
+		{ControlID: "b-1", Status: StatusFail, Counts: StatusCounts{Fail: 1}, Rules: []string{"rule-2"}},
+		{ControlID: "b-2", Status: StatusNotAssessed, Counts: StatusCounts{NotAssessed: 1}, Rules: []string{"rule-3"}},
+		{ControlID: "b-3", Status: StatusNotAssessed, Counts: StatusCounts{NotAssessed: 1}, Rules: []string{"rule-4"}},
+	}, baseline.Controls)
+}
+
+// summaryRuleIDs returns the IDs of the rules of a results summary.
+func summaryRuleIDs(summary ResultsSummary) []string {
+	var ruleIDs []string
+	for _, rule := range summary.Rules {
+		ruleIDs = append(ruleIDs, rule.RuleID)
+	}
+	return ruleIDs
+}
+
+func TestFrameworkAssessmentResults(t *testing.T) {
+	plan := testMergedPlan(t, hclog.NewNullLogger())
+	results := testFrameworkResults()
+
+	cis := FrameworkAssessmentResults(results, plan, "cis")
+	require.Len(t, *cis.Results[0].Findings, 1)
+	require.Equal(t, "control-2_smt", (*cis.Results[0].Findings)[0].Target.TargetId)
+	require.Len(t, *cis.Results[0].Observations, 2)
+
+	baseline := FrameworkAssessmentResults(results, plan, "baseline")
+	require.Len(t, *baseline.Results[0].Findings, 1)
+	require.Equal(t, "b-1_smt", (*baseline.Results[0].Findings)[0].Target.TargetId)
+
+	// The original results are unchanged.
+	require.Len(t, *results.Results[0].Findings, 2)
+}
+
+func TestFrameworksSharingControls(t *testing.T) {
+	// The baseline is tailored from CIS and assesses control-1 with another rule.
+	cis := testFrameworkPlan([]oscalTypes.Activity{testFrameworkActivity("a1", "rule-1", "", "control-1")}, "comp-1")
+	baseline := testFrameworkPlan([]oscalTypes.Activity{testFrameworkActivity("b1", "rule-2", "", "control-1")}, "comp-1")
+	plan, err := MergePlans([]FrameworkPlan{{FrameworkID: "cis", Plan: cis}, {FrameworkID: "baseline", Plan: baseline}}, hclog.NewNullLogger())
+	require.NoError(t, err)
+	plan.Metadata.Props = &[]oscalTypes.Property{
+		{Name: extensions.FrameworkProp, Value: "cis", Ns: extensions.TrestleNameSpace},
+		{Name: extensions.FrameworkProp, Value: "baseline", Ns: extensions.TrestleNameSpace},
+	}
+	observations := []oscalTypes.Observation{
+		testObservation("obs-1", "rule-1", "check-rule-1", StatusPass),
+		testObservation("obs-2", "rule-2", "check-rule-2", StatusFail),
+	}
+	findings := []oscalTypes.Finding{
+		{Target: oscalTypes.FindingTarget{TargetId: "control-1_smt"}, RelatedObservations: &[]oscalTypes.RelatedObservation{{ObservationUuid: "obs-1"}, {ObservationUuid: "obs-2"}}},
+	}
+	results := &oscalTypes.AssessmentResults{
+		Results: []oscalTypes.Result{{Observations: &observations, Findings: &findings}},
+	}
+
+	summary := SummarizeResults(results, plan)
+	require.Len(t, summary.Frameworks, 2)
+	require.Equal(t, []ControlResult{
+		{ControlID: "control-1", Status: StatusPass, Counts: StatusCounts{Pass: 1}, Rules: []string{"rule-1"}},
+	}, summary.Frameworks[0].Controls)
+	require.Equal(t, StatusCounts{Pass: 1}, summary.Frameworks[0].RuleCounts)
+	require.Equal(t, []ControlResult{
+		{ControlID: "control-1", Status: StatusFail, Counts: StatusCounts{Fail: 1}, Rules: []string{"rule-2"}},
+	}, summary.Frameworks[1].Controls)
+	require.Equal(t, StatusCounts{Fail: 1}, summary.Frameworks[1].RuleCounts)
+
+	for frameworkID, observationID := range map[string]string{"cis": "obs-1", "baseline": "obs-2"} {
+		filtered := FrameworkAssessmentResults(results, plan, frameworkID)
+		require.Len(t, *filtered.Results[0].Observations, 1, frameworkID)
+		require.Equal(t, observationID, (*filtered.Results[0].Observations)[0].UUID, frameworkID)
+		require.Len(t, *filtered.Results[0].Findings, 1, frameworkID)
+		require.Equal(t, []oscalTypes.RelatedObservation{{ObservationUuid: observationID}}, *(*filtered.Results[0].Findings)[0].RelatedObservations, frameworkID)
+	}
+
+	cisSummary := SummarizeFrameworkResults(results, plan, "cis")
+	require.Equal(t, []string{"rule-1"}, summaryRuleIDs(cisSummary))
+	require.Equal(t, []ControlResult{
+		{ControlID: "control-1", Status: StatusPass, Counts: StatusCounts{Pass: 1}, Rules: []string{"rule-1"}},
+	}, cisSummary.Controls)
+
+	// The original results are unchanged.
+	require.Len(t, *results.Results[0].Observations, 2)
+	require.Len(t, *(*results.Results[0].Findings)[0].RelatedObservations, 2)
+}
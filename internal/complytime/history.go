@@ -0,0 +1,374 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	// HistoryDir is the directory of a workspace containing a directory for each scan run.
+	HistoryDir = "history"
+	// RunManifestFile is the name of the file describing a scan run in its directory.
+	RunManifestFile = "run.yaml"
+	// RunEvidenceDir is the directory of a run containing the copies of the local evidence files.
+	RunEvidenceDir = "evidence"
+	// LatestRun refers to the most recent scan run recorded in a workspace.
+	LatestRun = "latest"
+	// runIDFormat is the UTC timestamp format of run IDs, sorted chronologically.
+	runIDFormat = "20060102T150405Z"
+)
+
+// ErrRunNotFound is returned when a scan run is not recorded in the workspace history.
+var ErrRunNotFound = errors.New("scan run not found")
+
+// Run describes a scan run recorded in the workspace history.
+type Run struct {
+	// ID is the name of the run directory.
+	ID string `json:"id" yaml:"id"`
+	// Time is the time the run was recorded.
+	Time time.Time `json:"time" yaml:"time"`
+	// FrameworkID is the framework of the assessment plan.
+	FrameworkID string `json:"framework" yaml:"framework"`
+	// PlanDigest is the digest of the assessment plan used for the run.
+	PlanDigest string `json:"planDigest,omitempty" yaml:"planDigest,omitempty"`
+	// RuleCounts counts the rule results by status.
+	RuleCounts StatusCounts `json:"ruleCounts" yaml:"ruleCounts"`
+	// ControlCounts counts the control results by status.
+	ControlCounts StatusCounts `json:"controlCounts" yaml:"controlCounts"`
+	// Frameworks count the results of each framework for assessment plans with several frameworks.
+	Frameworks []FrameworkCounts `json:"frameworks,omitempty" yaml:"frameworks,omitempty"`
+	// Files are the names of the files written in the run directory.
+	Files []string `json:"files" yaml:"files"`
+	// Artifacts reference the evidence linked from the assessment results.
+	Artifacts []RunArtifact `json:"artifacts,omitempty" yaml:"artifacts,omitempty"`
+}
+
+// FrameworkCounts counts the results of a single framework of a scan run.
+type FrameworkCounts struct {
+	FrameworkID   string       `json:"framework" yaml:"framework"`
+	RuleCounts    StatusCounts `json:"ruleCounts" yaml:"ruleCounts"`
+	ControlCounts StatusCounts `json:"controlCounts" yaml:"controlCounts"`
+}
+
+// RunArtifact is a reference to evidence linked from the assessment results.
+type RunArtifact struct {
+	Href string `json:"href" yaml:"href"`
+	// Path is the copy of a local evidence file, relative to the run directory.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Digest is the digest of the copy of a local evidence file, in the form "sha256:<hex>".
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+}
+
+// Retention limits the scan runs kept in the workspace history.
+// Zero values do not limit the history.
+type Retention struct {
+	// Keep is the number of most recent runs to keep.
+	Keep int
+	// MaxAge is the age after which runs are removed.
+	MaxAge time.Duration
+}
+
+// HistoryPath returns the history directory of a workspace.
+func HistoryPath(workspace string) string {
+	return filepath.Join(workspace, HistoryDir)
+}
+
+// RunPath returns the directory of a scan run in the workspace history.
+func RunPath(workspace, id string) string {
+	return filepath.Join(HistoryPath(workspace), id)
+}
+
+// RecordRun writes the files of a scan run in a new timestamped directory of the workspace
+// history, with a manifest describing the run, and makes it the latest run.
+// The local evidence files of the run artifacts are copied into the run directory.
+// The ID, time, files and artifact copies of the run are set from the recording.
+func RecordRun(workspace string, run Run, files map[string][]byte, now time.Time) (Run, error) {
+	historyDir := HistoryPath(workspace)
+	if err := os.MkdirAll(historyDir, 0700); err != nil {
+		return Run{}, err
+	}
+	run.Time = now.UTC().Truncate(time.Second)
+	baseID := run.Time.Format(runIDFormat)
+	run.ID = baseID
+	// Runs recorded within the same second get a sequence suffix.
+	for i := 2; ; i++ {
+		err := os.Mkdir(RunPath(workspace, run.ID), 0700)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return Run{}, err
+		}
+		run.ID = baseID + "-" + strconv.Itoa(i)
+	}
+
+	run.Files = make([]string, 0, len(files))
+	for name := range files {
+		run.Files = append(run.Files, name)
+	}
+	sort.Strings(run.Files)
+	for _, name := range run.Files {
+		if !filepath.IsLocal(name) || strings.ContainsRune(name, filepath.Separator) {
+			return Run{}, fmt.Errorf("invalid run file name %s", name)
+		}
+		if err := os.WriteFile(filepath.Join(RunPath(workspace, run.ID), name), files[name], 0600); err != nil {
+			return Run{}, err
+		}
+	}
+	run.Artifacts = slices.Clone(run.Artifacts)
+	if err := copyRunEvidence(RunPath(workspace, run.ID), run.Artifacts); err != nil {
+		return Run{}, err
+	}
+
+	data, err := yaml.Marshal(run)
+	if err != nil {
+		return Run{}, fmt.Errorf("error marshalling run manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(RunPath(workspace, run.ID), RunManifestFile), data, 0600); err != nil {
+		return Run{}, err
+	}
+	if err := os.WriteFile(filepath.Join(historyDir, LatestRun), []byte(run.ID+"\n"), 0600); err != nil {
+		return Run{}, err
+	}
+	return run, nil
+}
+
+// ReadRun reads the manifest of a scan run. The id LatestRun refers to the most recent run.
+func ReadRun(workspace, id string) (Run, error) {
+	if id == LatestRun {
+		latest, err := latestRunID(workspace)
+		if err != nil {
+			return Run{}, err
+		}
+		id = latest
+	}
+	if !filepath.IsLocal(id) || strings.ContainsRune(id, filepath.Separator) {
+		return Run{}, fmt.Errorf("%w: %s", ErrRunNotFound, id)
+	}
+	return ReadRunDir(RunPath(workspace, id))
+}
+
+// ReadRunDir reads the manifest of the scan run recorded in a run directory.
+func ReadRunDir(runDir string) (Run, error) {
+	data, err := os.ReadFile(filepath.Join(runDir, RunManifestFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Run{}, fmt.Errorf("%w: %s", ErrRunNotFound, filepath.Base(runDir))
+		}
+		return Run{}, err
+	}
+	var run Run
+	if err := yaml.Unmarshal(data, &run); err != nil {
+		return Run{}, fmt.Errorf("error reading run manifest %s: %w", filepath.Base(runDir), err)
+	}
+	return run, nil
+}
+
+// latestRunID returns the ID of the latest run from the pointer in the history directory.
+func latestRunID(workspace string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(HistoryPath(workspace), LatestRun))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("%w: no scan runs recorded in %s", ErrRunNotFound, HistoryPath(workspace))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ListRuns returns the scan runs recorded in the workspace history, most recent first.
+// Directories without a run manifest are ignored.
+func ListRuns(workspace string) ([]Run, error) {
+	entries, err := os.ReadDir(HistoryPath(workspace))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Run{}, nil
+		}
+		return nil, err
+	}
+	runs := []Run{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		run, err := ReadRun(workspace, entry.Name())
+		if err != nil {
+			if errors.Is(err, ErrRunNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].Time.Equal(runs[j].Time) {
+			return runs[i].Time.After(runs[j].Time)
+		}
+		return runs[i].ID > runs[j].ID
+	})
+	return runs, nil
+}
+
+// PruneRuns removes the runs exceeding the retention from the workspace history and returns them.
+// The latest run is always kept. When dryRun is true, the runs are returned without being removed.
+func PruneRuns(workspace string, retention Retention, now time.Time, dryRun bool) ([]Run, error) {
+	runs, err := ListRuns(workspace)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := latestRunID(workspace)
+	if err != nil && !errors.Is(err, ErrRunNotFound) {
+		return nil, err
+	}
+
+	var pruned []Run
+	for i, run := range runs {
+		if run.ID == latest {
+			continue
+		}
+		tooMany := retention.Keep > 0 && i >= retention.Keep
+		tooOld := retention.MaxAge > 0 && now.Sub(run.Time) > retention.MaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if !dryRun {
+			if err := os.RemoveAll(RunPath(workspace, run.ID)); err != nil {
+				return pruned, err
+			}
+		}
+		pruned = append(pruned, run)
+	}
+	return pruned, nil
+}
+
+// RunArtifacts returns references to the evidence linked from the observations of the
+// assessment results.
+func RunArtifacts(assessmentResults *oscalTypes.AssessmentResults) []RunArtifact {
+	var artifacts []RunArtifact
+	seen := make(map[string]bool)
+	for _, result := range assessmentResults.Results {
+		if result.Observations == nil {
+			continue
+		}
+		for _, observation := range *result.Observations {
+			if observation.RelevantEvidence == nil {
+				continue
+			}
+			for _, evidence := range *observation.RelevantEvidence {
+				if evidence.Href == "" || seen[evidence.Href] {
+					continue
+				}
+				seen[evidence.Href] = true
+				artifacts = append(artifacts, RunArtifact{Href: evidence.Href})
+			}
+		}
+	}
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].Href < artifacts[j].Href
+	})
+	return artifacts
+}
+
+// copyRunEvidence copies the local evidence files of the artifacts into the evidence directory
+// of a run, as plugins overwrite their evidence on each scan, and sets the path and digest of
+// each copy. Evidence files that no longer exist are only referenced.
+func copyRunEvidence(runDir string, artifacts []RunArtifact) error {
+	names := make(map[string]bool)
+	for i := range artifacts {
+		source := localEvidencePath(artifacts[i].Href)
+		if source == "" {
+			continue
+		}
+		info, err := os.Stat(source)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		// Evidence files of different plugins may have the same name.
+		name := filepath.Base(source)
+		for n := 2; names[name]; n++ {
+			name = strconv.Itoa(n) + "-" + filepath.Base(source)
+		}
+		names[name] = true
+
+		if err := os.MkdirAll(filepath.Join(runDir, RunEvidenceDir), 0700); err != nil {
+			return err
+		}
+		path := filepath.Join(RunEvidenceDir, name)
+		if err := copyFile(source, filepath.Join(runDir, path)); err != nil {
+			return fmt.Errorf("error copying evidence %s: %w", source, err)
+		}
+		checksum, err := fileChecksum(filepath.Join(runDir, path))
+		if err != nil {
+			return err
+		}
+		artifacts[i].Path = path
+		artifacts[i].Digest = "sha256:" + checksum
+	}
+	return nil
+}
+
+// copyFile copies the content of a file to a new file.
+func copyFile(source, destination string) error {
+	in, err := os.Open(filepath.Clean(source))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(filepath.Clean(destination), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// localEvidencePath returns the path of evidence referenced by a file URL or a path,
+// or an empty string for remote evidence.
+func localEvidencePath(href string) string {
+	uri, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	switch uri.Scheme {
+	case "file":
+		return uri.Path
+	case "":
+		return href
+	default:
+		return ""
+	}
+}
+
+// ParseAge parses a retention age, either a number of days such as "30d"
+// or a Go duration such as "12h".
+func ParseAge(age string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(age, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q: must be a number of days such as \"30d\" or a duration such as \"12h\"", age)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	duration, err := time.ParseDuration(age)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid age %q: must be a number of days such as \"30d\" or a duration such as \"12h\"", age)
+	}
+	return duration, nil
+}
@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordRun(t *testing.T) {
+	workspace := t.TempDir()
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	files := map[string][]byte{
+		"assessment-results.json": []byte("{}"),
+		"assessment-results.md":   []byte("# Results"),
+	}
+	run := Run{FrameworkID: "example", RuleCounts: StatusCounts{Pass: 1, Fail: 2}}
+
+	first, err := RecordRun(workspace, run, files, now)
+	require.NoError(t, err)
+	require.Equal(t, "20250501T120000Z", first.ID)
+	require.Equal(t, []string{"assessment-results.json", "assessment-results.md"}, first.Files)
+	data, err := os.ReadFile(filepath.Join(RunPath(workspace, first.ID), "assessment-results.md"))
+	require.NoError(t, err)
+	require.Equal(t, "# Results", string(data))
+
+	// Runs recorded within the same second get a sequence suffix.
+	second, err := RecordRun(workspace, run, files, now.Add(500*time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, "20250501T120000Z-2", second.ID)
+
+	latest, err := ReadRun(workspace, LatestRun)
+	require.NoError(t, err)
+	require.Equal(t, second, latest)
+	require.Equal(t, StatusCounts{Pass: 1, Fail: 2}, latest.RuleCounts)
+
+	_, err = RecordRun(workspace, run, map[string][]byte{"../escape.json": nil}, now)
+	require.EqualError(t, err, "invalid run file name ../escape.json")
+}
+
+func TestReadRunNotFound(t *testing.T) {
+	workspace := t.TempDir()
+	_, err := ReadRun(workspace, LatestRun)
+	require.ErrorIs(t, err, ErrRunNotFound)
+	_, err = ReadRun(workspace, "20250501T120000Z")
+	require.ErrorIs(t, err, ErrRunNotFound)
+	_, err = ReadRun(workspace, "../..")
+	require.ErrorIs(t, err, ErrRunNotFound)
+
+	runs, err := ListRuns(workspace)
+	require.NoError(t, err)
+	require.Empty(t, runs)
+}
+
+func TestPruneRuns(t *testing.T) {
+	now := time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		retention  Retention
+		wantPruned []string
+	}{
+		{
+			name:      "NoRetention",
+			retention: Retention{},
+		},
+		{
+			name:       "Keep",
+			retention:  Retention{Keep: 2},
+			wantPruned: []string{"20250505T000000Z", "20250503T000000Z", "20250501T000000Z"},
+		},
+		{
+			name:       "MaxAge",
+			retention:  Retention{MaxAge: 8 * 24 * time.Hour},
+			wantPruned: []string{"20250501T000000Z"},
+		},
+		{
+			// The latest run is kept even when it exceeds the retention.
+			name:       "LatestKept",
+			retention:  Retention{MaxAge: time.Hour},
+			wantPruned: []string{"20250507T000000Z", "20250505T000000Z", "20250503T000000Z", "20250501T000000Z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := t.TempDir()
+			for _, day := range []int{1, 3, 5, 7, 9} {
+				_, err := RecordRun(workspace, Run{FrameworkID: "example"}, nil, time.Date(2025, 5, day, 0, 0, 0, 0, time.UTC))
+				require.NoError(t, err)
+			}
+
+			pruned, err := PruneRuns(workspace, tt.retention, now, true)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPruned, runIDs(pruned))
+			runs, err := ListRuns(workspace)
+			require.NoError(t, err)
+			require.Len(t, runs, 5)
+
+			pruned, err = PruneRuns(workspace, tt.retention, now, false)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPruned, runIDs(pruned))
+			runs, err = ListRuns(workspace)
+			require.NoError(t, err)
+			require.Len(t, runs, 5-len(tt.wantPruned))
+			require.Equal(t, "20250509T000000Z", runs[0].ID)
+		})
+	}
+}
+
+func TestRunArtifacts(t *testing.T) {
+	evidencePath := filepath.Join(t.TempDir(), "arf.xml")
+	require.NoError(t, os.WriteFile(evidencePath, []byte("results"), 0600))
+	evidence := []oscalTypes.RelevantEvidence{
+		{Href: "file://" + evidencePath},
+		{Href: "https://example.com/evidence"},
+	}
+	assessmentResults := &oscalTypes.AssessmentResults{
+		Results: []oscalTypes.Result{
+			{Observations: &[]oscalTypes.Observation{
+				{RelevantEvidence: &evidence},
+				{RelevantEvidence: &evidence},
+				{},
+			}},
+		},
+	}
+	artifacts := RunArtifacts(assessmentResults)
+	require.Equal(t, []RunArtifact{
+		{Href: "file://" + evidencePath},
+		{Href: "https://example.com/evidence"},
+	}, artifacts)
+}
+
+func TestRecordRunEvidence(t *testing.T) {
+	workspace := t.TempDir()
+	evidenceDir := t.TempDir()
+	arfPath := filepath.Join(evidenceDir, "arf.xml")
+	otherArfPath := filepath.Join(evidenceDir, "other", "arf.xml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(otherArfPath), 0700))
+	require.NoError(t, os.WriteFile(arfPath, []byte("results"), 0600))
+	require.NoError(t, os.WriteFile(otherArfPath, []byte("other results"), 0600))
+	run := Run{
+		FrameworkID: "example",
+		Artifacts: []RunArtifact{
+			{Href: "file://" + arfPath},
+			{Href: otherArfPath},
+			{Href: "file://" + filepath.Join(evidenceDir, "missing.xml")},
+			{Href: "https://example.com/evidence"},
+		},
+	}
+	first, err := RecordRun(workspace, run, nil, time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, []RunArtifact{
+		{Href: "file://" + arfPath, Path: filepath.Join("evidence", "arf.xml"), Digest: "sha256:c099142bc3186ded72786ba27e9ea6d2da240fb9fd3fe79b479ecf8e734b2850"},
+		{Href: otherArfPath, Path: filepath.Join("evidence", "2-arf.xml"), Digest: first.Artifacts[1].Digest},
+		{Href: "file://" + filepath.Join(evidenceDir, "missing.xml")},
+		{Href: "https://example.com/evidence"},
+	}, first.Artifacts)
+	require.Empty(t, run.Artifacts[0].Path)
+
+	// The plugins overwrite their evidence on the next scan, the copy of the first run is kept.
+	require.NoError(t, os.WriteFile(arfPath, []byte("new results"), 0600))
+	second, err := RecordRun(workspace, run, nil, time.Date(2025, 5, 2, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NotEqual(t, first.Artifacts[0].Digest, second.Artifacts[0].Digest)
+	data, err := os.ReadFile(filepath.Join(RunPath(workspace, first.ID), first.Artifacts[0].Path))
+	require.NoError(t, err)
+	require.Equal(t, "results", string(data))
+	data, err = os.ReadFile(filepath.Join(RunPath(workspace, second.ID), second.Artifacts[0].Path))
+	require.NoError(t, err)
+	require.Equal(t, "new results", string(data))
+
+	recorded, err := ReadRun(workspace, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, first.Artifacts, recorded.Artifacts)
+}
+
+func TestParseAge(t *testing.T) {
+	age, err := ParseAge("30d")
+	require.NoError(t, err)
+	require.Equal(t, 30*24*time.Hour, age)
+	age, err = ParseAge("12h")
+	require.NoError(t, err)
+	require.Equal(t, 12*time.Hour, age)
+	_, err = ParseAge("1w")
+	require.ErrorContains(t, err, "invalid age \"1w\"")
+	_, err = ParseAge("-1d")
+	require.Error(t, err)
+}
+
+func runIDs(runs []Run) []string {
+	var ids []string
+	for _, run := range runs {
+		ids = append(ids, run.ID)
+	}
+	return ids
+}
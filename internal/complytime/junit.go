@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+// unmappedSuiteName is the JUnit test suite for rules that are not mapped to any control.
+const unmappedSuiteName = "rules-without-controls"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// GenerateJUnit exports OSCAL Assessment Results as JUnit XML.
+//
+// Each control becomes a test suite and each rule mapped to the control becomes a test case.
+// Failed and errored rules carry the reasons reported for their subjects and rules that
+// were not assessed are skipped. Rules without controls are grouped in a separate suite.
+// The plan is optional and is used to map passing rules to controls.
+func GenerateJUnit(assessmentResults *oscalTypes.AssessmentResults, plan *oscalTypes.AssessmentPlan, frameworkID string) ([]byte, error) {
+	summary := SummarizeResults(assessmentResults, plan)
+
+	rules := make(map[string]RuleResult, len(summary.Rules))
+	var unmapped []RuleResult
+	for _, rule := range summary.Rules {
+		rules[rule.RuleID] = rule
+		if len(rule.Controls) == 0 {
+			unmapped = append(unmapped, rule)
+		}
+	}
+
+	suites := junitTestSuites{Name: frameworkID}
+	addSuite := func(name string, suiteRules []RuleResult) {
+		suite := junitTestSuite{Name: name}
+		for _, rule := range suiteRules {
+			testCase := newJUnitTestCase(name, rule)
+			switch {
+			case testCase.Failure != nil:
+				suite.Failures++
+			case testCase.Error != nil:
+				suite.Errors++
+			case testCase.Skipped != nil:
+				suite.Skipped++
+			}
+			suite.Tests++
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	for _, control := range summary.Controls {
+		var controlRules []RuleResult
+		for _, ruleID := range control.Rules {
+			controlRules = append(controlRules, rules[ruleID])
+		}
+		addSuite(control.ControlID, controlRules)
+	}
+	if len(unmapped) > 0 {
+		addSuite(unmappedSuiteName, unmapped)
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	data = append([]byte(xml.Header), data...)
+	return append(data, '\n'), nil
+}
+
+// newJUnitTestCase returns the test case for a rule result in a given suite.
+func newJUnitTestCase(suiteName string, rule RuleResult) junitTestCase {
+	testCase := junitTestCase{Name: rule.RuleID, ClassName: suiteName}
+
+	var reasons, output []string
+	for _, subject := range rule.Subjects {
+		line := fmt.Sprintf("%s: %s", subject.Title, subject.Result)
+		if subject.Reason != "" {
+			line = fmt.Sprintf("%s: %s", subject.Title, subject.Reason)
+		}
+		output = append(output, line)
+		if IsFailing(normalizeStatus(subject.Result)) {
+			reasons = append(reasons, line)
+		}
+	}
+	testCase.SystemOut = strings.Join(output, "\n")
+
+	switch rule.Status {
+	case StatusFail:
+		testCase.Failure = &junitMessage{Message: fmt.Sprintf("rule %s failed", rule.RuleID), Text: strings.Join(reasons, "\n")}
+	case StatusError:
+		testCase.Error = &junitMessage{Message: fmt.Sprintf("rule %s could not be evaluated", rule.RuleID), Text: strings.Join(reasons, "\n")}
+	case StatusNotAssessed:
+		testCase.Skipped = &junitMessage{Message: "not assessed"}
+	}
+	return testCase
+}
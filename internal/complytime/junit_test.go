@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateJUnit(t *testing.T) {
+	data, err := GenerateJUnit(testResults(StatusPass, StatusFail), testResultsPlan(), "example")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(data), xml.Header))
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &suites))
+	require.Equal(t, "example", suites.Name)
+	require.Equal(t, 4, suites.Tests)
+	require.Equal(t, 2, suites.Failures)
+	require.Equal(t, 0, suites.Errors)
+	require.Equal(t, 1, suites.Skipped)
+
+	require.Len(t, suites.Suites, 2)
+	control1 := suites.Suites[0]
+	require.Equal(t, "control-1", control1.Name)
+	require.Equal(t, 2, control1.Tests)
+	require.Equal(t, 1, control1.Failures)
+	require.Len(t, control1.Cases, 2)
+
+	passed := control1.Cases[0]
+	require.Equal(t, "rule-1", passed.Name)
+	require.Equal(t, "control-1", passed.ClassName)
+	require.Nil(t, passed.Failure)
+	require.Equal(t, "Host localhost: openscap rule-result is pass", passed.SystemOut)
+
+	failed := control1.Cases[1]
+	require.Equal(t, "rule-2", failed.Name)
+	require.NotNil(t, failed.Failure)
+	require.Equal(t, "rule rule-2 failed", failed.Failure.Message)
+	require.Equal(t, "Host localhost: openscap rule-result is fail", failed.Failure.Text)
+
+	control2 := suites.Suites[1]
+	require.Equal(t, "control-2", control2.Name)
+	require.NotNil(t, control2.Cases[1].Skipped)
+
+	// Without a plan, passing rules are not mapped to controls.
+	data, err = GenerateJUnit(testResults(StatusPass, StatusError), nil, "example")
+	require.NoError(t, err)
+	suites = junitTestSuites{}
+	require.NoError(t, xml.Unmarshal(data, &suites))
+	require.Len(t, suites.Suites, 2)
+	require.Equal(t, 1, suites.Suites[0].Errors)
+	require.Equal(t, unmappedSuiteName, suites.Suites[1].Name)
+	require.Equal(t, 2, suites.Suites[1].Tests)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/oscal-compass/oscal-sdk-go/extensions"
@@ -17,23 +18,26 @@ import (
 )
 
 // WritePlan writes an AssessmentPlan to a given path location with consistency.
-func WritePlan(plan *oscalTypes.AssessmentPlan, frameworkId string, planLocation string) error {
+// The plan records the given frameworks, the first one being the primary framework.
+func WritePlan(plan *oscalTypes.AssessmentPlan, planLocation string, frameworkIDs ...string) error {
 	// Ensure UserWorkspace exists before writing the plan
 	userWorkspace := filepath.Dir(planLocation)
 	if err := os.MkdirAll(userWorkspace, 0700); err != nil {
 		return err
 	}
 
-	// Add the framework property needed for ComplyTime
+	// Add the framework properties needed for ComplyTime
 	if plan.Metadata.Props == nil {
 		plan.Metadata.Props = &[]oscalTypes.Property{}
 	}
-	frameworkProperty := oscalTypes.Property{
-		Name:  extensions.FrameworkProp,
-		Value: frameworkId,
-		Ns:    extensions.TrestleNameSpace,
+	for _, frameworkID := range frameworkIDs {
+		frameworkProperty := oscalTypes.Property{
+			Name:  extensions.FrameworkProp,
+			Value: frameworkID,
+			Ns:    extensions.TrestleNameSpace,
+		}
+		*plan.Metadata.Props = append(*plan.Metadata.Props, frameworkProperty)
 	}
-	*plan.Metadata.Props = append(*plan.Metadata.Props, frameworkProperty)
 
 	// To ensure we can easily read the plan once written, include under
 	// OSCAL Model type to include the top-level "assessment-plan" key.
@@ -63,6 +67,33 @@ func ReadPlan(assessmentPlanPath string, validator validation.Validator) (*oscal
 	return plan, nil
 }
 
+// PlanFrameworkID returns the framework short name recorded in the assessment plan metadata.
+// Plans with several frameworks return the primary framework.
+func PlanFrameworkID(plan *oscalTypes.AssessmentPlan) (string, error) {
+	frameworkIDs, err := PlanFrameworkIDs(plan)
+	if err != nil {
+		return "", err
+	}
+	return frameworkIDs[0], nil
+}
+
+// PlanFrameworkIDs returns the framework short names recorded in the assessment plan metadata,
+// starting with the primary framework.
+func PlanFrameworkIDs(plan *oscalTypes.AssessmentPlan) ([]string, error) {
+	var frameworkIDs []string
+	if plan.Metadata.Props != nil {
+		for _, prop := range extensions.FindAllProps(*plan.Metadata.Props, extensions.WithName(extensions.FrameworkProp), extensions.WithNamespace(extensions.TrestleNameSpace)) {
+			if !slices.Contains(frameworkIDs, prop.Value) {
+				frameworkIDs = append(frameworkIDs, prop.Value)
+			}
+		}
+	}
+	if len(frameworkIDs) == 0 {
+		return nil, errors.New("error reading framework property from assessment plan")
+	}
+	return frameworkIDs, nil
+}
+
 var ErrNoActivities = errors.New("no local activities detected")
 
 // Settings return a new compliance Settings instance based on the
@@ -74,6 +105,21 @@ func Settings(plan *oscalTypes.AssessmentPlan) (settings.Settings, error) {
 	return settings.Settings{}, ErrNoActivities
 }
 
+// PlanParameters returns the values of the rule parameters set in the
+// activities of the assessment plan, by parameter ID.
+func PlanParameters(plan *oscalTypes.AssessmentPlan) map[string]string {
+	parameters := make(map[string]string)
+	if plan.LocalDefinitions == nil || plan.LocalDefinitions.Activities == nil {
+		return parameters
+	}
+	for _, activity := range *plan.LocalDefinitions.Activities {
+		for _, parameter := range activityParameters(activity) {
+			parameters[parameter.Name] = parameter.Value
+		}
+	}
+	return parameters
+}
+
 // loadControlTitlesFromSource loads all control titles from a source and returns them as a map
 func loadControlTitlesFromSource(controlSource string, appDir ApplicationDirectory, validator validation.Validator) (map[string]string, error) {
 	profile, err := LoadProfile(appDir, controlSource, validator)
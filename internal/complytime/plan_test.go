@@ -28,7 +28,7 @@ func TestPlan(t *testing.T) {
 		},
 	}
 
-	err := WritePlan(&testPlan, "testid", testPlanPath)
+	err := WritePlan(&testPlan, testPlanPath, "testid")
 	require.NoError(t, err)
 
 	ap, err := ReadPlan(testPlanPath, validation.NoopValidator{})
@@ -38,6 +38,13 @@ func TestPlan(t *testing.T) {
 	_, err = Settings(ap)
 	require.ErrorIs(t, err, ErrNoActivities)
 
+	frameworkID, err := PlanFrameworkID(ap)
+	require.NoError(t, err)
+	require.Equal(t, "testid", frameworkID)
+
+	_, err = PlanFrameworkID(&oscalTypes.AssessmentPlan{})
+	require.EqualError(t, err, "error reading framework property from assessment plan")
+
 	// Test Write -> Read -> Settings on a happy path
 	localDefs := oscalTypes.LocalDefinitions{
 		Activities: &[]oscalTypes.Activity{
@@ -50,7 +57,7 @@ func TestPlan(t *testing.T) {
 	}
 	testPlan.LocalDefinitions = &localDefs
 
-	err = WritePlan(&testPlan, "testid", testPlanPath)
+	err = WritePlan(&testPlan, testPlanPath, "testid")
 	require.NoError(t, err)
 
 	// read plan to ensure it has the expected props
@@ -69,3 +76,26 @@ func TestPlan(t *testing.T) {
 	require.NotNil(t, ap.Metadata.Props)
 	require.Contains(t, *ap.Metadata.Props, wantProp)
 }
+
+func TestPlanFrameworkIDs(t *testing.T) {
+	plan := &oscalTypes.AssessmentPlan{
+		Metadata: oscalTypes.Metadata{
+			Props: &[]oscalTypes.Property{
+				{Name: extensions.FrameworkProp, Value: "cis", Ns: extensions.TrestleNameSpace},
+				{Name: extensions.FrameworkProp, Value: "baseline", Ns: extensions.TrestleNameSpace},
+				{Name: extensions.FrameworkProp, Value: "cis", Ns: extensions.TrestleNameSpace},
+				{Name: extensions.FrameworkProp, Value: "other"},
+			},
+		},
+	}
+	frameworkIDs, err := PlanFrameworkIDs(plan)
+	require.NoError(t, err)
+	require.Equal(t, []string{"cis", "baseline"}, frameworkIDs)
+
+	frameworkID, err := PlanFrameworkID(plan)
+	require.NoError(t, err)
+	require.Equal(t, "cis", frameworkID)
+
+	_, err = PlanFrameworkIDs(&oscalTypes.AssessmentPlan{})
+	require.EqualError(t, err, "error reading framework property from assessment plan")
+}
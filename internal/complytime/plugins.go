@@ -3,11 +3,17 @@
 package complytime
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
@@ -100,6 +106,17 @@ func (p PluginOptions) ToMap(pluginId string, logger hclog.Logger) (map[string]s
 	return selections, nil
 }
 
+// withDefaultConfigRoot returns the options with the UserConfigRoot set to
+// DefaultPluginConfigDir when it is not set and the default directory exists.
+func (p PluginOptions) withDefaultConfigRoot() PluginOptions {
+	if p.UserConfigRoot == "" {
+		if _, err := os.Stat(DefaultPluginConfigDir); err == nil {
+			p.UserConfigRoot = DefaultPluginConfigDir
+		}
+	}
+	return p
+}
+
 // Plugins launches and configures plugins with the given complytime global options. This function returns the plugin map with the
 // launched plugins, a plugin cleanup function, and an error. The cleanup function should be used if it is not nil.
 func Plugins(manager *framework.PluginManager, inputs *actions.InputContext, selections PluginOptions, logger hclog.Logger) (map[plugin.ID]policy.Provider, func(), error) {
@@ -108,11 +125,7 @@ func Plugins(manager *framework.PluginManager, inputs *actions.InputContext, sel
 		return nil, nil, err
 	}
 
-	if selections.UserConfigRoot == "" {
-		if _, err := os.Stat(DefaultPluginConfigDir); err == nil {
-			selections.UserConfigRoot = DefaultPluginConfigDir
-		}
-	}
+	selections = selections.withDefaultConfigRoot()
 	if err := selections.Validate(); err != nil {
 		return nil, nil, fmt.Errorf("failed plugin config validation: %w", err)
 	}
@@ -135,3 +148,155 @@ func Plugins(manager *framework.PluginManager, inputs *actions.InputContext, sel
 	}
 	return plugins, manager.Clean, nil
 }
+
+var (
+	// ErrPluginManifestNotFound indicates no manifest exists for a plugin ID.
+	ErrPluginManifestNotFound = errors.New("plugin manifest not found")
+	// ErrPluginManifestInvalid indicates the plugin manifest cannot be parsed or has an invalid ID.
+	ErrPluginManifestInvalid = errors.New("invalid plugin manifest")
+	// ErrPluginExecutable indicates the plugin executable is missing, outside the plugin
+	// directory or not executable.
+	ErrPluginExecutable = errors.New("invalid plugin executable")
+	// ErrPluginChecksum indicates the plugin executable does not match the manifest checksum.
+	ErrPluginChecksum = errors.New("plugin checksum mismatch")
+)
+
+// VerifyPlugin finds the plugin with a given ID the same way as when plugins are launched and
+// checks that the plugin executable matches the manifest checksum.
+// The returned manifest has a resolved executable path.
+func VerifyPlugin(appDir ApplicationDirectory, pluginID string) (plugin.Manifest, error) {
+	manifests, err := plugin.FindPlugins(appDir.PluginDir(), appDir.PluginManifestDir(), plugin.WithProviderIds([]plugin.ID{plugin.ID(pluginID)}))
+	if err != nil {
+		return plugin.Manifest{}, pluginError(appDir, err)
+	}
+	manifest := manifests[plugin.ID(pluginID)]
+
+	checksum, err := fileChecksum(manifest.ExecutablePath)
+	if err != nil {
+		return manifest, err
+	}
+	if !strings.EqualFold(checksum, manifest.Checksum) {
+		return manifest, fmt.Errorf("%w: %s has sha256 %s, the manifest expects %q", ErrPluginChecksum, manifest.ExecutablePath, checksum, manifest.Checksum)
+	}
+	return manifest, nil
+}
+
+// pluginError classifies an error of plugin discovery as ErrPluginManifestNotFound,
+// ErrPluginManifestInvalid or ErrPluginExecutable.
+func pluginError(appDir ApplicationDirectory, err error) error {
+	var notFound *plugin.NotFoundError
+	var manifestNotFound *plugin.ManifestNotFoundError
+	var pathErr *fs.PathError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &notFound), errors.As(err, &manifestNotFound), errors.Is(err, plugin.ErrPluginsNotFound):
+		return fmt.Errorf("%w: %v", ErrPluginManifestNotFound, err)
+	case errors.As(err, &pathErr) && pathErr.Path == appDir.PluginManifestDir():
+		// The plugin manifest directory cannot be read.
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %v", ErrPluginManifestNotFound, err)
+		}
+		return err
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.Is(err, io.ErrUnexpectedEOF), strings.HasPrefix(err.Error(), "invalid plugin id"):
+		return fmt.Errorf("%w: %v", ErrPluginManifestInvalid, err)
+	default:
+		return fmt.Errorf("%w: %v", ErrPluginExecutable, err)
+	}
+}
+
+// fileChecksum returns the hex encoded SHA256 checksum of a file.
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Sources of resolved plugin configuration values.
+const (
+	// OptionSourceComplyctl is used for the global options set by complyctl.
+	OptionSourceComplyctl = "complyctl"
+	// OptionSourceManifest is used for values from the plugin manifest defaults.
+	OptionSourceManifest = "manifest default"
+	// OptionSourceUnset is used for options without a value.
+	OptionSourceUnset = "unset"
+)
+
+// PluginOption is a plugin configuration option with the value that will be passed to the plugin
+// and where the value comes from. The source is OptionSourceComplyctl, OptionSourceManifest,
+// OptionSourceUnset or the user configuration directory.
+type PluginOption struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool   `json:"required" yaml:"required"`
+	Value       string `json:"value" yaml:"value"`
+	Source      string `json:"source" yaml:"source"`
+}
+
+// ResolvePluginOptions returns each configuration option of a plugin manifest with the value
+// resolved by the manifest, as when plugins are launched. User configuration is read from the
+// UserConfigRoot or from DefaultPluginConfigDir if it exists.
+func ResolvePluginOptions(manifest plugin.Manifest, selections PluginOptions, logger hclog.Logger) ([]PluginOption, error) {
+	selections = selections.withDefaultConfigRoot()
+	selectionsMap, err := selections.ToMap(manifest.ID.String(), logger)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := manifest.ResolveOptions(selectionsMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration for plugin %s: %w", manifest.ID, err)
+	}
+
+	var options []PluginOption
+	for _, configOption := range manifest.Configuration {
+		option := PluginOption{
+			Name:        configOption.Name,
+			Description: configOption.Description,
+			Required:    configOption.Required,
+			Source:      OptionSourceUnset,
+		}
+		value, resolvedOk := resolved[configOption.Name]
+		_, selected := selectionsMap[configOption.Name]
+		switch {
+		case !resolvedOk:
+		case configOption.Name == "workspace" || configOption.Name == "profile":
+			if value != "" {
+				option.Value = value
+				option.Source = OptionSourceComplyctl
+			}
+		case selected:
+			option.Value = value
+			option.Source = selections.UserConfigRoot
+		default:
+			option.Value = value
+			option.Source = OptionSourceManifest
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+// FindPluginIDs returns the sorted IDs of the plugins found in the application directory.
+// No IDs are returned when no plugin manifests are installed.
+func FindPluginIDs(appDir ApplicationDirectory) ([]string, error) {
+	manifests, err := plugin.FindPlugins(appDir.PluginDir(), appDir.PluginManifestDir())
+	if err != nil {
+		if errors.Is(err, plugin.ErrPluginsNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding plugins in %s: %w", appDir.PluginManifestDir(), err)
+	}
+	ids := make([]string, 0, len(manifests))
+	for id := range manifests {
+		ids = append(ids, id.String())
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -3,10 +3,14 @@
 package complytime
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
 	"github.com/stretchr/testify/require"
 )
 
@@ -73,3 +77,100 @@ func TestPluginOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyPlugin(t *testing.T) {
+	appDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+
+	executable := filepath.Join(appDir.PluginDir(), "myplugin")
+	require.NoError(t, os.WriteFile(executable, []byte("#!/bin/sh\n"), 0700))
+	manifestPath := filepath.Join(appDir.PluginManifestDir(), "c2p-myplugin-manifest.json")
+	wrongChecksum := strings.Repeat("0", 64)
+	writeManifest := func(id, sum string) {
+		manifest := fmt.Sprintf(`{"metadata": {"id": %q, "types": ["pvp"]}, "executablePath": "myplugin", "sha256": %q}`, id, sum)
+		require.NoError(t, os.WriteFile(manifestPath, []byte(manifest), 0600))
+	}
+
+	_, err = VerifyPlugin(appDir, "myplugin")
+	require.ErrorIs(t, err, ErrPluginManifestNotFound)
+
+	require.NoError(t, os.WriteFile(manifestPath, []byte("{"), 0600))
+	_, err = VerifyPlugin(appDir, "myplugin")
+	require.ErrorIs(t, err, ErrPluginManifestInvalid)
+
+	writeManifest("otherplugin", wrongChecksum)
+	_, err = VerifyPlugin(appDir, "myplugin")
+	require.ErrorIs(t, err, ErrPluginManifestInvalid)
+
+	writeManifest("myplugin", wrongChecksum)
+	_, err = VerifyPlugin(appDir, "myplugin")
+	require.ErrorIs(t, err, ErrPluginChecksum)
+
+	actual, err := fileChecksum(executable)
+	require.NoError(t, err)
+	writeManifest("myplugin", strings.ToUpper(actual))
+	manifest, err := VerifyPlugin(appDir, "myplugin")
+	require.NoError(t, err)
+	require.Equal(t, executable, manifest.ExecutablePath)
+
+	require.NoError(t, os.Chmod(executable, 0600))
+	_, err = VerifyPlugin(appDir, "myplugin")
+	require.ErrorIs(t, err, ErrPluginExecutable)
+}
+
+func TestResolvePluginOptions(t *testing.T) {
+	resultsDefault := "results.xml"
+	manifest := plugin.Manifest{
+		Metadata: plugin.Metadata{ID: "openscap"},
+		Configuration: []plugin.ConfigurationOption{
+			{Name: "workspace", Required: true},
+			{Name: "profile", Required: true},
+			{Name: "results", Default: &resultsDefault},
+			{Name: "policy"},
+		},
+	}
+
+	options, err := ResolvePluginOptions(manifest, PluginOptions{Workspace: "testworkspace"}, hclog.NewNullLogger())
+	require.NoError(t, err)
+	want := []PluginOption{
+		{Name: "workspace", Required: true, Value: "testworkspace", Source: OptionSourceComplyctl},
+		{Name: "profile", Required: true, Source: OptionSourceUnset},
+		{Name: "results", Value: "results.xml", Source: OptionSourceManifest},
+		{Name: "policy", Source: OptionSourceUnset},
+	}
+	require.Equal(t, want, options)
+
+	selections := PluginOptions{Workspace: "testworkspace", Profile: "testprofile", UserConfigRoot: testPluginConfigRoot}
+	options, err = ResolvePluginOptions(manifest, selections, hclog.NewNullLogger())
+	require.NoError(t, err)
+	require.Equal(t, PluginOption{Name: "profile", Required: true, Value: "testprofile", Source: OptionSourceComplyctl}, options[1])
+	require.Equal(t, PluginOption{Name: "results", Value: "results_test.xml", Source: testPluginConfigRoot}, options[2])
+
+	// Required options without a value fail as when the plugin is launched.
+	manifest.Configuration = append(manifest.Configuration, plugin.ConfigurationOption{Name: "datastream", Required: true})
+	_, err = ResolvePluginOptions(manifest, selections, hclog.NewNullLogger())
+	require.ErrorContains(t, err, `required value not supplied for option "datastream"`)
+}
+
+func TestFindPluginIDs(t *testing.T) {
+	appDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+
+	ids, err := FindPluginIDs(appDir)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	for _, id := range []string{"openscap", "myplugin"} {
+		require.NoError(t, os.WriteFile(filepath.Join(appDir.PluginDir(), id), []byte("#!/bin/sh\n"), 0700))
+		manifest := fmt.Sprintf(`{"metadata": {"id": %q, "types": ["pvp"]}, "executablePath": %q}`, id, id)
+		require.NoError(t, os.WriteFile(filepath.Join(appDir.PluginManifestDir(), "c2p-"+id+"-manifest.json"), []byte(manifest), 0600))
+	}
+	ids, err = FindPluginIDs(appDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"myplugin", "openscap"}, ids)
+
+	// Plugins are found the same way as when they are launched, so an invalid manifest is an error.
+	require.NoError(t, os.WriteFile(filepath.Join(appDir.PluginManifestDir(), "c2p-broken-manifest.json"), []byte("{}"), 0600))
+	_, err = FindPluginIDs(appDir)
+	require.Error(t, err)
+}
@@ -0,0 +1,374 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// RemediationDir is the directory of a plugin workspace containing the remediation
+// artifacts generated by the plugin.
+const RemediationDir = "remediations"
+
+// Remediation artifact types.
+const (
+	RemediationBash      = "bash"
+	RemediationAnsible   = "ansible"
+	RemediationBlueprint = "blueprint"
+)
+
+// remediationTypes maps the extensions of remediation artifacts to their type.
+var remediationTypes = map[string]string{
+	".sh":   RemediationBash,
+	".yml":  RemediationAnsible,
+	".yaml": RemediationAnsible,
+	".toml": RemediationBlueprint,
+}
+
+var (
+	// bashFixBegin and bashFixEnd delimit the fix of a rule in the scripts generated by OpenSCAP.
+	bashFixBegin = regexp.MustCompile(`^# BEGIN fix \(\d+ / \d+\) for '([^']+)'$`)
+	bashFixEnd   = regexp.MustCompile(`^# END fix for '([^']+)'$`)
+	// xccdfRulePrefix matches the namespace of XCCDF rule IDs, such as "xccdf_org.ssgproject.content_rule_".
+	xccdfRulePrefix = regexp.MustCompile(`^xccdf_[^_]+_rule_`)
+)
+
+// RemediationArtifact is a remediation file generated by a plugin in its workspace directory.
+type RemediationArtifact struct {
+	// Plugin is the name of the plugin workspace directory.
+	Plugin string `json:"plugin" yaml:"plugin"`
+	Type   string `json:"type" yaml:"type"`
+	Path   string `json:"path" yaml:"path"`
+
+	// header is the content of a script preceding the fixes.
+	header string
+	// plays are the plays of a playbook, with the tasks replaced by the fixes when exported.
+	plays []yaml.MapSlice
+	fixes []remediationFix
+}
+
+// remediationFix is the fix of one or more rules in a remediation artifact.
+type remediationFix struct {
+	// rules are the normalized IDs of the rules the fix applies to.
+	rules   []string
+	snippet string
+	// play and task locate the fix in a playbook.
+	play int
+	task interface{}
+}
+
+// RuleRemediation holds the fixes found in the remediation artifacts for a rule.
+type RuleRemediation struct {
+	RuleID   string           `json:"ruleId" yaml:"ruleId"`
+	Title    string           `json:"title,omitempty" yaml:"title,omitempty"`
+	Status   string           `json:"status" yaml:"status"`
+	Controls []string         `json:"controls,omitempty" yaml:"controls,omitempty"`
+	Fixes    []RemediationFix `json:"fixes" yaml:"fixes"`
+}
+
+// RemediationFix is the fix of a rule in a remediation artifact.
+type RemediationFix struct {
+	Plugin string `json:"plugin" yaml:"plugin"`
+	Type   string `json:"type" yaml:"type"`
+	Path   string `json:"path" yaml:"path"`
+	// Snippet is the part of the artifact fixing the rule.
+	Snippet string `json:"snippet,omitempty" yaml:"snippet,omitempty"`
+}
+
+// FindRemediationArtifacts reads the remediation artifacts generated by plugins in the
+// remediations directory of their workspace directory, sorted by plugin and path.
+// Files of unknown types are ignored.
+func FindRemediationArtifacts(workspace string) ([]RemediationArtifact, error) {
+	paths, err := filepath.Glob(filepath.Join(workspace, "*", RemediationDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	artifacts := []RemediationArtifact{}
+	for _, path := range paths {
+		remediationType, ok := remediationTypes[filepath.Ext(path)]
+		if !ok {
+			continue
+		}
+		artifact, err := ReadRemediationArtifact(path, remediationType)
+		if err != nil {
+			return nil, err
+		}
+		artifact.Plugin = filepath.Base(filepath.Dir(filepath.Dir(path)))
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, nil
+}
+
+// ReadRemediationArtifact reads a remediation artifact and splits it into fixes by rule.
+// Bash scripts are split on the fix delimiters written by OpenSCAP and Ansible playbooks
+// on the rule IDs in the task tags. Blueprints cannot be split by rule and have no fixes.
+func ReadRemediationArtifact(path, remediationType string) (RemediationArtifact, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return RemediationArtifact{}, err
+	}
+	artifact := RemediationArtifact{Type: remediationType, Path: path}
+	switch remediationType {
+	case RemediationBash:
+		artifact.header, artifact.fixes, err = parseBashFixes(data)
+	case RemediationAnsible:
+		artifact.plays, artifact.fixes, err = parseAnsibleFixes(data)
+	}
+	if err != nil {
+		return RemediationArtifact{}, fmt.Errorf("error reading remediation artifact %s: %w", path, err)
+	}
+	return artifact, nil
+}
+
+// parseBashFixes splits a script into the header preceding the first fix and the fixes.
+// The separator line preceding a fix delimiter belongs to the fix.
+func parseBashFixes(data []byte) (string, []remediationFix, error) {
+	var header []string
+	var fixes []remediationFix
+	var fix []string
+	var ruleID string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	var previous string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case ruleID == "":
+			if matches := bashFixBegin.FindStringSubmatch(line); matches != nil {
+				ruleID = matches[1]
+				fix = nil
+				if isBashSeparator(previous) {
+					fix = append(fix, previous)
+					if len(fixes) == 0 && len(header) > 0 {
+						header = header[:len(header)-1]
+					}
+				}
+				fix = append(fix, line)
+			} else if len(fixes) == 0 {
+				header = append(header, line)
+			}
+		default:
+			fix = append(fix, line)
+			if matches := bashFixEnd.FindStringSubmatch(line); matches != nil && matches[1] == ruleID {
+				fixes = append(fixes, remediationFix{
+					rules:   []string{NormalizeRemediationRuleID(ruleID)},
+					snippet: strings.Join(fix, "\n") + "\n",
+				})
+				ruleID = ""
+			}
+		}
+		previous = line
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, err
+	}
+	if ruleID != "" {
+		return "", nil, fmt.Errorf("fix for %s is not terminated", ruleID)
+	}
+	return strings.TrimRight(strings.Join(header, "\n"), "\n") + "\n", fixes, nil
+}
+
+func isBashSeparator(line string) bool {
+	return len(line) > 1 && strings.Trim(line, "#") == ""
+}
+
+// parseAnsibleFixes reads the plays of a playbook and makes a fix of each task,
+// applying to the rules found in its tags.
+func parseAnsibleFixes(data []byte) ([]yaml.MapSlice, []remediationFix, error) {
+	var plays []yaml.MapSlice
+	if err := yaml.UnmarshalWithOptions(data, &plays, yaml.UseOrderedMap()); err != nil {
+		return nil, nil, err
+	}
+	var fixes []remediationFix
+	for i, play := range plays {
+		tasks, _ := mapSliceValue(play, "tasks").([]interface{})
+		for _, task := range tasks {
+			taskMap, ok := task.(yaml.MapSlice)
+			if !ok {
+				continue
+			}
+			var rules []string
+			tags, _ := mapSliceValue(taskMap, "tags").([]interface{})
+			for _, tag := range tags {
+				rules = append(rules, NormalizeRemediationRuleID(fmt.Sprint(tag)))
+			}
+			snippet, err := yaml.MarshalWithOptions([]interface{}{task}, yaml.UseLiteralStyleIfMultiline(true))
+			if err != nil {
+				return nil, nil, err
+			}
+			fixes = append(fixes, remediationFix{rules: rules, snippet: string(snippet), play: i, task: task})
+		}
+	}
+	return plays, fixes, nil
+}
+
+func mapSliceValue(mapSlice yaml.MapSlice, key string) interface{} {
+	for _, item := range mapSlice {
+		if item.Key == key {
+			return item.Value
+		}
+	}
+	return nil
+}
+
+// NormalizeRemediationRuleID returns a rule ID without its XCCDF namespace, so rule IDs
+// from the component definitions, the XCCDF rule references and the playbook tags match.
+func NormalizeRemediationRuleID(ruleID string) string {
+	return xccdfRulePrefix.ReplaceAllString(ruleID, "")
+}
+
+// ruleRemediationIDs returns the normalized IDs identifying a rule in remediation artifacts.
+func ruleRemediationIDs(rule RuleResult) []string {
+	var ids []string
+	for _, id := range append([]string{rule.RuleID, rule.Title}, rule.CheckIDs...) {
+		if id == "" {
+			continue
+		}
+		if normalized := NormalizeRemediationRuleID(id); !slices.Contains(ids, normalized) {
+			ids = append(ids, normalized)
+		}
+	}
+	return ids
+}
+
+// matchingFixes returns the fixes of an artifact applying to any of the given rules.
+func (a RemediationArtifact) matchingFixes(ruleIDs []string) []remediationFix {
+	var fixes []remediationFix
+	for _, fix := range a.fixes {
+		for _, ruleID := range ruleIDs {
+			if slices.Contains(fix.rules, ruleID) {
+				fixes = append(fixes, fix)
+				break
+			}
+		}
+	}
+	return fixes
+}
+
+// FindRuleRemediation returns the fixes of a rule in the remediation artifacts.
+// Fixes of the same artifact are joined in a single snippet.
+func FindRuleRemediation(rule RuleResult, artifacts []RemediationArtifact) RuleRemediation {
+	remediation := RuleRemediation{
+		RuleID:   rule.RuleID,
+		Title:    rule.Title,
+		Status:   rule.Status,
+		Controls: rule.Controls,
+		Fixes:    []RemediationFix{},
+	}
+	ruleIDs := ruleRemediationIDs(rule)
+	for _, artifact := range artifacts {
+		fixes := artifact.matchingFixes(ruleIDs)
+		if len(fixes) == 0 {
+			continue
+		}
+		var snippets []string
+		for _, fix := range fixes {
+			snippets = append(snippets, fix.snippet)
+		}
+		remediation.Fixes = append(remediation.Fixes, RemediationFix{
+			Plugin:  artifact.Plugin,
+			Type:    artifact.Type,
+			Path:    artifact.Path,
+			Snippet: strings.Join(snippets, ""),
+		})
+	}
+	return remediation
+}
+
+// FailedRuleRemediations returns the fixes of the failing rules of the results.
+func FailedRuleRemediations(summary ResultsSummary, artifacts []RemediationArtifact) []RuleRemediation {
+	remediations := []RuleRemediation{}
+	for _, rule := range summary.Rules {
+		if rule.Status != StatusFail {
+			continue
+		}
+		remediations = append(remediations, FindRuleRemediation(rule, artifacts))
+	}
+	return remediations
+}
+
+// ExportRemediation writes the fixes of the given rules found in the artifacts of a type as a
+// single artifact, keeping the header of scripts and the plays of playbooks. Fixes are written
+// in the order of the artifacts, and fixes applying to several rules are written once.
+// It returns the exported artifact and the number of fixes written.
+func ExportRemediation(artifacts []RemediationArtifact, remediationType string, rules []RuleResult) ([]byte, int, error) {
+	var ruleIDs []string
+	for _, rule := range rules {
+		ruleIDs = append(ruleIDs, ruleRemediationIDs(rule)...)
+	}
+
+	var buf bytes.Buffer
+	var exportedPlays []yaml.MapSlice
+	count := 0
+	for _, artifact := range artifacts {
+		if artifact.Type != remediationType {
+			continue
+		}
+		fixes := artifact.matchingFixes(ruleIDs)
+		count += len(fixes)
+		switch remediationType {
+		case RemediationBash:
+			if buf.Len() == 0 {
+				buf.WriteString(artifact.header)
+			}
+			for _, fix := range fixes {
+				buf.WriteString("\n")
+				buf.WriteString(fix.snippet)
+			}
+		case RemediationAnsible:
+			exportedPlays = append(exportedPlays, exportPlays(artifact, fixes)...)
+		default:
+			return nil, 0, fmt.Errorf("remediation type %s cannot be exported by rule", remediationType)
+		}
+	}
+
+	if remediationType == RemediationAnsible {
+		if len(exportedPlays) == 0 {
+			return nil, 0, nil
+		}
+		data, err := yaml.MarshalWithOptions(exportedPlays, yaml.UseLiteralStyleIfMultiline(true))
+		if err != nil {
+			return nil, 0, fmt.Errorf("error marshalling playbook: %w", err)
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+	return buf.Bytes(), count, nil
+}
+
+// exportPlays returns the plays of a playbook with their tasks replaced by the given fixes.
+// Plays without fixes are left out.
+func exportPlays(artifact RemediationArtifact, fixes []remediationFix) []yaml.MapSlice {
+	var plays []yaml.MapSlice
+	for i, play := range artifact.plays {
+		var tasks []interface{}
+		for _, fix := range fixes {
+			if fix.play == i {
+				tasks = append(tasks, fix.task)
+			}
+		}
+		if len(tasks) == 0 {
+			continue
+		}
+		exported := make(yaml.MapSlice, 0, len(play))
+		for _, item := range play {
+			if item.Key == "tasks" {
+				item.Value = tasks
+			}
+			exported = append(exported, item)
+		}
+		plays = append(plays, exported)
+	}
+	return plays
+}
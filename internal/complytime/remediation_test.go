@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testRemediationScript = `#!/usr/bin/env bash
+###############################################################################
+#
+# Bash Remediation Script for Example Profile
+#
+###############################################################################
+
+###############################################################################
+# BEGIN fix (1 / 2) for 'xccdf_org.ssgproject.content_rule_package_aide_installed'
+###############################################################################
+(>&2 echo "Remediating rule 1/2: 'xccdf_org.ssgproject.content_rule_package_aide_installed'")
+dnf install -y "aide"
+# END fix for 'xccdf_org.ssgproject.content_rule_package_aide_installed'
+
+###############################################################################
+# BEGIN fix (2 / 2) for 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'
+###############################################################################
+(>&2 echo "Remediating rule 2/2: 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'")
+systemctl enable --now auditd.service
+# END fix for 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'
+`
+
+const testRemediationPlaybook = `---
+- name: Ansible Playbook for Example Profile
+  hosts: all
+  vars:
+    var_example: value
+  tasks:
+    - name: Gather the package facts
+      package_facts:
+        manager: auto
+      tags:
+        - package_aide_installed
+        - service_auditd_enabled
+    - name: Ensure aide is installed
+      package:
+        name: aide
+        state: present
+      tags:
+        - CCE-80844-4
+        - package_aide_installed
+    - name: Enable service auditd
+      systemd:
+        name: auditd
+        enabled: true
+      tags:
+        - service_auditd_enabled
+`
+
+func writeTestRemediations(t *testing.T) string {
+	workspace := t.TempDir()
+	dir := filepath.Join(workspace, "openscap", RemediationDir)
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "remediation-script.sh"), []byte(testRemediationScript), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "remediation-playbook.yml"), []byte(testRemediationPlaybook), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "remediation-blueprint.toml"), []byte("name = \"example\"\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("ignored"), 0600))
+	return workspace
+}
+
+func TestFindRemediationArtifacts(t *testing.T) {
+	workspace := writeTestRemediations(t)
+	artifacts, err := FindRemediationArtifacts(workspace)
+	require.NoError(t, err)
+	require.Len(t, artifacts, 3)
+	for i, expected := range []string{RemediationBlueprint, RemediationAnsible, RemediationBash} {
+		require.Equal(t, "openscap", artifacts[i].Plugin)
+		require.Equal(t, expected, artifacts[i].Type)
+	}
+	require.Empty(t, artifacts[0].fixes)
+	require.Len(t, artifacts[1].fixes, 3)
+	require.Len(t, artifacts[2].fixes, 2)
+
+	artifacts, err = FindRemediationArtifacts(t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, artifacts)
+}
+
+func TestNormalizeRemediationRuleID(t *testing.T) {
+	require.Equal(t, "package_aide_installed", NormalizeRemediationRuleID("xccdf_org.ssgproject.content_rule_package_aide_installed"))
+	require.Equal(t, "package_aide_installed", NormalizeRemediationRuleID("package_aide_installed"))
+}
+
+func TestFindRuleRemediation(t *testing.T) {
+	artifacts, err := FindRemediationArtifacts(writeTestRemediations(t))
+	require.NoError(t, err)
+
+	rule := RuleResult{
+		RuleID:   "package_aide_installed",
+		Title:    "xccdf_org.ssgproject.content_rule_package_aide_installed",
+		Status:   StatusFail,
+		Controls: []string{"ex-1"},
+	}
+	remediation := FindRuleRemediation(rule, artifacts)
+	require.Equal(t, "package_aide_installed", remediation.RuleID)
+	require.Equal(t, []string{"ex-1"}, remediation.Controls)
+	require.Len(t, remediation.Fixes, 2)
+
+	require.Equal(t, RemediationAnsible, remediation.Fixes[0].Type)
+	require.Contains(t, remediation.Fixes[0].Snippet, "name: Gather the package facts")
+	require.Contains(t, remediation.Fixes[0].Snippet, "name: Ensure aide is installed")
+	require.NotContains(t, remediation.Fixes[0].Snippet, "Enable service auditd")
+
+	require.Equal(t, RemediationBash, remediation.Fixes[1].Type)
+	require.Contains(t, remediation.Fixes[1].Snippet, "# BEGIN fix (1 / 2) for 'xccdf_org.ssgproject.content_rule_package_aide_installed'")
+	require.Contains(t, remediation.Fixes[1].Snippet, "dnf install -y \"aide\"\n# END fix")
+	require.NotContains(t, remediation.Fixes[1].Snippet, "auditd")
+
+	remediation = FindRuleRemediation(RuleResult{RuleID: "unknown", Status: StatusFail}, artifacts)
+	require.Empty(t, remediation.Fixes)
+}
+
+func TestFailedRuleRemediations(t *testing.T) {
+	artifacts, err := FindRemediationArtifacts(writeTestRemediations(t))
+	require.NoError(t, err)
+	summary := ResultsSummary{Rules: []RuleResult{
+		{RuleID: "package_aide_installed", Status: StatusPass},
+		{RuleID: "service_auditd_enabled", Status: StatusFail},
+		{RuleID: "unknown", Status: StatusError},
+	}}
+	remediations := FailedRuleRemediations(summary, artifacts)
+	require.Len(t, remediations, 1)
+	require.Equal(t, "service_auditd_enabled", remediations[0].RuleID)
+	require.Len(t, remediations[0].Fixes, 2)
+}
+
+func TestExportRemediation(t *testing.T) {
+	artifacts, err := FindRemediationArtifacts(writeTestRemediations(t))
+	require.NoError(t, err)
+	rules := []RuleResult{{RuleID: "service_auditd_enabled", Status: StatusFail}}
+
+	script, count, err := ExportRemediation(artifacts, RemediationBash, rules)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	expectedScript := `#!/usr/bin/env bash
+###############################################################################
+#
+# Bash Remediation Script for Example Profile
+#
+###############################################################################
+
+###############################################################################
+# BEGIN fix (2 / 2) for 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'
+###############################################################################
+(>&2 echo "Remediating rule 2/2: 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'")
+systemctl enable --now auditd.service
+# END fix for 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'
+`
+	require.Equal(t, expectedScript, string(script))
+
+	playbook, count, err := ExportRemediation(artifacts, RemediationAnsible, rules)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	plays, fixes, err := parseAnsibleFixes(playbook)
+	require.NoError(t, err)
+	require.Len(t, plays, 1)
+	require.Equal(t, "Ansible Playbook for Example Profile", mapSliceValue(plays[0], "name"))
+	require.NotNil(t, mapSliceValue(plays[0], "vars"))
+	require.Len(t, fixes, 2)
+	require.Contains(t, fixes[0].snippet, "Gather the package facts")
+	require.Contains(t, fixes[1].snippet, "Enable service auditd")
+
+	_, _, err = ExportRemediation(artifacts, RemediationBlueprint, rules)
+	require.EqualError(t, err, "remediation type blueprint cannot be exported by rule")
+
+	playbook, count, err = ExportRemediation(nil, RemediationAnsible, rules)
+	require.NoError(t, err)
+	require.Zero(t, count)
+	require.Empty(t, playbook)
+}
+
+func TestParseBashFixesUnterminated(t *testing.T) {
+	_, _, err := parseBashFixes([]byte("# BEGIN fix (1 / 1) for 'rule'\necho\n"))
+	require.EqualError(t, err, "fix for rule is not terminated")
+}
@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/url"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+//go:embed templates/posture.html
+var reportTemplates embed.FS
+
+// htmlReport is the data rendered by the HTML posture report template.
+type htmlReport struct {
+	Title         string
+	FrameworkID   string
+	CatalogTitle  string
+	Generated     string
+	Summary       ResultsSummary
+	PassRate      string
+	Frameworks    []htmlFramework
+	Sections      []htmlSection
+	UnmappedRules []htmlRule
+}
+
+// htmlSection lists the controls of the HTML posture report. Plans with several frameworks
+// have a section for each framework, with its FrameworkID and CatalogTitle.
+type htmlSection struct {
+	FrameworkID  string
+	CatalogTitle string
+	Controls     []htmlControl
+}
+
+// htmlFramework is the posture of a framework in the HTML posture report of plans with several frameworks.
+type htmlFramework struct {
+	FrameworkResult
+	PassRate string
+}
+
+// htmlControl is a control and its rule results in the HTML posture report.
+type htmlControl struct {
+	ControlResult
+	Anchor string
+	Title  string
+	Rules  []htmlRule
+}
+
+// htmlRule is a rule result with evidence links safe to render in the HTML posture report.
+type htmlRule struct {
+	RuleResult
+	Evidence []htmlEvidence
+}
+
+// htmlEvidence is an evidence link in the HTML posture report.
+// Href is empty when the evidence location cannot be rendered as a link.
+type htmlEvidence struct {
+	Href        template.URL
+	Location    string
+	Description string
+}
+
+// FrameworkCatalog is the catalog of a framework of an assessment plan.
+type FrameworkCatalog struct {
+	FrameworkID string
+	Catalog     *oscalTypes.Catalog
+}
+
+// GenerateHTMLReport renders OSCAL Assessment Results as a self-contained HTML posture report.
+// The report includes per-control status summaries, collapsible observation details and
+// evidence links. Styles are inlined so the file can be viewed offline. Control titles are
+// taken from the catalogs of the frameworks, the first one being the primary framework.
+// Plans with several frameworks get a summary and a section of controls for each framework.
+func GenerateHTMLReport(assessmentResults *oscalTypes.AssessmentResults, plan *oscalTypes.AssessmentPlan, catalogs ...FrameworkCatalog) ([]byte, error) {
+	tmpl, err := template.ParseFS(reportTemplates, "templates/posture.html")
+	if err != nil {
+		return nil, err
+	}
+
+	summary := SummarizeResults(assessmentResults, plan)
+	report := htmlReport{
+		Title:     assessmentResults.Metadata.Title,
+		Generated: assessmentResults.Metadata.LastModified.UTC().Format(time.RFC1123),
+		Summary:   summary,
+		PassRate:  fmt.Sprintf("%.1f%%", summary.RuleCounts.PassRate()),
+	}
+	if len(catalogs) > 0 {
+		report.FrameworkID = catalogs[0].FrameworkID
+	}
+
+	rules := make(map[string]htmlRule, len(summary.Rules))
+	for _, rule := range summary.Rules {
+		rules[rule.RuleID] = newHTMLRule(rule)
+		if len(rule.Controls) == 0 {
+			report.UnmappedRules = append(report.UnmappedRules, rules[rule.RuleID])
+		}
+	}
+
+	if len(summary.Frameworks) == 0 {
+		section := newHTMLSection("", summary.Controls, rules, catalogs)
+		report.CatalogTitle = section.CatalogTitle
+		section.CatalogTitle = ""
+		report.Sections = []htmlSection{section}
+	}
+	for _, framework := range summary.Frameworks {
+		report.Frameworks = append(report.Frameworks, htmlFramework{
+			FrameworkResult: framework,
+			PassRate:        fmt.Sprintf("%.1f%%", framework.RuleCounts.PassRate()),
+		})
+		report.Sections = append(report.Sections, newHTMLSection(framework.FrameworkID, framework.Controls, rules, catalogs))
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buffer, report); err != nil {
+		return nil, fmt.Errorf("error rendering HTML report: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// newHTMLSection prepares the controls of a framework for rendering, with the titles of the
+// framework catalog. An empty framework ID takes the catalog of the primary framework.
+func newHTMLSection(frameworkID string, controls []ControlResult, rules map[string]htmlRule, catalogs []FrameworkCatalog) htmlSection {
+	section := htmlSection{FrameworkID: frameworkID}
+	controlTitles := make(map[string]string)
+	for i, catalog := range catalogs {
+		if catalog.Catalog == nil || (frameworkID == "" && i > 0) || (frameworkID != "" && catalog.FrameworkID != frameworkID) {
+			continue
+		}
+		section.CatalogTitle = catalog.Catalog.Metadata.Title
+		controlTitles = CatalogControlTitles(catalog.Catalog)
+		break
+	}
+
+	anchorPrefix := "control-"
+	if frameworkID != "" {
+		anchorPrefix = fmt.Sprintf("control-%s-", frameworkID)
+	}
+	for _, control := range controls {
+		htmlCtrl := htmlControl{
+			ControlResult: control,
+			Anchor:        anchorPrefix + control.ControlID,
+			Title:         controlTitles[control.ControlID],
+		}
+		for _, ruleID := range control.Rules {
+			htmlCtrl.Rules = append(htmlCtrl.Rules, rules[ruleID])
+		}
+		section.Controls = append(section.Controls, htmlCtrl)
+	}
+	return section
+}
+
+// newHTMLRule prepares a rule result for rendering.
+func newHTMLRule(rule RuleResult) htmlRule {
+	htmlRule := htmlRule{RuleResult: rule}
+	for _, evidence := range rule.Evidence {
+		htmlRule.Evidence = append(htmlRule.Evidence, htmlEvidence{
+			Href:        evidenceURL(evidence.Href),
+			Location:    evidence.Href,
+			Description: evidence.Description,
+		})
+	}
+	return htmlRule
+}
+
+// evidenceURL returns the evidence location as a trusted URL for local files
+// and web locations, or an empty URL for any other scheme.
+func evidenceURL(href string) template.URL {
+	uri, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	switch uri.Scheme {
+	case "file", "http", "https":
+		// #nosec G203 -- the scheme is restricted to file and web locations.
+		return template.URL(uri.String())
+	default:
+		return ""
+	}
+}
+
+// CatalogControlTitles returns the titles of all controls in a catalog by control ID,
+// including controls nested in groups and in other controls.
+func CatalogControlTitles(catalog *oscalTypes.Catalog) map[string]string {
+	titles := make(map[string]string)
+	var addControls func(controls *[]oscalTypes.Control)
+	addControls = func(controls *[]oscalTypes.Control) {
+		if controls == nil {
+			return
+		}
+		for _, control := range *controls {
+			titles[control.ID] = control.Title
+			addControls(control.Controls)
+		}
+	}
+	var addGroups func(groups *[]oscalTypes.Group)
+	addGroups = func(groups *[]oscalTypes.Group) {
+		if groups == nil {
+			return
+		}
+		for _, group := range *groups {
+			addControls(group.Controls)
+			addGroups(group.Groups)
+		}
+	}
+	addControls(catalog.Controls)
+	addGroups(catalog.Groups)
+	return titles
+}
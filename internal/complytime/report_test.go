@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateHTMLReport(t *testing.T) {
+	results := testResults(StatusPass, StatusFail)
+	results.Metadata.Title = "Results <example>"
+	catalog := &oscalTypes.Catalog{
+		Metadata: oscalTypes.Metadata{Title: "Example Catalog"},
+		Groups: &[]oscalTypes.Group{
+			{
+				ID: "group-1",
+				Controls: &[]oscalTypes.Control{
+					{ID: "control-1", Title: "Control One"},
+					{ID: "control-2", Title: "Control Two"},
+				},
+			},
+		},
+	}
+
+	report, err := GenerateHTMLReport(results, testResultsPlan(), FrameworkCatalog{FrameworkID: "example", Catalog: catalog})
+	require.NoError(t, err)
+	html := string(report)
+
+	require.Contains(t, html, "<title>Results &lt;example&gt;</title>")
+	require.Contains(t, html, "<style>")
+	require.Contains(t, html, "Framework: <strong>example</strong>")
+	require.Contains(t, html, "Catalog: Example Catalog")
+	require.Contains(t, html, `<div class="value">50.0%</div>`)
+	require.Contains(t, html, `<a href="#control-control-1">control-1</a>`)
+	require.Contains(t, html, "Control Two")
+	require.Contains(t, html, `<details id="control-control-1" open>`)
+	require.Contains(t, html, `<span class="status status-fail">fail</span> <code>rule-2</code>`)
+	require.Contains(t, html, `<a href="file:///workspace/openscap/results/arf.xml">file:///workspace/openscap/results/arf.xml</a>`)
+	require.Contains(t, html, "No observations were recorded for this rule.")
+	require.NotContains(t, html, "Rules Without Controls")
+
+	// Without a plan, passing rules cannot be mapped to controls.
+	report, err = GenerateHTMLReport(results, nil, FrameworkCatalog{FrameworkID: "example"})
+	require.NoError(t, err)
+	require.Contains(t, string(report), "Rules Without Controls")
+	require.NotContains(t, string(report), "<h2>Frameworks</h2>")
+
+	// Plans with several frameworks get a summary and the controls of each framework,
+	// with the titles of its catalog.
+	baselineCatalog := &oscalTypes.Catalog{
+		Metadata: oscalTypes.Metadata{Title: "Baseline Catalog"},
+		Controls: &[]oscalTypes.Control{{ID: "b-1", Title: "Baseline One"}},
+	}
+	report, err = GenerateHTMLReport(testFrameworkResults(), testMergedPlan(t, hclog.NewNullLogger()),
+		FrameworkCatalog{FrameworkID: "cis", Catalog: catalog},
+		FrameworkCatalog{FrameworkID: "baseline", Catalog: baselineCatalog},
+	)
+	require.NoError(t, err)
+	html = string(report)
+	require.Contains(t, html, "Frameworks: <strong>cis</strong>, <strong>baseline</strong>")
+	require.Contains(t, html, "<h2>Frameworks</h2>")
+	require.Contains(t, html, "<td>baseline</td>\n      <td>0.0%</td>\n      <td>3</td>")
+	require.Contains(t, html, `<h2 id="framework-cis">Framework: cis</h2>`)
+	require.Contains(t, html, `<h2 id="framework-baseline">Framework: baseline</h2>
+<p class="meta">Catalog: Baseline Catalog</p>`)
+	require.Contains(t, html, `<details id="control-cis-control-2" open>`)
+	require.Contains(t, html, `<a href="#control-baseline-b-1">b-1</a></td>
+      <td>Baseline One</td>`)
+	require.Contains(t, html, `<a href="#control-baseline-b-3">b-3</a>`)
+	// The cis titles only appear in the table and the details of the cis section.
+	require.Equal(t, 2, strings.Count(html, "Control Two"))
+}
+
+func TestEvidenceURL(t *testing.T) {
+	tests := []struct {
+		href string
+		want template.URL
+	}{
+		{href: "file:///tmp/arf.xml", want: "file:///tmp/arf.xml"},
+		{href: "https://example.com/evidence", want: "https://example.com/evidence"},
+		{href: "javascript:alert(1)", want: ""},
+		{href: "relative/path.xml", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.href, func(t *testing.T) {
+			require.Equal(t, tt.want, evidenceURL(tt.href))
+		})
+	}
+}
+
+func TestCatalogControlTitles(t *testing.T) {
+	catalog := &oscalTypes.Catalog{
+		Controls: &[]oscalTypes.Control{{ID: "top", Title: "Top"}},
+		Groups: &[]oscalTypes.Group{
+			{
+				Controls: &[]oscalTypes.Control{
+					{
+						ID:       "parent",
+						Title:    "Parent",
+						Controls: &[]oscalTypes.Control{{ID: "child", Title: "Child"}},
+					},
+				},
+				Groups: &[]oscalTypes.Group{
+					{Controls: &[]oscalTypes.Control{{ID: "nested", Title: "Nested"}}},
+				},
+			},
+		},
+	}
+	want := map[string]string{"top": "Top", "parent": "Parent", "child": "Child", "nested": "Nested"}
+	require.Equal(t, want, CatalogControlTitles(catalog))
+}
@@ -0,0 +1,377 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// Status values for evaluated rules and controls in assessment results.
+const (
+	StatusPass        = "pass"
+	StatusFail        = "fail"
+	StatusError       = "error"
+	StatusNotAssessed = "not-assessed"
+)
+
+// statusRank orders statuses from least to most severe when aggregating
+// results for a rule or a control.
+var statusRank = map[string]int{
+	StatusNotAssessed: 0,
+	StatusPass:        1,
+	StatusError:       2,
+	StatusFail:        3,
+}
+
+// SubjectResult is the result of a rule evaluated against a single subject.
+type SubjectResult struct {
+	Title      string `json:"title" yaml:"title"`
+	ResourceID string `json:"resourceId,omitempty" yaml:"resourceId,omitempty"`
+	Result     string `json:"result" yaml:"result"`
+	Reason     string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// Evidence is a link to evidence supporting an observation.
+type Evidence struct {
+	Href        string `json:"href" yaml:"href"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// RuleResult summarizes the observations recorded for a single rule.
+type RuleResult struct {
+	RuleID   string          `json:"ruleId" yaml:"ruleId"`
+	Title    string          `json:"title,omitempty" yaml:"title,omitempty"`
+	CheckIDs []string        `json:"checkIds,omitempty" yaml:"checkIds,omitempty"`
+	Status   string          `json:"status" yaml:"status"`
+	Controls []string        `json:"controls,omitempty" yaml:"controls,omitempty"`
+	Subjects []SubjectResult `json:"subjects,omitempty" yaml:"subjects,omitempty"`
+	Evidence []Evidence      `json:"evidence,omitempty" yaml:"evidence,omitempty"`
+}
+
+// ControlResult summarizes the rule results for a single control.
+type ControlResult struct {
+	ControlID string       `json:"controlId" yaml:"controlId"`
+	Status    string       `json:"status" yaml:"status"`
+	Counts    StatusCounts `json:"counts" yaml:"counts"`
+	Rules     []string     `json:"rules" yaml:"rules"`
+}
+
+// StatusCounts counts results by status.
+type StatusCounts struct {
+	Pass        int `json:"pass" yaml:"pass"`
+	Fail        int `json:"fail" yaml:"fail"`
+	Error       int `json:"error" yaml:"error"`
+	NotAssessed int `json:"notAssessed" yaml:"notAssessed"`
+}
+
+// Add increments the count for the given status.
+func (c *StatusCounts) Add(status string) {
+	switch status {
+	case StatusPass:
+		c.Pass++
+	case StatusFail:
+		c.Fail++
+	case StatusError:
+		c.Error++
+	default:
+		c.NotAssessed++
+	}
+}
+
+// Total returns the sum of all counts.
+func (c StatusCounts) Total() int {
+	return c.Pass + c.Fail + c.Error + c.NotAssessed
+}
+
+// PassRate returns the percentage of passing results out of the
+// assessed results. When nothing was assessed, the pass rate is zero.
+func (c StatusCounts) PassRate() float64 {
+	assessed := c.Pass + c.Fail + c.Error
+	if assessed == 0 {
+		return 0
+	}
+	return float64(c.Pass) * 100 / float64(assessed)
+}
+
+// ResultsSummary is a per-rule and per-control view of OSCAL Assessment Results.
+type ResultsSummary struct {
+	// Rules are the rule results sorted by rule ID.
+	Rules []RuleResult `json:"rules" yaml:"rules"`
+	// Controls are the control results sorted by control ID.
+	Controls []ControlResult `json:"controls" yaml:"controls"`
+	// RuleCounts counts the rule results by status.
+	RuleCounts StatusCounts `json:"ruleCounts" yaml:"ruleCounts"`
+	// ControlCounts counts the control results by status.
+	ControlCounts StatusCounts `json:"controlCounts" yaml:"controlCounts"`
+	// Frameworks break down the results by framework for plans with several frameworks.
+	Frameworks []FrameworkResult `json:"frameworks,omitempty" yaml:"frameworks,omitempty"`
+}
+
+// Rule returns the result for a given rule ID.
+func (s ResultsSummary) Rule(ruleID string) (RuleResult, bool) {
+	for _, rule := range s.Rules {
+		if rule.RuleID == ruleID {
+			return rule, true
+		}
+	}
+	return RuleResult{}, false
+}
+
+// Control returns the result for a given control ID.
+func (s ResultsSummary) Control(controlID string) (ControlResult, bool) {
+	for _, control := range s.Controls {
+		if control.ControlID == controlID {
+			return control, true
+		}
+	}
+	return ControlResult{}, false
+}
+
+// SummarizeResults builds a ResultsSummary from OSCAL Assessment Results.
+//
+// Rules are mapped to controls using the activities of the given assessment plan.
+// The plan is optional. When it is nil, only rules with findings can be mapped to
+// controls because passing observations carry no control information.
+func SummarizeResults(assessmentResults *oscalTypes.AssessmentResults, plan *oscalTypes.AssessmentPlan) ResultsSummary {
+	return summarizeResults(assessmentResults, plan, "")
+}
+
+// SummarizeFrameworkResults builds a ResultsSummary of a framework of the assessment plan, with
+// the rules in scope of the framework and the findings of the controls selected for it.
+func SummarizeFrameworkResults(assessmentResults *oscalTypes.AssessmentResults, plan *oscalTypes.AssessmentPlan, frameworkID string) ResultsSummary {
+	return summarizeResults(FrameworkAssessmentResults(assessmentResults, plan, frameworkID), plan, frameworkID)
+}
+
+// summarizeResults builds a ResultsSummary, keeping only the rules in scope of the framework
+// when a framework ID is given.
+func summarizeResults(assessmentResults *oscalTypes.AssessmentResults, plan *oscalTypes.AssessmentPlan, frameworkID string) ResultsSummary {
+	ruleControls, checkRules := planRuleMappings(plan, frameworkID)
+	observationControls := findingControls(assessmentResults)
+
+	rulesByID := make(map[string]*RuleResult)
+	var ruleIDs []string
+	getRule := func(ruleID string) *RuleResult {
+		rule, ok := rulesByID[ruleID]
+		if !ok {
+			rule = &RuleResult{RuleID: ruleID, Status: StatusNotAssessed}
+			rulesByID[ruleID] = rule
+			ruleIDs = append(ruleIDs, ruleID)
+		}
+		return rule
+	}
+
+	for _, result := range assessmentResults.Results {
+		if result.Observations == nil {
+			continue
+		}
+		for _, observation := range *result.Observations {
+			ruleID, checkID := observationRuleID(observation, checkRules)
+			if _, inScope := ruleControls[ruleID]; frameworkID != "" && !inScope {
+				continue
+			}
+
+			rule := getRule(ruleID)
+			if observation.Props != nil && rule.Title == "" {
+				rule.Title = observation.Title
+			}
+			if checkID != "" && !slices.Contains(rule.CheckIDs, checkID) {
+				rule.CheckIDs = append(rule.CheckIDs, checkID)
+			}
+			if observation.Subjects != nil {
+				for _, subject := range *observation.Subjects {
+					subjectResult := newSubjectResult(subject)
+					rule.Subjects = append(rule.Subjects, subjectResult)
+					rule.Status = worseStatus(rule.Status, normalizeStatus(subjectResult.Result))
+				}
+			}
+			if observation.RelevantEvidence != nil {
+				for _, evidence := range *observation.RelevantEvidence {
+					rule.Evidence = append(rule.Evidence, Evidence{Href: evidence.Href, Description: evidence.Description})
+				}
+			}
+			for _, controlID := range observationControls[observation.UUID] {
+				if !slices.Contains(rule.Controls, controlID) {
+					rule.Controls = append(rule.Controls, controlID)
+				}
+			}
+		}
+	}
+
+	// Rules in scope of the plan without any observations were not assessed.
+	for ruleID := range ruleControls {
+		getRule(ruleID)
+	}
+
+	controlsByID := make(map[string]*ControlResult)
+	summary := ResultsSummary{
+		Rules:    []RuleResult{},
+		Controls: []ControlResult{},
+	}
+	sort.Strings(ruleIDs)
+	for _, ruleID := range ruleIDs {
+		rule := rulesByID[ruleID]
+		for _, controlID := range ruleControls[ruleID] {
+			if !slices.Contains(rule.Controls, controlID) {
+				rule.Controls = append(rule.Controls, controlID)
+			}
+		}
+		sort.Strings(rule.Controls)
+		for _, controlID := range rule.Controls {
+			control, ok := controlsByID[controlID]
+			if !ok {
+				control = &ControlResult{ControlID: controlID, Status: StatusNotAssessed}
+				controlsByID[controlID] = control
+			}
+			control.Rules = append(control.Rules, ruleID)
+			control.Counts.Add(rule.Status)
+			control.Status = worseStatus(control.Status, rule.Status)
+		}
+		summary.RuleCounts.Add(rule.Status)
+		summary.Rules = append(summary.Rules, *rule)
+	}
+
+	for _, control := range controlsByID {
+		summary.ControlCounts.Add(control.Status)
+		summary.Controls = append(summary.Controls, *control)
+	}
+	sort.Slice(summary.Controls, func(i, j int) bool {
+		return summary.Controls[i].ControlID < summary.Controls[j].ControlID
+	})
+	if plan != nil && frameworkID == "" {
+		if frameworkIDs, err := PlanFrameworkIDs(plan); err == nil && len(frameworkIDs) > 1 {
+			summary.Frameworks = summarizeFrameworks(summary, plan, frameworkIDs)
+		}
+	}
+	return summary
+}
+
+// observationRuleID returns the rule and the check of an observation. The rule is read from the
+// observation properties, or else is the rule of the check in the plan or the check itself.
+func observationRuleID(observation oscalTypes.Observation, checkRules map[string]string) (string, string) {
+	checkID := observation.Title
+	var ruleID string
+	if observation.Props != nil {
+		if prop, found := extensions.GetTrestleProp(extensions.AssessmentCheckIdProp, *observation.Props); found {
+			checkID = prop.Value
+		}
+		if prop, found := extensions.GetTrestleProp(extensions.AssessmentRuleIdProp, *observation.Props); found {
+			ruleID = prop.Value
+		}
+	}
+	if ruleID == "" {
+		ruleID = checkRules[checkID]
+	}
+	if ruleID == "" {
+		ruleID = checkID
+	}
+	return ruleID, checkID
+}
+
+// planRuleMappings returns the controls for each rule and the rule for each check
+// defined in the in-scope activities of an assessment plan. When a framework ID is
+// given, only the controls selected for that framework are returned.
+func planRuleMappings(plan *oscalTypes.AssessmentPlan, frameworkID string) (map[string][]string, map[string]string) {
+	ruleControls := make(map[string][]string)
+	checkRules := make(map[string]string)
+	if plan == nil || plan.LocalDefinitions == nil || plan.LocalDefinitions.Activities == nil {
+		return ruleControls, checkRules
+	}
+	for _, activity := range *plan.LocalDefinitions.Activities {
+		// Skipped activities have no related controls.
+		if activity.Title == "" || activity.RelatedControls == nil {
+			continue
+		}
+		var controls []string
+		for _, selection := range activity.RelatedControls.ControlSelections {
+			if selection.IncludeControls == nil || !selectionInFramework(selection, frameworkID) {
+				continue
+			}
+			for _, control := range *selection.IncludeControls {
+				if !slices.Contains(controls, control.ControlId) {
+					controls = append(controls, control.ControlId)
+				}
+			}
+		}
+		if len(controls) == 0 {
+			continue
+		}
+		ruleControls[activity.Title] = controls
+		if activity.Steps != nil {
+			for _, step := range *activity.Steps {
+				checkRules[step.Title] = activity.Title
+			}
+		}
+	}
+	return ruleControls, checkRules
+}
+
+// findingControls returns the controls targeted by findings for each related
+// observation UUID.
+func findingControls(assessmentResults *oscalTypes.AssessmentResults) map[string][]string {
+	controls := make(map[string][]string)
+	for _, result := range assessmentResults.Results {
+		if result.Findings == nil {
+			continue
+		}
+		for _, finding := range *result.Findings {
+			if finding.RelatedObservations == nil {
+				continue
+			}
+			controlID := strings.TrimSuffix(finding.Target.TargetId, "_smt")
+			for _, related := range *finding.RelatedObservations {
+				controls[related.ObservationUuid] = append(controls[related.ObservationUuid], controlID)
+			}
+		}
+	}
+	return controls
+}
+
+// newSubjectResult extracts the result properties of an observation subject.
+func newSubjectResult(subject oscalTypes.SubjectReference) SubjectResult {
+	subjectResult := SubjectResult{Title: subject.Title}
+	if subject.Props == nil {
+		return subjectResult
+	}
+	for _, prop := range *subject.Props {
+		switch prop.Name {
+		case "result":
+			subjectResult.Result = prop.Value
+		case "reason":
+			subjectResult.Reason = prop.Value
+		case "resource-id":
+			subjectResult.ResourceID = prop.Value
+		}
+	}
+	return subjectResult
+}
+
+// normalizeStatus maps a subject result value to a summary status.
+// Results other than pass and fail, such as warnings, are treated as errors.
+func normalizeStatus(result string) string {
+	switch result {
+	case StatusPass, StatusFail:
+		return result
+	case "":
+		return StatusNotAssessed
+	default:
+		return StatusError
+	}
+}
+
+// worseStatus returns the more severe of two statuses.
+func worseStatus(current, next string) string {
+	if statusRank[next] > statusRank[current] {
+		return next
+	}
+	return current
+}
+
+// IsFailing returns true if the status represents a failed or errored evaluation.
+func IsFailing(status string) bool {
+	return status == StatusFail || status == StatusError
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+// testObservation returns an observation for a rule with a single subject result.
+func testObservation(uuid, ruleID, checkID, result string) oscalTypes.Observation {
+	return oscalTypes.Observation{
+		UUID:  uuid,
+		Title: "xccdf_" + ruleID,
+		Props: &[]oscalTypes.Property{
+			{Name: extensions.AssessmentRuleIdProp, Value: ruleID, Ns: extensions.TrestleNameSpace},
+			{Name: extensions.AssessmentCheckIdProp, Value: checkID, Ns: extensions.TrestleNameSpace},
+		},
+		Subjects: &[]oscalTypes.SubjectReference{
+			{
+				Title: "Host localhost",
+				Props: &[]oscalTypes.Property{
+					{Name: "resource-id", Value: "localhost", Ns: extensions.TrestleNameSpace},
+					{Name: "result", Value: result, Ns: extensions.TrestleNameSpace},
+					{Name: "reason", Value: "openscap rule-result is " + result, Ns: extensions.TrestleNameSpace},
+				},
+			},
+		},
+		RelevantEvidence: &[]oscalTypes.RelevantEvidence{
+			{Href: "file:///workspace/openscap/results/arf.xml", Description: "ARF_FILE"},
+		},
+	}
+}
+
+// testActivity returns a plan activity for a rule with a single check.
+func testActivity(ruleID, checkID string, controls ...string) oscalTypes.Activity {
+	var selected []oscalTypes.AssessedControlsSelectControlById
+	for _, control := range controls {
+		selected = append(selected, oscalTypes.AssessedControlsSelectControlById{ControlId: control})
+	}
+	activity := oscalTypes.Activity{
+		Title: ruleID,
+		Steps: &[]oscalTypes.Step{{Title: checkID}},
+	}
+	if len(selected) > 0 {
+		activity.RelatedControls = &oscalTypes.ReviewedControls{
+			ControlSelections: []oscalTypes.AssessedControls{{IncludeControls: &selected}},
+		}
+	}
+	return activity
+}
+
+// testResultsPlan returns a plan mapping rule-1 and rule-2 to control-1, rule-2 and
+// rule-3 to control-2 and a skipped rule-4.
+func testResultsPlan() *oscalTypes.AssessmentPlan {
+	return &oscalTypes.AssessmentPlan{
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			Activities: &[]oscalTypes.Activity{
+				testActivity("rule-1", "check-1", "control-1"),
+				testActivity("rule-2", "check-2", "control-1", "control-2"),
+				testActivity("rule-3", "check-3", "control-2"),
+				testActivity("rule-4", "check-4"),
+			},
+		},
+	}
+}
+
+// testResults returns assessment results where rule-1 passes, rule-2 fails
+// and rule-3 has no results.
+func testResults(rule1, rule2 string) *oscalTypes.AssessmentResults {
+	observations := []oscalTypes.Observation{
+		testObservation("obs-1", "rule-1", "check-1", rule1),
+		testObservation("obs-2", "rule-2", "check-2", rule2),
+		{UUID: "obs-3", Title: "check-3"},
+	}
+	var findings []oscalTypes.Finding
+	for _, obs := range observations[:2] {
+		result, _ := extensions.GetTrestleProp("result", *(*obs.Subjects)[0].Props)
+		if result.Value == StatusPass {
+			continue
+		}
+		findings = append(findings, oscalTypes.Finding{
+			Target:              oscalTypes.FindingTarget{TargetId: "control-1_smt"},
+			RelatedObservations: &[]oscalTypes.RelatedObservation{{ObservationUuid: obs.UUID}},
+		})
+	}
+	return &oscalTypes.AssessmentResults{
+		Results: []oscalTypes.Result{
+			{
+				Observations: &observations,
+				Findings:     &findings,
+			},
+		},
+	}
+}
+
+func TestSummarizeResults(t *testing.T) {
+	summary := SummarizeResults(testResults(StatusPass, StatusFail), testResultsPlan())
+
+	require.Len(t, summary.Rules, 3)
+	rule1, found := summary.Rule("rule-1")
+	require.True(t, found)
+	require.Equal(t, StatusPass, rule1.Status)
+	require.Equal(t, []string{"control-1"}, rule1.Controls)
+	require.Equal(t, []string{"check-1"}, rule1.CheckIDs)
+	require.Equal(t, "xccdf_rule-1", rule1.Title)
+	require.Equal(t, []Evidence{{Href: "file:///workspace/openscap/results/arf.xml", Description: "ARF_FILE"}}, rule1.Evidence)
+	require.Equal(t, []SubjectResult{
+		{Title: "Host localhost", ResourceID: "localhost", Result: StatusPass, Reason: "openscap rule-result is pass"},
+	}, rule1.Subjects)
+
+	rule2, found := summary.Rule("rule-2")
+	require.True(t, found)
+	require.Equal(t, StatusFail, rule2.Status)
+	require.Equal(t, []string{"control-1", "control-2"}, rule2.Controls)
+
+	// Unassessed observations are mapped to rules through the plan steps.
+	rule3, found := summary.Rule("rule-3")
+	require.True(t, found)
+	require.Equal(t, StatusNotAssessed, rule3.Status)
+	require.Equal(t, []string{"control-2"}, rule3.Controls)
+
+	// Skipped activities are not in scope.
+	_, found = summary.Rule("rule-4")
+	require.False(t, found)
+
+	wantControls := []ControlResult{
+		{
+			ControlID: "control-1",
+			Status:    StatusFail,
+			Counts:    StatusCounts{Pass: 1, Fail: 1},
+			Rules:     []string{"rule-1", "rule-2"},
+		},
+		{
+			ControlID: "control-2",
+			Status:    StatusFail,
+			Counts:    StatusCounts{Fail: 1, NotAssessed: 1},
+			Rules:     []string{"rule-2", "rule-3"},
+		},
+	}
+	require.Equal(t, wantControls, summary.Controls)
+	require.Equal(t, StatusCounts{Pass: 1, Fail: 1, NotAssessed: 1}, summary.RuleCounts)
+	require.Equal(t, StatusCounts{Fail: 2}, summary.ControlCounts)
+}
+
+func TestSummarizeResultsWithoutPlan(t *testing.T) {
+	summary := SummarizeResults(testResults(StatusPass, StatusError), nil)
+
+	require.Len(t, summary.Rules, 3)
+	rule1, _ := summary.Rule("rule-1")
+	require.Empty(t, rule1.Controls)
+	rule2, _ := summary.Rule("rule-2")
+	require.Equal(t, StatusError, rule2.Status)
+	require.Equal(t, []string{"control-1"}, rule2.Controls)
+	// Without a plan, the check ID is used as the rule ID for unassessed observations.
+	_, found := summary.Rule("check-3")
+	require.True(t, found)
+
+	require.Equal(t, []ControlResult{
+		{ControlID: "control-1", Status: StatusError, Counts: StatusCounts{Error: 1}, Rules: []string{"rule-2"}},
+	}, summary.Controls)
+}
+
+func TestStatusCounts(t *testing.T) {
+	counts := StatusCounts{}
+	require.Equal(t, float64(0), counts.PassRate())
+
+	for _, status := range []string{StatusPass, StatusPass, StatusPass, StatusFail, StatusNotAssessed} {
+		counts.Add(status)
+	}
+	require.Equal(t, 5, counts.Total())
+	require.Equal(t, float64(75), counts.PassRate())
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"encoding/json"
+	"fmt"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolURI = "https://github.com/complytime/complyctl"
+)
+
+// sarifLog is the top-level object of a SARIF 2.1.0 log file.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string           `json:"id"`
+	Name             string           `json:"name,omitempty"`
+	ShortDescription *sarifMessage    `json:"shortDescription,omitempty"`
+	Properties       *sarifProperties `json:"properties,omitempty"`
+}
+
+type sarifProperties struct {
+	Tags []string `json:"tags"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID      string            `json:"ruleId"`
+	RuleIndex   int               `json:"ruleIndex"`
+	Kind        string            `json:"kind"`
+	Level       string            `json:"level"`
+	Message     sarifMessage      `json:"message"`
+	Locations   []sarifLocation   `json:"locations,omitempty"`
+	Attachments []sarifAttachment `json:"attachments,omitempty"`
+	Properties  *sarifProperties  `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name,omitempty"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind"`
+}
+
+type sarifAttachment struct {
+	Description      *sarifMessage         `json:"description,omitempty"`
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// GenerateSARIF exports OSCAL Assessment Results as a SARIF 2.1.0 log.
+//
+// Each assessed rule becomes a SARIF rule tagged with its control IDs and each subject
+// result becomes a SARIF result. Failures are reported at the "error" level and evaluation
+// errors at the "warning" level. Passing subjects are included with the "pass" kind and
+// rules without subject results with the "notApplicable" kind, so dashboards can show coverage.
+// The plan is optional and is used to map passing rules to controls.
+func GenerateSARIF(assessmentResults *oscalTypes.AssessmentResults, plan *oscalTypes.AssessmentPlan, toolVersion string) ([]byte, error) {
+	summary := SummarizeResults(assessmentResults, plan)
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "complyctl",
+				Version:        toolVersion,
+				InformationURI: sarifToolURI,
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	for index, rule := range summary.Rules {
+		descriptor := sarifRule{ID: rule.RuleID, Name: rule.Title}
+		if rule.Title != "" {
+			descriptor.ShortDescription = &sarifMessage{Text: rule.Title}
+		}
+		if len(rule.Controls) > 0 {
+			descriptor.Properties = &sarifProperties{Tags: rule.Controls}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, descriptor)
+
+		var attachments []sarifAttachment
+		for _, evidence := range rule.Evidence {
+			attachment := sarifAttachment{ArtifactLocation: sarifArtifactLocation{URI: evidence.Href}}
+			if evidence.Description != "" {
+				attachment.Description = &sarifMessage{Text: evidence.Description}
+			}
+			attachments = append(attachments, attachment)
+		}
+
+		if len(rule.Subjects) == 0 {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    rule.RuleID,
+				RuleIndex: index,
+				Kind:      "notApplicable",
+				Level:     "none",
+				Message:   sarifMessage{Text: fmt.Sprintf("Rule %s was not assessed.", rule.RuleID)},
+			})
+			continue
+		}
+		for _, subject := range rule.Subjects {
+			kind, level := sarifKindAndLevel(normalizeStatus(subject.Result))
+			message := subject.Reason
+			if message == "" {
+				message = fmt.Sprintf("Rule %s result is %s on %s.", rule.RuleID, subject.Result, subject.Title)
+			}
+			result := sarifResult{
+				RuleID:      rule.RuleID,
+				RuleIndex:   index,
+				Kind:        kind,
+				Level:       level,
+				Message:     sarifMessage{Text: message},
+				Attachments: attachments,
+				Locations: []sarifLocation{
+					{
+						LogicalLocations: []sarifLogicalLocation{
+							{Name: subject.Title, FullyQualifiedName: subject.ResourceID, Kind: "resource"},
+						},
+					},
+				},
+			}
+			if len(rule.Controls) > 0 {
+				result.Properties = &sarifProperties{Tags: rule.Controls}
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// sarifKindAndLevel returns the SARIF result kind and level for a summary status.
+func sarifKindAndLevel(status string) (string, string) {
+	switch status {
+	case StatusFail:
+		return "fail", "error"
+	case StatusError:
+		return "fail", "warning"
+	case StatusPass:
+		return "pass", "none"
+	default:
+		return "notApplicable", "none"
+	}
+}
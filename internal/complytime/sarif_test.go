@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSARIF(t *testing.T) {
+	data, err := GenerateSARIF(testResults(StatusPass, StatusFail), testResultsPlan(), "v1.0.0")
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(data, &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	driver := log.Runs[0].Tool.Driver
+	require.Equal(t, "complyctl", driver.Name)
+	require.Equal(t, "v1.0.0", driver.Version)
+	require.Len(t, driver.Rules, 3)
+	require.Equal(t, "rule-2", driver.Rules[1].ID)
+	require.Equal(t, "xccdf_rule-2", driver.Rules[1].Name)
+	require.Equal(t, []string{"control-1", "control-2"}, driver.Rules[1].Properties.Tags)
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 3)
+
+	require.Equal(t, "rule-1", results[0].RuleID)
+	require.Equal(t, "pass", results[0].Kind)
+	require.Equal(t, "none", results[0].Level)
+
+	failed := results[1]
+	require.Equal(t, "rule-2", failed.RuleID)
+	require.Equal(t, 1, failed.RuleIndex)
+	require.Equal(t, "fail", failed.Kind)
+	require.Equal(t, "error", failed.Level)
+	require.Equal(t, "openscap rule-result is fail", failed.Message.Text)
+	require.Equal(t, []string{"control-1", "control-2"}, failed.Properties.Tags)
+	require.Equal(t, "localhost", failed.Locations[0].LogicalLocations[0].FullyQualifiedName)
+	require.Equal(t, "file:///workspace/openscap/results/arf.xml", failed.Attachments[0].ArtifactLocation.URI)
+	require.Equal(t, "ARF_FILE", failed.Attachments[0].Description.Text)
+
+	require.Equal(t, "rule-3", results[2].RuleID)
+	require.Equal(t, "notApplicable", results[2].Kind)
+	require.Empty(t, results[2].Locations)
+}
+
+func TestSarifKindAndLevel(t *testing.T) {
+	tests := []struct {
+		status    string
+		wantKind  string
+		wantLevel string
+	}{
+		{status: StatusFail, wantKind: "fail", wantLevel: "error"},
+		{status: StatusError, wantKind: "fail", wantLevel: "warning"},
+		{status: StatusPass, wantKind: "pass", wantLevel: "none"},
+		{status: StatusNotAssessed, wantKind: "notApplicable", wantLevel: "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			kind, level := sarifKindAndLevel(tt.status)
+			require.Equal(t, tt.wantKind, kind)
+			require.Equal(t, tt.wantLevel, level)
+		})
+	}
+}
@@ -4,23 +4,44 @@ package complytime
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
 )
 
 // WriteAssessmentResults writes AssessmentResults as a JSON file to a given path location.
 func WriteAssessmentResults(assessmentResults *oscalTypes.AssessmentResults, assessmentResultsLocation string) error {
+	assessmentResultsJson, err := MarshalAssessmentResults(assessmentResults)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(assessmentResultsLocation, assessmentResultsJson, 0600)
+
+}
 
+// MarshalAssessmentResults returns AssessmentResults as an OSCAL JSON document.
+func MarshalAssessmentResults(assessmentResults *oscalTypes.AssessmentResults) ([]byte, error) {
 	oscalModels := oscalTypes.OscalModels{
 		AssessmentResults: assessmentResults,
 	}
+	return json.MarshalIndent(oscalModels, "", " ")
+}
 
-	assessmentResultsJson, err := json.MarshalIndent(oscalModels, "", " ")
+// ReadAssessmentResults reads assessment results from a given file path.
+func ReadAssessmentResults(assessmentResultsPath string, validator validation.Validator) (*oscalTypes.AssessmentResults, error) {
+	file, err := os.Open(assessmentResultsPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer file.Close()
 
-	return os.WriteFile(assessmentResultsLocation, assessmentResultsJson, 0600)
-
+	assessmentResults, err := models.NewAssessmentResults(file, validator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load assessment results from %s: %w", assessmentResultsPath, err)
+	}
+	return assessmentResults, nil
 }
@@ -3,8 +3,14 @@
 package complytime
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
 	"sort"
+	"strings"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/hashicorp/go-hclog"
@@ -12,12 +18,19 @@ import (
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 )
 
-// ControlEntry represents a control in the assessment scope
+// regexPatternPrefix marks a control or rule ID of a scope config as a regular expression.
+const regexPatternPrefix = "re:"
+
+// ControlEntry represents a control in the assessment scope.
+// ControlID, IncludeRules and ExcludeRules accept patterns, see MatchScopePattern.
 type ControlEntry struct {
 	ControlID    string   `yaml:"controlId"`
 	ControlTitle string   `yaml:"controlTitle"`
 	IncludeRules []string `yaml:"includeRules"`
 	ExcludeRules []string `yaml:"excludeRules,omitempty"`
+	// Parameters sets rule parameter values, by parameter ID, for the rules
+	// assessing the control. They take precedence over the global parameters.
+	Parameters map[string]string `yaml:"parameters,omitempty"`
 }
 
 // AssessmentScope sets up the yaml mapping type for writing to config file.
@@ -28,8 +41,14 @@ type AssessmentScope struct {
 	FrameworkID string `yaml:"frameworkId"`
 	// IncludeControls defines controls that are in scope
 	// of an assessment.
-	IncludeControls    []ControlEntry `yaml:"includeControls"`
-	GlobalExcludeRules []string       `yaml:"globalExcludeRules,omitempty"`
+	IncludeControls []ControlEntry `yaml:"includeControls"`
+	// ExcludeControls removes the matching controls from
+	// the included controls.
+	ExcludeControls    []string `yaml:"excludeControls,omitempty"`
+	GlobalExcludeRules []string `yaml:"globalExcludeRules,omitempty"`
+	// Parameters sets rule parameter values, by parameter ID,
+	// for all the rules in scope.
+	Parameters map[string]string `yaml:"parameters,omitempty"`
 }
 
 // NewAssessmentScope creates an AssessmentScope struct for a given framework id.
@@ -128,23 +147,254 @@ func NewAssessmentScopeFromCDs(frameworkId string, appDir ApplicationDirectory,
 	return scope, nil
 }
 
+// ControlRules returns the IDs of the rules mapped to each control implemented for the given
+// framework id in the OSCAL Component Definitions, sorted by rule ID.
+func ControlRules(frameworkID string, cds ...oscalTypes.ComponentDefinition) map[string][]string {
+	rulesByControl := make(map[string][]string)
+	for _, componentDef := range cds {
+		if componentDef.Components == nil {
+			continue
+		}
+		for _, component := range *componentDef.Components {
+			if component.ControlImplementations == nil {
+				continue
+			}
+			for _, ci := range *component.ControlImplementations {
+				if ci.Props == nil {
+					continue
+				}
+				frameworkProp, found := extensions.GetTrestleProp(extensions.FrameworkProp, *ci.Props)
+				if !found || frameworkProp.Value != frameworkID {
+					continue
+				}
+				for _, ir := range ci.ImplementedRequirements {
+					if ir.Props == nil {
+						continue
+					}
+					for _, prop := range *ir.Props {
+						if prop.Name == extensions.RuleIdProp && !slices.Contains(rulesByControl[ir.ControlId], prop.Value) {
+							rulesByControl[ir.ControlId] = append(rulesByControl[ir.ControlId], prop.Value)
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, rules := range rulesByControl {
+		sort.Strings(rules)
+	}
+	return rulesByControl
+}
+
+// Validate checks the control and rule patterns of the AssessmentScope.
+func (a AssessmentScope) Validate() error {
+	var errs []error
+	check := func(patterns ...string) {
+		for _, pattern := range patterns {
+			if _, err := compileScopePattern(pattern); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	for _, entry := range a.IncludeControls {
+		check(entry.ControlID)
+		check(entry.IncludeRules...)
+		check(entry.ExcludeRules...)
+	}
+	check(a.ExcludeControls...)
+	check(a.GlobalExcludeRules...)
+	return errors.Join(errs...)
+}
+
+// ScopeError is an entry of a scope config that does not match the controls and rules of the framework.
+type ScopeError struct {
+	// Path locates the entry in the scope config, such as "includeControls[0].excludeRules[1]".
+	Path string `json:"path" yaml:"path"`
+	// Message describes the problem.
+	Message string `json:"message" yaml:"message"`
+}
+
+// String returns the error in the form "path: message".
+func (e ScopeError) String() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// CheckControls checks the control and rule IDs of the AssessmentScope against the controls available
+// for the framework and the IDs of the rules mapped to each control, in controlRules. Unknown IDs are
+// reported with the closest known ID, and patterns matching no control or rule are reported.
+// Invalid patterns are reported by Validate.
+func (a AssessmentScope) CheckControls(frameworkID string, available []ControlEntry, controlRules map[string][]string) []ScopeError {
+	var scopeErrors []ScopeError
+	addError := func(path, format string, args ...interface{}) {
+		scopeErrors = append(scopeErrors, ScopeError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if a.FrameworkID != frameworkID {
+		addError("frameworkId", "framework %q does not match the plan framework %q", a.FrameworkID, frameworkID)
+	}
+
+	controlIDs := make([]string, 0, len(available))
+	for _, entry := range available {
+		controlIDs = append(controlIDs, entry.ControlID)
+	}
+	var ruleIDs []string
+	for _, rules := range controlRules {
+		ruleIDs = append(ruleIDs, rules...)
+	}
+	slices.Sort(ruleIDs)
+	ruleIDs = slices.Compact(ruleIDs)
+
+	// checkControl returns the known controls matching a control ID or pattern.
+	checkControl := func(path, id string) []string {
+		pattern, err := compileScopePattern(id)
+		if err != nil {
+			return nil
+		}
+		var matched []string
+		for _, controlID := range controlIDs {
+			if pattern.Match(controlID) {
+				matched = append(matched, controlID)
+			}
+		}
+		switch {
+		case len(matched) > 0:
+		case IsScopePattern(id):
+			addError(path, "pattern %q matches no control", id)
+		default:
+			addError(path, "unknown control %q%s", id, suggestion(id, controlIDs))
+		}
+		return matched
+	}
+	// checkRule checks a rule ID or pattern against the rules of the controls of a control entry,
+	// or against all rules when controls is nil.
+	checkRule := func(path, id string, entry ControlEntry, controls []string) {
+		pattern, err := compileScopePattern(id)
+		if err != nil {
+			return
+		}
+		if IsScopePattern(id) {
+			if !slices.ContainsFunc(ruleIDs, pattern.Match) {
+				addError(path, "pattern %q matches no rule", id)
+			}
+			return
+		}
+		if controls == nil {
+			if !slices.Contains(ruleIDs, id) {
+				addError(path, "unknown rule %q%s", id, suggestion(id, ruleIDs))
+			}
+			return
+		}
+		var controlRuleIDs []string
+		for _, controlID := range controls {
+			controlRuleIDs = append(controlRuleIDs, controlRules[controlID]...)
+		}
+		switch {
+		case slices.Contains(controlRuleIDs, id):
+		case slices.Contains(ruleIDs, id) && IsScopePattern(entry.ControlID):
+			addError(path, "rule %q is not mapped to the controls matching %q", id, entry.ControlID)
+		case slices.Contains(ruleIDs, id):
+			addError(path, "rule %q is not mapped to control %q", id, entry.ControlID)
+		default:
+			addError(path, "unknown rule %q%s", id, suggestion(id, controlRuleIDs))
+		}
+	}
+
+	for i, entry := range a.IncludeControls {
+		entryPath := fmt.Sprintf("includeControls[%d]", i)
+		controls := checkControl(entryPath+".controlId", entry.ControlID)
+		if len(controls) == 0 {
+			continue
+		}
+		for j, rule := range entry.IncludeRules {
+			checkRule(fmt.Sprintf("%s.includeRules[%d]", entryPath, j), rule, entry, controls)
+		}
+		for j, rule := range entry.ExcludeRules {
+			checkRule(fmt.Sprintf("%s.excludeRules[%d]", entryPath, j), rule, entry, controls)
+		}
+	}
+	for i, control := range a.ExcludeControls {
+		checkControl(fmt.Sprintf("excludeControls[%d]", i), control)
+	}
+	for i, rule := range a.GlobalExcludeRules {
+		checkRule(fmt.Sprintf("globalExcludeRules[%d]", i), rule, ControlEntry{}, nil)
+	}
+	return scopeErrors
+}
+
+// suggestion returns a hint with the candidate closest to an unknown ID, or an empty
+// string when no candidate is close enough to be a likely typo.
+func suggestion(id string, candidates []string) string {
+	maxDistance := max(1, len(id)/4)
+	var closest string
+	for _, candidate := range candidates {
+		distance := levenshteinDistance(id, candidate)
+		if distance < maxDistance || (distance == maxDistance && (closest == "" || candidate < closest)) {
+			closest = candidate
+			maxDistance = distance
+		}
+	}
+	if closest == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %q?", closest)
+}
+
+// levenshteinDistance returns the number of single character edits turning a into b.
+func levenshteinDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// FindControlEntry returns the entry including a control, the entry with the control ID or else the
+// first entry with a pattern matching it. Controls matching ExcludeControls are not included.
+func (a AssessmentScope) FindControlEntry(controlID string) (ControlEntry, bool) {
+	entry, found := a.compile(hclog.NewNullLogger()).controlEntry(controlID)
+	return entry.ControlEntry, found
+}
+
 // ApplyScope alters the given OSCAL Assessment Plan based on the AssessmentScope.
+// Invalid patterns are logged and match no control or rule.
 func (a AssessmentScope) ApplyScope(assessmentPlan *oscalTypes.AssessmentPlan, logger hclog.Logger) {
 
 	// This is a thin wrapper right now, but the goal to expand to different areas
 	// of customization.
-	a.applyControlScope(assessmentPlan, logger)
-	a.applyRuleScope(assessmentPlan, logger)
+	scope := a.compile(logger)
+	scope.applyControlScope(assessmentPlan, logger)
+	scope.applyRuleScope(assessmentPlan, logger)
+	scope.applyParameters(assessmentPlan, logger)
 }
 
 // applyControlScope alters the AssessedControls of the given OSCAL Assessment Plan by the AssessmentScope
-// IncludeControls.
-func (a AssessmentScope) applyControlScope(assessmentPlan *oscalTypes.AssessmentPlan, logger hclog.Logger) {
+// IncludeControls and ExcludeControls.
+func (c compiledScope) applyControlScope(assessmentPlan *oscalTypes.AssessmentPlan, logger hclog.Logger) {
 	// "Any control specified within exclude-controls must first be within a range of explicitly
 	// included controls, via include-controls or include-all."
+	// Patterns are resolved against the controls selected in the plan, and the IDs given in the scope.
+	candidates := planControlIDs(assessmentPlan)
+	for _, entry := range c.controls {
+		if !IsScopePattern(entry.ControlID) {
+			candidates.Add(entry.ControlID)
+		}
+	}
 	includedControls := includeControlsSet{}
-	for _, entry := range a.IncludeControls {
-		includedControls.Add(entry.ControlID)
+	for controlID := range candidates {
+		if _, found := c.controlEntry(controlID); found {
+			includedControls.Add(controlID)
+		}
 	}
 	logger.Debug("Found included controls", "count", len(includedControls))
 	for _, controlT := range assessmentPlan.ReviewedControls.ControlSelections {
@@ -192,38 +442,12 @@ func (a AssessmentScope) applyControlScope(assessmentPlan *oscalTypes.Assessment
 }
 
 // applyRuleScope alters the AssessedControls of activities based on IncludeRules and ExcludeRules configuration
-func (a AssessmentScope) applyRuleScope(assessmentPlan *oscalTypes.AssessmentPlan, logger hclog.Logger) {
-	// Convert global exclude rules to a map for fast lookup
-	globalExcludeRules := make(map[string]struct{})
-	for _, rule := range a.GlobalExcludeRules {
-		globalExcludeRules[rule] = struct{}{}
-	}
-
+func (c compiledScope) applyRuleScope(assessmentPlan *oscalTypes.AssessmentPlan, logger hclog.Logger) {
 	// Check if globalExcludeRules contains "*" (exclude all rules globally)
-	_, hasGlobalWildcard := globalExcludeRules["*"]
-	if hasGlobalWildcard {
+	if slices.Contains(c.globalExcludeRules.patterns(), "*") {
 		logger.Warn("Global exclude rules contains '*' - all rules will be excluded from all controls")
 	}
-
-	// Build a map of control ID to ControlEntry for quick lookup
-	controlRuleConfig := make(map[string]ControlEntry)
-	for _, entry := range a.IncludeControls {
-		// Normalize the entry before storing it in the map
-		normalizedEntry := entry
-
-		// If globalExcludeRules contains "*", all rules are globally excluded
-		if hasGlobalWildcard {
-			normalizedEntry.IncludeRules = []string{} // Clear includeRules since all rules are globally excluded
-			normalizedEntry.ExcludeRules = []string{} // Clear excludeRules since global takes precedence
-		} else if a.isRuleInList("*", normalizedEntry.ExcludeRules) {
-			// If excludeRules contains "*", includeRules doesn't matter
-			normalizedEntry.IncludeRules = []string{} // Clear includeRules since they don't matter
-		} else if len(normalizedEntry.IncludeRules) == 0 {
-			normalizedEntry.IncludeRules = []string{"*"}
-		}
-		controlRuleConfig[entry.ControlID] = normalizedEntry
-	}
-	logger.Debug("Applying rule scope filtering", "globalExcludeRules", len(globalExcludeRules))
+	logger.Debug("Applying rule scope filtering", "globalExcludeRules", len(c.globalExcludeRules))
 
 	if assessmentPlan.LocalDefinitions != nil {
 		if assessmentPlan.LocalDefinitions.Activities != nil {
@@ -240,7 +464,7 @@ func (a AssessmentScope) applyRuleScope(assessmentPlan *oscalTypes.AssessmentPla
 					controlSelections := activity.RelatedControls.ControlSelections
 					for controlSelectionI := range controlSelections {
 						controlSelection := &controlSelections[controlSelectionI]
-						a.filterControlSelectionByRule(controlSelection, activity.Title, controlRuleConfig, globalExcludeRules, logger, activity.Title)
+						c.filterControlSelectionByRule(controlSelection, activity.Title, logger)
 						if controlSelection.IncludeControls == nil {
 							activity.RelatedControls = nil
 							if activity.Props == nil {
@@ -268,7 +492,7 @@ func (a AssessmentScope) applyRuleScope(assessmentPlan *oscalTypes.AssessmentPla
 						controlSelections := step.ReviewedControls.ControlSelections
 						for controlSelectionI := range controlSelections {
 							controlSelection := &controlSelections[controlSelectionI]
-							a.filterControlSelectionByRule(controlSelection, activity.Title, controlRuleConfig, globalExcludeRules, logger, activity.Title)
+							c.filterControlSelectionByRule(controlSelection, activity.Title, logger)
 							if controlSelection.IncludeControls == nil {
 								activity.RelatedControls.ControlSelections = nil
 								step.ReviewedControls = nil
@@ -290,6 +514,110 @@ func (a AssessmentScope) applyRuleScope(assessmentPlan *oscalTypes.AssessmentPla
 	}
 }
 
+// applyParameters sets the rule parameters of the activities to the values of the AssessmentScope.
+// Plugins receive a single value for each parameter, so a value set for a control applies to all the
+// rules with the parameter once a rule in scope assesses the control. Conflicting values set for
+// different controls are logged and the first one is kept.
+func (c compiledScope) applyParameters(assessmentPlan *oscalTypes.AssessmentPlan, logger hclog.Logger) {
+	if assessmentPlan.LocalDefinitions == nil || assessmentPlan.LocalDefinitions.Activities == nil {
+		return
+	}
+	activities := *assessmentPlan.LocalDefinitions.Activities
+
+	usedParameters := make(map[string]bool)
+	controlValues := make(map[string]string)
+	for _, activity := range activities {
+		parameters := activityParameters(activity)
+		for _, parameter := range parameters {
+			usedParameters[parameter.Name] = true
+		}
+		// Skipped activities have no related controls.
+		if len(parameters) == 0 || activity.RelatedControls == nil {
+			continue
+		}
+		for _, controlSelection := range activity.RelatedControls.ControlSelections {
+			if controlSelection.IncludeControls == nil {
+				continue
+			}
+			for _, control := range *controlSelection.IncludeControls {
+				entry, found := c.controlEntry(control.ControlId)
+				if !found {
+					continue
+				}
+				for _, parameter := range parameters {
+					value, found := entry.Parameters[parameter.Name]
+					if !found {
+						continue
+					}
+					if previous, set := controlValues[parameter.Name]; set {
+						if previous != value {
+							logger.Warn("Conflicting values for rule parameter, keeping the first one", "parameter", parameter.Name, "control", control.ControlId, "value", previous)
+						}
+						continue
+					}
+					controlValues[parameter.Name] = value
+				}
+			}
+		}
+	}
+
+	values := make(map[string]string, len(c.parameters)+len(controlValues))
+	maps.Copy(values, c.parameters)
+	maps.Copy(values, controlValues)
+
+	var unusedParameters []string
+	for name := range c.parameters {
+		unusedParameters = append(unusedParameters, name)
+	}
+	for _, entry := range c.controls {
+		for name := range entry.Parameters {
+			unusedParameters = append(unusedParameters, name)
+		}
+	}
+	slices.Sort(unusedParameters)
+	for _, name := range slices.Compact(unusedParameters) {
+		if !usedParameters[name] {
+			logger.Warn("Rule parameter is not used by the rules of the plan", "parameter", name)
+		}
+	}
+
+	for activityI := range activities {
+		activity := &activities[activityI]
+		if activity.Props == nil {
+			continue
+		}
+		for propI := range *activity.Props {
+			prop := &(*activity.Props)[propI]
+			if !isParameterProp(*prop) {
+				continue
+			}
+			if value, found := values[prop.Name]; found {
+				logger.Debug("Setting rule parameter", "rule", activity.Title, "parameter", prop.Name, "value", value)
+				prop.Value = value
+			}
+		}
+	}
+}
+
+// activityParameters returns the rule parameter properties of an activity.
+func activityParameters(activity oscalTypes.Activity) []oscalTypes.Property {
+	if activity.Props == nil {
+		return nil
+	}
+	var parameters []oscalTypes.Property
+	for _, prop := range *activity.Props {
+		if isParameterProp(prop) {
+			parameters = append(parameters, prop)
+		}
+	}
+	return parameters
+}
+
+// isParameterProp returns whether a property of an activity is a rule parameter.
+func isParameterProp(prop oscalTypes.Property) bool {
+	return prop.Class == extensions.TestParameterClass && prop.Ns == extensions.TrestleNameSpace
+}
+
 func filterControlSelection(controlSelection *oscalTypes.AssessedControls, includedControls includeControlsSet) {
 	// The new included controls should be the intersection of
 	// the originally included controls and the newly included controls.
@@ -324,17 +652,19 @@ func filterControlSelection(controlSelection *oscalTypes.AssessedControls, inclu
 	}
 }
 
-// filterControlSelectionByRule removes controls from a selection if the activity's rule should be excluded for those controls
-func (a AssessmentScope) filterControlSelectionByRule(controlSelection *oscalTypes.AssessedControls, activityRuleID string, controlRuleConfig map[string]ControlEntry, globalExcludeRules map[string]struct{}, logger hclog.Logger, activityTitle string) {
+// filterControlSelectionByRule removes controls from a selection if the activity's rule should be excluded for those controls.
+// Controls the rule is excluded from by their control entry are recorded in the excluded controls of the selection,
+// so that regenerating the plan can tell them from controls newly mapped to the rule.
+func (c compiledScope) filterControlSelectionByRule(controlSelection *oscalTypes.AssessedControls, activityRuleID string, logger hclog.Logger) {
 	if controlSelection.IncludeControls == nil {
-		logger.Debug("No controls to filter for activity", "activity", activityTitle)
+		logger.Debug("No controls to filter for activity", "activity", activityRuleID)
 		return
 	}
 
-	var filteredControls []oscalTypes.AssessedControlsSelectControlById
+	var filteredControls, excludedControls []oscalTypes.AssessedControlsSelectControlById
 
 	for _, control := range *controlSelection.IncludeControls {
-		controlEntry, exists := controlRuleConfig[control.ControlId]
+		controlEntry, exists := c.controlEntry(control.ControlId)
 		if !exists {
 			// Control not in our scope configuration, keep it
 			filteredControls = append(filteredControls, control)
@@ -343,21 +673,19 @@ func (a AssessmentScope) filterControlSelectionByRule(controlSelection *oscalTyp
 
 		shouldKeepControl := true
 
-		// Check global exclude rules first (highest priority)
-		if _, isGloballyExcluded := globalExcludeRules[activityRuleID]; isGloballyExcluded {
+		if c.globalExcludeRules.Match(activityRuleID) {
+			// Check global exclude rules first (highest priority)
 			shouldKeepControl = false
 			logger.Debug("Removing control from activity due to globally excluded rule", "control", control.ControlId, "rule", activityRuleID)
-		} else if _, isAllGloballyExcluded := globalExcludeRules["*"]; isAllGloballyExcluded {
-			// Check if "*" is globally excluded (exclude all rules)
-			shouldKeepControl = false
-			logger.Debug("Removing control from activity due to global exclude all rules", "control", control.ControlId, "rule", activityRuleID)
-		} else if a.isRuleInList(activityRuleID, controlEntry.ExcludeRules) {
+		} else if controlEntry.excludeRules.Match(activityRuleID) {
 			// Check if rule is in control-specific exclude list
 			shouldKeepControl = false
+			excludedControls = append(excludedControls, control)
 			logger.Debug("Removing control from activity due to control-specific excluded rule", "control", control.ControlId, "rule", activityRuleID)
-		} else if !a.isRuleInList("*", controlEntry.IncludeRules) && !a.isRuleInList(activityRuleID, controlEntry.IncludeRules) {
-			// Check if rule should be included (if not using wildcard)
+		} else if len(controlEntry.includeRules) > 0 && !controlEntry.includeRules.Match(activityRuleID) {
+			// Check if rule should be included, all rules are included by default
 			shouldKeepControl = false
+			excludedControls = append(excludedControls, control)
 			logger.Debug("Removing control from activity due to rule not in include list", "control", control.ControlId, "rule", activityRuleID)
 		}
 
@@ -369,21 +697,180 @@ func (a AssessmentScope) filterControlSelectionByRule(controlSelection *oscalTyp
 	// Update the control selection
 	if len(filteredControls) == 0 {
 		controlSelection.IncludeControls = nil
-	} else {
-		*controlSelection.IncludeControls = filteredControls
+		return
+	}
+	*controlSelection.IncludeControls = filteredControls
+	if len(excludedControls) > 0 {
+		if controlSelection.ExcludeControls == nil {
+			controlSelection.ExcludeControls = &[]oscalTypes.AssessedControlsSelectControlById{}
+		}
+		*controlSelection.ExcludeControls = append(*controlSelection.ExcludeControls, excludedControls...)
+	}
+}
+
+// planControlIDs returns the IDs of the controls explicitly selected in an assessment plan.
+func planControlIDs(assessmentPlan *oscalTypes.AssessmentPlan) includeControlsSet {
+	controlIDs := includeControlsSet{}
+	addSelections := func(selections []oscalTypes.AssessedControls) {
+		for _, selection := range selections {
+			if selection.IncludeControls == nil {
+				continue
+			}
+			for _, control := range *selection.IncludeControls {
+				controlIDs.Add(control.ControlId)
+			}
+		}
 	}
+	addSelections(assessmentPlan.ReviewedControls.ControlSelections)
+	if assessmentPlan.LocalDefinitions != nil && assessmentPlan.LocalDefinitions.Activities != nil {
+		for _, activity := range *assessmentPlan.LocalDefinitions.Activities {
+			if activity.RelatedControls != nil {
+				addSelections(activity.RelatedControls.ControlSelections)
+			}
+		}
+	}
+	return controlIDs
+}
+
+// compiledScope is an AssessmentScope with compiled patterns.
+type compiledScope struct {
+	controls           []compiledControlEntry
+	excludeControls    scopePatterns
+	globalExcludeRules scopePatterns
+	parameters         map[string]string
+}
+
+// compiledControlEntry is a ControlEntry with compiled patterns.
+type compiledControlEntry struct {
+	ControlEntry
+	control      scopePattern
+	includeRules scopePatterns
+	excludeRules scopePatterns
+}
+
+// compile compiles the patterns of the AssessmentScope. Invalid patterns are logged and left out.
+func (a AssessmentScope) compile(logger hclog.Logger) compiledScope {
+	compilePatterns := func(patterns []string) scopePatterns {
+		var compiled scopePatterns
+		for _, pattern := range patterns {
+			scopePattern, err := compileScopePattern(pattern)
+			if err != nil {
+				logger.Warn("Ignoring scope pattern", "error", err)
+				continue
+			}
+			compiled = append(compiled, scopePattern)
+		}
+		return compiled
+	}
+
+	scope := compiledScope{
+		excludeControls:    compilePatterns(a.ExcludeControls),
+		globalExcludeRules: compilePatterns(a.GlobalExcludeRules),
+		parameters:         a.Parameters,
+	}
+	for _, entry := range a.IncludeControls {
+		control, err := compileScopePattern(entry.ControlID)
+		if err != nil {
+			logger.Warn("Ignoring scope control", "error", err)
+			continue
+		}
+		scope.controls = append(scope.controls, compiledControlEntry{
+			ControlEntry: entry,
+			control:      control,
+			includeRules: compilePatterns(entry.IncludeRules),
+			excludeRules: compilePatterns(entry.ExcludeRules),
+		})
+	}
+	return scope
 }
 
-// isRuleInList checks if a rule ID exists in a list of rules
-func (a AssessmentScope) isRuleInList(ruleID string, ruleList []string) bool {
-	for _, rule := range ruleList {
-		if rule == ruleID {
+// controlEntry returns the entry of an included control. An entry with the control ID takes
+// precedence over the first entry with a pattern matching it. Excluded controls have no entry.
+func (c compiledScope) controlEntry(controlID string) (compiledControlEntry, bool) {
+	if c.excludeControls.Match(controlID) {
+		return compiledControlEntry{}, false
+	}
+	for _, entry := range c.controls {
+		if entry.ControlID == controlID {
+			return entry, true
+		}
+	}
+	for _, entry := range c.controls {
+		if entry.control.Match(controlID) {
+			return entry, true
+		}
+	}
+	return compiledControlEntry{}, false
+}
+
+// scopePattern matches control or rule IDs, see MatchScopePattern.
+type scopePattern struct {
+	pattern string
+	regex   *regexp.Regexp
+}
+
+func compileScopePattern(pattern string) (scopePattern, error) {
+	if expr, found := strings.CutPrefix(pattern, regexPatternPrefix); found {
+		regex, err := regexp.Compile(expr)
+		if err != nil {
+			return scopePattern{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		return scopePattern{pattern: pattern, regex: regex}, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return scopePattern{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return scopePattern{pattern: pattern}, nil
+}
+
+func (p scopePattern) Match(id string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(id)
+	}
+	if p.pattern == id {
+		return true
+	}
+	matched, _ := path.Match(p.pattern, id)
+	return matched
+}
+
+type scopePatterns []scopePattern
+
+// Match returns whether any of the patterns matches the ID.
+func (p scopePatterns) Match(id string) bool {
+	for _, pattern := range p {
+		if pattern.Match(id) {
 			return true
 		}
 	}
 	return false
 }
 
+func (p scopePatterns) patterns() []string {
+	patterns := make([]string, 0, len(p))
+	for _, pattern := range p {
+		patterns = append(patterns, pattern.pattern)
+	}
+	return patterns
+}
+
+// IsScopePattern returns whether a control or rule ID of a scope config is a pattern.
+func IsScopePattern(id string) bool {
+	return strings.HasPrefix(id, regexPatternPrefix) || strings.ContainsAny(id, "*?[")
+}
+
+// MatchScopePattern returns whether a control or rule ID matches a pattern of a scope config.
+// Patterns prefixed with "re:", such as "re:^r3[0-9]$", are regular expressions. Other patterns
+// are globs such as "ac-*", where "*" matches any sequence of characters, "?" any single character
+// and "[...]" a character class. Invalid patterns match nothing.
+func MatchScopePattern(pattern, id string) bool {
+	compiled, err := compileScopePattern(pattern)
+	if err != nil {
+		return false
+	}
+	return compiled.Match(id)
+}
+
 type includeControlsSet map[string]struct{}
 
 func (i includeControlsSet) Add(controlID string) {
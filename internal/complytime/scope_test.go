@@ -2,6 +2,10 @@
 package complytime
 
 import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
@@ -86,6 +90,61 @@ func TestNewAssessmentScopeFromCDs(t *testing.T) {
 	require.Equal(t, wantScope, scope)
 }
 
+func TestControlRules(t *testing.T) {
+	frameworkProps := &[]oscalTypes.Property{
+		{
+			Name:  extensions.FrameworkProp,
+			Value: "example",
+			Ns:    extensions.TrestleNameSpace,
+		},
+	}
+	ruleProps := func(ruleIDs ...string) *[]oscalTypes.Property {
+		var props []oscalTypes.Property
+		for _, ruleID := range ruleIDs {
+			props = append(props, oscalTypes.Property{Name: extensions.RuleIdProp, Value: ruleID})
+		}
+		return &props
+	}
+	cd := oscalTypes.ComponentDefinition{
+		Components: &[]oscalTypes.DefinedComponent{
+			{
+				Title: "Component",
+				ControlImplementations: &[]oscalTypes.ControlImplementationSet{
+					{
+						Props: frameworkProps,
+						ImplementedRequirements: []oscalTypes.ImplementedRequirementControlImplementation{
+							{ControlId: "control-1", Props: ruleProps("rule-2", "rule-1")},
+							{ControlId: "control-2"},
+						},
+					},
+					{
+						Props: &[]oscalTypes.Property{
+							{Name: extensions.FrameworkProp, Value: "other", Ns: extensions.TrestleNameSpace},
+						},
+						ImplementedRequirements: []oscalTypes.ImplementedRequirementControlImplementation{
+							{ControlId: "control-3", Props: ruleProps("rule-3")},
+						},
+					},
+				},
+			},
+			{
+				Title: "AnotherComponent",
+				ControlImplementations: &[]oscalTypes.ControlImplementationSet{
+					{
+						Props: frameworkProps,
+						ImplementedRequirements: []oscalTypes.ImplementedRequirementControlImplementation{
+							{ControlId: "control-1", Props: ruleProps("rule-1", "rule-4")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	rules := ControlRules("example", cd)
+	require.Equal(t, map[string][]string{"control-1": {"rule-1", "rule-2", "rule-4"}}, rules)
+}
+
 func TestAssessmentScope_ApplyScope(t *testing.T) {
 	testLogger := hclog.NewNullLogger()
 
@@ -344,6 +403,12 @@ func TestAssessmentScope_ApplyRuleScope(t *testing.T) {
 										ControlId: "control-2",
 									},
 								},
+								// The exclusion is recorded for plan updates.
+								ExcludeControls: &[]oscalTypes.AssessedControlsSelectControlById{
+									{
+										ControlId: "control-1",
+									},
+								},
 							},
 						},
 					},
@@ -653,3 +718,413 @@ func TestAssessmentScope_ApplyRuleScope(t *testing.T) {
 		})
 	}
 }
+
+// activityControls returns the controls selected by each activity of the plan,
+// or nil for skipped activities.
+func activityControls(plan *oscalTypes.AssessmentPlan) map[string][]string {
+	controls := make(map[string][]string)
+	for _, activity := range *plan.LocalDefinitions.Activities {
+		controls[activity.Title] = nil
+		if activity.RelatedControls == nil {
+			continue
+		}
+		for _, selection := range activity.RelatedControls.ControlSelections {
+			if selection.IncludeControls == nil {
+				continue
+			}
+			for _, control := range *selection.IncludeControls {
+				controls[activity.Title] = append(controls[activity.Title], control.ControlId)
+			}
+		}
+	}
+	return controls
+}
+
+func TestAssessmentScope_ApplyScopePatterns(t *testing.T) {
+	newPlan := func() *oscalTypes.AssessmentPlan {
+		activity := func(ruleID string, controlIDs ...string) oscalTypes.Activity {
+			var selected []oscalTypes.AssessedControlsSelectControlById
+			for _, controlID := range controlIDs {
+				selected = append(selected, oscalTypes.AssessedControlsSelectControlById{ControlId: controlID})
+			}
+			return oscalTypes.Activity{
+				Title:           ruleID,
+				RelatedControls: &oscalTypes.ReviewedControls{ControlSelections: []oscalTypes.AssessedControls{{IncludeControls: &selected}}},
+			}
+		}
+		return &oscalTypes.AssessmentPlan{
+			ReviewedControls: oscalTypes.ReviewedControls{
+				ControlSelections: []oscalTypes.AssessedControls{{IncludeAll: &oscalTypes.IncludeAll{}}},
+			},
+			LocalDefinitions: &oscalTypes.LocalDefinitions{
+				Activities: &[]oscalTypes.Activity{
+					activity("audit_enabled", "ac-1", "ac-2", "r30"),
+					activity("audit_rules", "ac-2", "r31", "r4"),
+					activity("password_length", "ia-5"),
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		scope          AssessmentScope
+		wantControls   []string
+		wantActivities map[string][]string
+	}{
+		{
+			name: "Success/GlobAndRegexControls",
+			scope: AssessmentScope{IncludeControls: []ControlEntry{
+				{ControlID: "ac-*"},
+				{ControlID: "re:^r3[0-9]$"},
+			}},
+			wantControls: []string{"ac-1", "ac-2", "r30", "r31"},
+			wantActivities: map[string][]string{
+				"audit_enabled":   {"ac-1", "ac-2", "r30"},
+				"audit_rules":     {"ac-2", "r31"},
+				"password_length": nil,
+			},
+		},
+		{
+			name: "Success/ExcludeControls",
+			scope: AssessmentScope{
+				IncludeControls: []ControlEntry{{ControlID: "*", IncludeRules: []string{"*"}}},
+				ExcludeControls: []string{"ac-2", "re:^r"},
+			},
+			wantControls: []string{"ac-1", "ia-5"},
+			wantActivities: map[string][]string{
+				"audit_enabled":   {"ac-1"},
+				"audit_rules":     nil,
+				"password_length": {"ia-5"},
+			},
+		},
+		{
+			name: "Success/RulePatterns",
+			scope: AssessmentScope{
+				IncludeControls: []ControlEntry{
+					// The entry with the control ID takes precedence over patterns.
+					{ControlID: "ac-2", IncludeRules: []string{"audit_*"}, ExcludeRules: []string{"re:rules$"}},
+					{ControlID: "*", IncludeRules: []string{"audit_?ules", "password_*"}},
+				},
+				GlobalExcludeRules: []string{"password_[lm]*"},
+			},
+			wantControls: []string{"ac-1", "ac-2", "ia-5", "r30", "r31", "r4"},
+			wantActivities: map[string][]string{
+				"audit_enabled":   {"ac-2"},
+				"audit_rules":     {"r31", "r4"},
+				"password_length": nil,
+			},
+		},
+		{
+			name: "Success/InvalidPatternsMatchNothing",
+			scope: AssessmentScope{
+				IncludeControls: []ControlEntry{{ControlID: "ia-5"}, {ControlID: "re:("}},
+				ExcludeControls: []string{"["},
+			},
+			wantControls: []string{"ia-5"},
+			wantActivities: map[string][]string{
+				"audit_enabled":   nil,
+				"audit_rules":     nil,
+				"password_length": {"ia-5"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := newPlan()
+			tt.scope.ApplyScope(plan, hclog.NewNullLogger())
+			var controls []string
+			for _, control := range *plan.ReviewedControls.ControlSelections[0].IncludeControls {
+				controls = append(controls, control.ControlId)
+			}
+			require.Equal(t, tt.wantControls, controls)
+			require.Equal(t, tt.wantActivities, activityControls(plan))
+		})
+	}
+}
+
+func TestAssessmentScope_ApplyParameters(t *testing.T) {
+	newPlan := func() *oscalTypes.AssessmentPlan {
+		activity := func(ruleID string, parameters map[string]string, controlIDs ...string) oscalTypes.Activity {
+			var selected []oscalTypes.AssessedControlsSelectControlById
+			for _, controlID := range controlIDs {
+				selected = append(selected, oscalTypes.AssessedControlsSelectControlById{ControlId: controlID})
+			}
+			props := []oscalTypes.Property{{Name: "method", Value: "TEST"}}
+			for name, value := range parameters {
+				props = append(props, oscalTypes.Property{
+					Name:  name,
+					Value: value,
+					Ns:    extensions.TrestleNameSpace,
+					Class: extensions.TestParameterClass,
+				})
+			}
+			return oscalTypes.Activity{
+				Title:           ruleID,
+				Props:           &props,
+				RelatedControls: &oscalTypes.ReviewedControls{ControlSelections: []oscalTypes.AssessedControls{{IncludeControls: &selected}}},
+			}
+		}
+		return &oscalTypes.AssessmentPlan{
+			ReviewedControls: oscalTypes.ReviewedControls{
+				ControlSelections: []oscalTypes.AssessedControls{{IncludeAll: &oscalTypes.IncludeAll{}}},
+			},
+			LocalDefinitions: &oscalTypes.LocalDefinitions{
+				Activities: &[]oscalTypes.Activity{
+					activity("audit_enabled", map[string]string{"var_audit_backlog": "8192"}, "ac-1", "ac-2"),
+					activity("audit_rules", map[string]string{"var_audit_backlog": "8192", "var_audit_action": "syslog"}, "ac-2"),
+					activity("password_length", map[string]string{"var_password_minlen": "12"}, "ia-5"),
+					{
+						Title: "other_namespace",
+						// Parameters of other namespaces are not rule parameters.
+						Props: &[]oscalTypes.Property{{
+							Name:  "var_other",
+							Value: "other",
+							Ns:    extensions.TrestleNameSpace + "/other",
+							Class: extensions.TestParameterClass,
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		scope          AssessmentScope
+		wantParameters map[string]string
+	}{
+		{
+			name: "Success/Defaults",
+			scope: AssessmentScope{
+				IncludeControls: []ControlEntry{{ControlID: "*"}},
+			},
+			wantParameters: map[string]string{"var_audit_backlog": "8192", "var_audit_action": "syslog", "var_password_minlen": "12"},
+		},
+		{
+			name: "Success/GlobalParameters",
+			scope: AssessmentScope{
+				IncludeControls: []ControlEntry{{ControlID: "*"}},
+				Parameters:      map[string]string{"var_password_minlen": "15", "var_unknown": "ignored", "var_other": "ignored"},
+			},
+			wantParameters: map[string]string{"var_audit_backlog": "8192", "var_audit_action": "syslog", "var_password_minlen": "15"},
+		},
+		{
+			name: "Success/ControlParametersTakePrecedence",
+			scope: AssessmentScope{
+				IncludeControls: []ControlEntry{
+					{ControlID: "ac-2", Parameters: map[string]string{"var_audit_backlog": "4096"}},
+					{ControlID: "*", Parameters: map[string]string{"var_password_minlen": "14"}},
+				},
+				Parameters: map[string]string{"var_audit_backlog": "2048", "var_password_minlen": "15"},
+			},
+			wantParameters: map[string]string{"var_audit_backlog": "4096", "var_audit_action": "syslog", "var_password_minlen": "14"},
+		},
+		{
+			name: "Success/ConflictingControlParameters",
+			scope: AssessmentScope{
+				IncludeControls: []ControlEntry{
+					{ControlID: "ac-1", Parameters: map[string]string{"var_audit_backlog": "1024"}},
+					{ControlID: "ac-2", Parameters: map[string]string{"var_audit_backlog": "4096"}},
+				},
+			},
+			wantParameters: map[string]string{"var_audit_backlog": "1024", "var_audit_action": "syslog", "var_password_minlen": "12"},
+		},
+		{
+			name: "Success/ExcludedControlParameters",
+			scope: AssessmentScope{
+				IncludeControls: []ControlEntry{
+					{ControlID: "ac-*", Parameters: map[string]string{"var_password_minlen": "20"}},
+					{ControlID: "ia-5", ExcludeRules: []string{"*"}, Parameters: map[string]string{"var_audit_action": "single"}},
+				},
+			},
+			wantParameters: map[string]string{"var_audit_backlog": "8192", "var_audit_action": "syslog", "var_password_minlen": "12"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := newPlan()
+			tt.scope.ApplyScope(plan, hclog.NewNullLogger())
+			require.Equal(t, tt.wantParameters, PlanParameters(plan))
+
+			// Every activity with a parameter has the same value.
+			for _, activity := range *plan.LocalDefinitions.Activities {
+				for _, parameter := range activityParameters(activity) {
+					require.Equal(t, tt.wantParameters[parameter.Name], parameter.Value)
+				}
+			}
+			require.Equal(t, "other", (*(*plan.LocalDefinitions.Activities)[3].Props)[0].Value)
+		})
+	}
+}
+
+func TestAssessmentScope_CheckControls(t *testing.T) {
+	available := []ControlEntry{{ControlID: "ac-1"}, {ControlID: "ac-2"}, {ControlID: "ia-5"}, {ControlID: "pl-1"}}
+	controlRules := map[string][]string{
+		"ac-1": {"audit_enabled"},
+		"ac-2": {"audit_enabled", "audit_rules"},
+		"ia-5": {"password_length"},
+	}
+
+	tests := []struct {
+		name       string
+		scope      AssessmentScope
+		wantErrors []string
+	}{
+		{
+			name: "Valid/KnownIDsAndPatterns",
+			scope: AssessmentScope{
+				FrameworkID: "example",
+				IncludeControls: []ControlEntry{
+					{ControlID: "ac-*", IncludeRules: []string{"*"}, ExcludeRules: []string{"audit_rules"}},
+					{ControlID: "pl-1", IncludeRules: []string{"*"}},
+				},
+				ExcludeControls:    []string{"re:^ia-"},
+				GlobalExcludeRules: []string{"password_length"},
+			},
+		},
+		{
+			name: "Invalid/UnknownIDs",
+			scope: AssessmentScope{
+				FrameworkID: "exmaple",
+				IncludeControls: []ControlEntry{
+					{ControlID: "ac-22", IncludeRules: []string{"*"}},
+					{ControlID: "ia-5", IncludeRules: []string{"pasword_length", "audit_rules", "unrelated"}},
+					{ControlID: "ac-*", ExcludeRules: []string{"password_length", "audit_*"}},
+				},
+				ExcludeControls:    []string{"sc-*", "ia5"},
+				GlobalExcludeRules: []string{"audit_enable", "re:^package_"},
+			},
+			wantErrors: []string{
+				`frameworkId: framework "exmaple" does not match the plan framework "example"`,
+				`includeControls[0].controlId: unknown control "ac-22", did you mean "ac-2"?`,
+				`includeControls[1].includeRules[0]: unknown rule "pasword_length", did you mean "password_length"?`,
+				`includeControls[1].includeRules[1]: rule "audit_rules" is not mapped to control "ia-5"`,
+				`includeControls[1].includeRules[2]: unknown rule "unrelated"`,
+				`includeControls[2].excludeRules[0]: rule "password_length" is not mapped to the controls matching "ac-*"`,
+				`excludeControls[0]: pattern "sc-*" matches no control`,
+				`excludeControls[1]: unknown control "ia5", did you mean "ia-5"?`,
+				`globalExcludeRules[0]: unknown rule "audit_enable", did you mean "audit_enabled"?`,
+				`globalExcludeRules[1]: pattern "re:^package_" matches no rule`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scopeErrors []string
+			for _, scopeError := range tt.scope.CheckControls("example", available, controlRules) {
+				scopeErrors = append(scopeErrors, scopeError.String())
+			}
+			require.Equal(t, tt.wantErrors, scopeErrors)
+		})
+	}
+}
+
+func TestSuggestion(t *testing.T) {
+	candidates := []string{"ac-1", "ac-2", "audit_rules_immutable"}
+	require.Equal(t, `, did you mean "ac-1"?`, suggestion("ac-3", candidates))
+	require.Equal(t, `, did you mean "audit_rules_immutable"?`, suggestion("audit_rule_imutable", candidates))
+	require.Empty(t, suggestion("sc-28", candidates))
+	require.Empty(t, suggestion("ac-1", nil))
+	require.Equal(t, 3, levenshteinDistance("kitten", "sitting"))
+}
+
+func TestMatchScopePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		id      string
+		want    bool
+	}{
+		{pattern: "ac-2", id: "ac-2", want: true},
+		{pattern: "ac-2", id: "ac-20", want: false},
+		{pattern: "*", id: "any_rule", want: true},
+		{pattern: "ac-*", id: "ac-2.1", want: true},
+		{pattern: "ac-*", id: "au-2", want: false},
+		{pattern: "ac-?", id: "ac-2", want: true},
+		{pattern: "ac-[12]", id: "ac-3", want: false},
+		{pattern: "re:^r3[0-9]$", id: "r31", want: true},
+		{pattern: "re:^r3[0-9]$", id: "r310", want: false},
+		{pattern: "re:audit", id: "service_auditd_enabled", want: true},
+		{pattern: "re:(", id: "(", want: false},
+		{pattern: "[", id: "[", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.id, func(t *testing.T) {
+			require.Equal(t, tt.want, MatchScopePattern(tt.pattern, tt.id))
+		})
+	}
+	require.True(t, IsScopePattern("re:ac"))
+	require.True(t, IsScopePattern("ac-*"))
+	require.False(t, IsScopePattern("ac-2.1"))
+}
+
+func TestAssessmentScope_Validate(t *testing.T) {
+	scope := AssessmentScope{
+		IncludeControls: []ControlEntry{
+			{ControlID: "ac-*", IncludeRules: []string{"re:^audit_"}, ExcludeRules: []string{"audit_[ab]"}},
+		},
+		ExcludeControls:    []string{"re:^r3[0-9]$"},
+		GlobalExcludeRules: []string{"*"},
+	}
+	require.NoError(t, scope.Validate())
+
+	scope.IncludeControls[0].ExcludeRules = []string{"re:("}
+	scope.GlobalExcludeRules = []string{"audit_[a"}
+	err := scope.Validate()
+	require.ErrorContains(t, err, `invalid pattern "re:("`)
+	require.ErrorContains(t, err, `invalid pattern "audit_[a"`)
+}
+
+func TestAssessmentScope_FindControlEntry(t *testing.T) {
+	scope := AssessmentScope{
+		IncludeControls: []ControlEntry{
+			{ControlID: "ac-*", IncludeRules: []string{"*"}},
+			{ControlID: "ac-2", IncludeRules: []string{"audit_rules"}},
+		},
+		ExcludeControls: []string{"ac-3"},
+	}
+	entry, found := scope.FindControlEntry("ac-2")
+	require.True(t, found)
+	require.Equal(t, []string{"audit_rules"}, entry.IncludeRules)
+	entry, found = scope.FindControlEntry("ac-1")
+	require.True(t, found)
+	require.Equal(t, "ac-*", entry.ControlID)
+	_, found = scope.FindControlEntry("ac-3")
+	require.False(t, found)
+	_, found = scope.FindControlEntry("au-1")
+	require.False(t, found)
+}
+
+func TestScopeConfigSchema(t *testing.T) {
+	data, err := os.ReadFile("../../docs/schemas/scope-config.schema.json")
+	require.NoError(t, err)
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Defs       struct {
+			ControlEntry struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"controlEntry"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	// The schema documents all the fields of the scope config.
+	yamlFields := func(value interface{}) []string {
+		var fields []string
+		valueType := reflect.TypeOf(value)
+		for i := 0; i < valueType.NumField(); i++ {
+			name, _, _ := strings.Cut(valueType.Field(i).Tag.Get("yaml"), ",")
+			fields = append(fields, name)
+		}
+		return fields
+	}
+	schemaFields := func(properties map[string]json.RawMessage) []string {
+		var fields []string
+		for name := range properties {
+			fields = append(fields, name)
+		}
+		return fields
+	}
+	require.ElementsMatch(t, yamlFields(AssessmentScope{}), schemaFields(schema.Properties))
+	require.ElementsMatch(t, yamlFields(ControlEntry{}), schemaFields(schema.Defs.ControlEntry.Properties))
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FailOn values select which rule statuses breach the fail-on threshold.
+const (
+	FailOnFail  = "fail"
+	FailOnError = "error"
+	FailOnAny   = "any"
+)
+
+// Breach kinds identify which threshold was breached.
+const (
+	BreachFailOn             = "fail-on"
+	BreachMaxFailures        = "max-failures"
+	BreachMinPassRate        = "min-pass-rate"
+	BreachMinControlPassRate = "min-control-pass-rate"
+)
+
+// Thresholds are policy limits evaluated against summarized assessment results.
+// The zero value disables all thresholds except MaxFailures, which is disabled
+// when negative.
+type Thresholds struct {
+	// FailOn breaches when any rule has the selected status.
+	// One of FailOnFail, FailOnError, FailOnAny or empty to disable.
+	FailOn string
+	// MaxFailures breaches when more rules fail or error than the given number.
+	MaxFailures int
+	// MinPassRate breaches when the percentage of passing assessed rules is lower.
+	MinPassRate float64
+	// MinControlPassRate breaches when the percentage of passing assessed rules
+	// for any control is lower.
+	MinControlPassRate float64
+}
+
+// ThresholdBreach describes a threshold that was not met.
+type ThresholdBreach struct {
+	Kind    string
+	Message string
+}
+
+// Validate ensures the thresholds are within range.
+func (t Thresholds) Validate() error {
+	switch t.FailOn {
+	case "", FailOnFail, FailOnError, FailOnAny:
+	default:
+		return fmt.Errorf("invalid fail-on value %q: must be one of: %s, %s, %s", t.FailOn, FailOnFail, FailOnError, FailOnAny)
+	}
+	if t.MinPassRate < 0 || t.MinPassRate > 100 {
+		return fmt.Errorf("invalid minimum pass rate %v: must be between 0 and 100", t.MinPassRate)
+	}
+	if t.MinControlPassRate < 0 || t.MinControlPassRate > 100 {
+		return fmt.Errorf("invalid minimum control pass rate %v: must be between 0 and 100", t.MinControlPassRate)
+	}
+	return nil
+}
+
+// Evaluate returns the breached thresholds for the given results in the order
+// fail-on, max-failures, min-pass-rate and min-control-pass-rate.
+func (t Thresholds) Evaluate(summary ResultsSummary) []ThresholdBreach {
+	var breaches []ThresholdBreach
+
+	if t.FailOn != "" {
+		var matched []string
+		for _, rule := range summary.Rules {
+			if t.matchesFailOn(rule.Status) {
+				matched = append(matched, rule.RuleID)
+			}
+		}
+		if len(matched) > 0 {
+			breaches = append(breaches, ThresholdBreach{
+				Kind:    BreachFailOn,
+				Message: fmt.Sprintf("%d rule(s) matched --fail-on %s: %s", len(matched), t.FailOn, strings.Join(matched, ", ")),
+			})
+		}
+	}
+
+	failures := summary.RuleCounts.Fail + summary.RuleCounts.Error
+	if t.MaxFailures >= 0 && failures > t.MaxFailures {
+		breaches = append(breaches, ThresholdBreach{
+			Kind:    BreachMaxFailures,
+			Message: fmt.Sprintf("%d rule(s) failed or errored, more than the maximum of %d", failures, t.MaxFailures),
+		})
+	}
+
+	if t.MinPassRate > 0 {
+		if passRate := summary.RuleCounts.PassRate(); passRate < t.MinPassRate {
+			breaches = append(breaches, ThresholdBreach{
+				Kind:    BreachMinPassRate,
+				Message: fmt.Sprintf("framework pass rate %.1f%% is below the minimum of %.1f%%", passRate, t.MinPassRate),
+			})
+		}
+	}
+
+	if t.MinControlPassRate > 0 {
+		var below []string
+		for _, control := range summary.Controls {
+			// Controls without assessed rules have no pass rate to compare.
+			if control.Counts.Total() == control.Counts.NotAssessed {
+				continue
+			}
+			if passRate := control.Counts.PassRate(); passRate < t.MinControlPassRate {
+				below = append(below, fmt.Sprintf("%s (%.1f%%)", control.ControlID, passRate))
+			}
+		}
+		if len(below) > 0 {
+			breaches = append(breaches, ThresholdBreach{
+				Kind:    BreachMinControlPassRate,
+				Message: fmt.Sprintf("%d control(s) below the minimum pass rate of %.1f%%: %s", len(below), t.MinControlPassRate, strings.Join(below, ", ")),
+			})
+		}
+	}
+	return breaches
+}
+
+// matchesFailOn returns true if the rule status matches the fail-on selection.
+func (t Thresholds) matchesFailOn(status string) bool {
+	switch t.FailOn {
+	case FailOnFail:
+		return status == StatusFail
+	case FailOnError:
+		return status == StatusError
+	case FailOnAny:
+		return IsFailing(status)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThresholdsValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds Thresholds
+		wantErr    string
+	}{
+		{
+			name:       "Valid/Disabled",
+			thresholds: Thresholds{MaxFailures: -1},
+		},
+		{
+			name:       "Valid/AllSet",
+			thresholds: Thresholds{FailOn: FailOnAny, MaxFailures: 3, MinPassRate: 90, MinControlPassRate: 100},
+		},
+		{
+			name:       "Invalid/FailOn",
+			thresholds: Thresholds{FailOn: "warning"},
+			wantErr:    `invalid fail-on value "warning": must be one of: fail, error, any`,
+		},
+		{
+			name:       "Invalid/MinPassRate",
+			thresholds: Thresholds{MinPassRate: 101},
+			wantErr:    "invalid minimum pass rate 101: must be between 0 and 100",
+		},
+		{
+			name:       "Invalid/MinControlPassRate",
+			thresholds: Thresholds{MinControlPassRate: -5},
+			wantErr:    "invalid minimum control pass rate -5: must be between 0 and 100",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.thresholds.Validate()
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestThresholdsEvaluate(t *testing.T) {
+	// rule-1 passes, rule-2 fails and rule-3 is not assessed.
+	// control-1 has a pass rate of 50% and control-2 a pass rate of 0%.
+	summary := SummarizeResults(testResults(StatusPass, StatusFail), testResultsPlan())
+
+	tests := []struct {
+		name       string
+		thresholds Thresholds
+		wantKinds  []string
+	}{
+		{
+			name:       "Disabled",
+			thresholds: Thresholds{MaxFailures: -1},
+		},
+		{
+			name:       "FailOnFail",
+			thresholds: Thresholds{FailOn: FailOnFail, MaxFailures: -1},
+			wantKinds:  []string{BreachFailOn},
+		},
+		{
+			name:       "FailOnErrorOnly",
+			thresholds: Thresholds{FailOn: FailOnError, MaxFailures: -1},
+		},
+		{
+			name:       "MaxFailuresNotExceeded",
+			thresholds: Thresholds{MaxFailures: 1},
+		},
+		{
+			name:       "MaxFailuresExceeded",
+			thresholds: Thresholds{MaxFailures: 0},
+			wantKinds:  []string{BreachMaxFailures},
+		},
+		{
+			name:       "MinPassRateMet",
+			thresholds: Thresholds{MaxFailures: -1, MinPassRate: 50},
+		},
+		{
+			name:       "MinPassRateBreached",
+			thresholds: Thresholds{MaxFailures: -1, MinPassRate: 50.1},
+			wantKinds:  []string{BreachMinPassRate},
+		},
+		{
+			name:       "MinControlPassRateBreached",
+			thresholds: Thresholds{MaxFailures: -1, MinControlPassRate: 40},
+			wantKinds:  []string{BreachMinControlPassRate},
+		},
+		{
+			name:       "AllBreachedInOrder",
+			thresholds: Thresholds{FailOn: FailOnAny, MaxFailures: 0, MinPassRate: 100, MinControlPassRate: 100},
+			wantKinds:  []string{BreachFailOn, BreachMaxFailures, BreachMinPassRate, BreachMinControlPassRate},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotKinds []string
+			for _, breach := range tt.thresholds.Evaluate(summary) {
+				gotKinds = append(gotKinds, breach.Kind)
+			}
+			require.Equal(t, tt.wantKinds, gotKinds)
+		})
+	}
+
+	breaches := Thresholds{MaxFailures: -1, MinControlPassRate: 40}.Evaluate(summary)
+	require.Equal(t, "1 control(s) below the minimum pass rate of 40.0%: control-2 (0.0%)", breaches[0].Message)
+}
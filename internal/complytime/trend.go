@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+// TrendInput is the summary of assessment results taken at a point in time.
+type TrendInput struct {
+	// Source is the file or scan run the results were read from.
+	Source string
+	// Time is the time the results were collected.
+	Time time.Time
+	// FrameworkID is the framework the results were assessed against.
+	FrameworkID string
+	Summary     ResultsSummary
+}
+
+// Trend holds the compliance trend of each framework found in a series of assessment results.
+type Trend struct {
+	// Frameworks are the framework trends sorted by framework ID.
+	Frameworks []FrameworkTrend `json:"frameworks" yaml:"frameworks"`
+}
+
+// FrameworkTrend holds the results of a framework over time.
+type FrameworkTrend struct {
+	FrameworkID string `json:"framework" yaml:"framework"`
+	// Points are the results of the framework sorted by time.
+	Points []TrendPoint `json:"points" yaml:"points"`
+	// Remediations counts the failing or erroring rules that were later fixed.
+	Remediations int `json:"remediations" yaml:"remediations"`
+	// MeanTimeToRemediateHours is the mean time between the first failure of a rule and its fix.
+	// It is zero when no rule was fixed.
+	MeanTimeToRemediateHours float64 `json:"meanTimeToRemediateHours" yaml:"meanTimeToRemediateHours"`
+}
+
+// TrendPoint is the result of a framework at a point in time.
+type TrendPoint struct {
+	Source string    `json:"source" yaml:"source"`
+	Time   time.Time `json:"time" yaml:"time"`
+	// PassRate is the percentage of passing rules out of the assessed rules.
+	PassRate   float64      `json:"passRate" yaml:"passRate"`
+	RuleCounts StatusCounts `json:"ruleCounts" yaml:"ruleCounts"`
+	// Controls are the control results sorted by control ID.
+	Controls []ControlTrendPoint `json:"controls" yaml:"controls"`
+	// NewlyFailing are the rules failing or erroring that did not in the previous point.
+	NewlyFailing []string `json:"newlyFailing" yaml:"newlyFailing"`
+	// NewlyFixed are the rules passing that failed or errored in the previous point.
+	NewlyFixed []string `json:"newlyFixed" yaml:"newlyFixed"`
+}
+
+// ControlTrendPoint is the result of a control at a point in time.
+type ControlTrendPoint struct {
+	ControlID string       `json:"controlId" yaml:"controlId"`
+	Status    string       `json:"status" yaml:"status"`
+	PassRate  float64      `json:"passRate" yaml:"passRate"`
+	Counts    StatusCounts `json:"counts" yaml:"counts"`
+	// NewlyFailing and NewlyFixed count the rules of the control newly failing or fixed.
+	NewlyFailing int `json:"newlyFailing" yaml:"newlyFailing"`
+	NewlyFixed   int `json:"newlyFixed" yaml:"newlyFixed"`
+}
+
+// ResultsTime returns the time assessment results were collected, which is the
+// latest end, or start when the end is not set, of its results.
+func ResultsTime(assessmentResults *oscalTypes.AssessmentResults) time.Time {
+	var latest time.Time
+	for _, result := range assessmentResults.Results {
+		collected := result.Start
+		if result.End != nil {
+			collected = *result.End
+		}
+		if collected.After(latest) {
+			latest = collected
+		}
+	}
+	if latest.IsZero() {
+		latest = assessmentResults.Metadata.LastModified
+	}
+	return latest
+}
+
+// ComputeTrend groups the inputs by framework, sorts them by time and computes the
+// pass rates, the status changes between consecutive points and the mean time to remediate.
+func ComputeTrend(inputs []TrendInput) Trend {
+	byFramework := make(map[string][]TrendInput)
+	for _, input := range inputs {
+		byFramework[input.FrameworkID] = append(byFramework[input.FrameworkID], input)
+	}
+
+	trend := Trend{Frameworks: []FrameworkTrend{}}
+	for frameworkID, frameworkInputs := range byFramework {
+		trend.Frameworks = append(trend.Frameworks, computeFrameworkTrend(frameworkID, frameworkInputs))
+	}
+	sort.Slice(trend.Frameworks, func(i, j int) bool {
+		return trend.Frameworks[i].FrameworkID < trend.Frameworks[j].FrameworkID
+	})
+	return trend
+}
+
+func computeFrameworkTrend(frameworkID string, inputs []TrendInput) FrameworkTrend {
+	sort.SliceStable(inputs, func(i, j int) bool {
+		return inputs[i].Time.Before(inputs[j].Time)
+	})
+
+	frameworkTrend := FrameworkTrend{FrameworkID: frameworkID, Points: []TrendPoint{}}
+	// failingSince holds the time each currently failing rule started failing.
+	failingSince := make(map[string]time.Time)
+	var remediationTime time.Duration
+	var previous *ResultsSummary
+	for _, input := range inputs {
+		point := TrendPoint{
+			Source:       input.Source,
+			Time:         input.Time,
+			PassRate:     input.Summary.RuleCounts.PassRate(),
+			RuleCounts:   input.Summary.RuleCounts,
+			Controls:     []ControlTrendPoint{},
+			NewlyFailing: []string{},
+			NewlyFixed:   []string{},
+		}
+		if previous != nil {
+			for _, change := range DiffResults(*previous, input.Summary).Rules {
+				switch change.Change {
+				case ChangeNewFailure:
+					point.NewlyFailing = append(point.NewlyFailing, change.ID)
+				case ChangeFixed:
+					point.NewlyFixed = append(point.NewlyFixed, change.ID)
+				}
+			}
+		}
+
+		for _, rule := range input.Summary.Rules {
+			since, failing := failingSince[rule.RuleID]
+			switch {
+			case IsFailing(rule.Status) && !failing:
+				failingSince[rule.RuleID] = input.Time
+			case rule.Status == StatusPass && failing:
+				frameworkTrend.Remediations++
+				remediationTime += input.Time.Sub(since)
+				delete(failingSince, rule.RuleID)
+			}
+		}
+
+		for _, control := range input.Summary.Controls {
+			controlPoint := ControlTrendPoint{
+				ControlID: control.ControlID,
+				Status:    control.Status,
+				PassRate:  control.Counts.PassRate(),
+				Counts:    control.Counts,
+			}
+			for _, ruleID := range control.Rules {
+				if slices.Contains(point.NewlyFailing, ruleID) {
+					controlPoint.NewlyFailing++
+				}
+				if slices.Contains(point.NewlyFixed, ruleID) {
+					controlPoint.NewlyFixed++
+				}
+			}
+			point.Controls = append(point.Controls, controlPoint)
+		}
+
+		frameworkTrend.Points = append(frameworkTrend.Points, point)
+		summary := input.Summary
+		previous = &summary
+	}
+	if frameworkTrend.Remediations > 0 {
+		frameworkTrend.MeanTimeToRemediateHours = remediationTime.Hours() / float64(frameworkTrend.Remediations)
+	}
+	return frameworkTrend
+}
+
+// trendCSVHeader are the columns of the CSV export of a Trend.
+var trendCSVHeader = []string{"time", "source", "framework", "control", "status", "pass_rate", "pass", "fail", "error", "not_assessed", "newly_failing", "newly_fixed"}
+
+// WriteTrendCSV writes a Trend as CSV with a row for each framework and control at each point
+// in time. Framework rows have an empty control column and no status.
+func WriteTrendCSV(writer io.Writer, trend Trend) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(trendCSVHeader); err != nil {
+		return err
+	}
+	row := func(point TrendPoint, frameworkID, controlID, status string, passRate float64, counts StatusCounts, newlyFailing, newlyFixed int) []string {
+		return []string{
+			point.Time.Format(time.RFC3339),
+			point.Source,
+			frameworkID,
+			controlID,
+			status,
+			strconv.FormatFloat(passRate, 'f', 2, 64),
+			strconv.Itoa(counts.Pass),
+			strconv.Itoa(counts.Fail),
+			strconv.Itoa(counts.Error),
+			strconv.Itoa(counts.NotAssessed),
+			strconv.Itoa(newlyFailing),
+			strconv.Itoa(newlyFixed),
+		}
+	}
+	for _, frameworkTrend := range trend.Frameworks {
+		for _, point := range frameworkTrend.Points {
+			if err := csvWriter.Write(row(point, frameworkTrend.FrameworkID, "", "", point.PassRate, point.RuleCounts, len(point.NewlyFailing), len(point.NewlyFixed))); err != nil {
+				return err
+			}
+			for _, control := range point.Controls {
+				if err := csvWriter.Write(row(point, frameworkTrend.FrameworkID, control.ControlID, control.Status, control.PassRate, control.Counts, control.NewlyFailing, control.NewlyFixed)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return fmt.Errorf("error writing trend CSV: %w", err)
+	}
+	return nil
+}
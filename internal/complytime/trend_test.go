@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/stretchr/testify/require"
+)
+
+func testTrendInputs() []TrendInput {
+	day := func(d int) time.Time { return time.Date(2025, 5, d, 0, 0, 0, 0, time.UTC) }
+	plan := testResultsPlan()
+	return []TrendInput{
+		// Inputs are sorted by time.
+		{Source: "run-3", Time: day(4), FrameworkID: "example", Summary: SummarizeResults(testResults(StatusPass, StatusPass), plan)},
+		{Source: "run-1", Time: day(1), FrameworkID: "example", Summary: SummarizeResults(testResults(StatusPass, StatusPass), plan)},
+		{Source: "run-2", Time: day(2), FrameworkID: "example", Summary: SummarizeResults(testResults(StatusFail, StatusFail), plan)},
+		{Source: "other", Time: day(3), FrameworkID: "other", Summary: SummarizeResults(testResults(StatusFail, StatusPass), plan)},
+	}
+}
+
+func TestComputeTrend(t *testing.T) {
+	trend := ComputeTrend(testTrendInputs())
+	require.Len(t, trend.Frameworks, 2)
+	example := trend.Frameworks[0]
+	require.Equal(t, "example", example.FrameworkID)
+	require.Equal(t, []string{"run-1", "run-2", "run-3"}, []string{example.Points[0].Source, example.Points[1].Source, example.Points[2].Source})
+
+	require.Equal(t, 100.0, example.Points[0].PassRate)
+	require.Empty(t, example.Points[0].NewlyFailing)
+	require.Equal(t, 0.0, example.Points[1].PassRate)
+	require.Equal(t, []string{"rule-1", "rule-2"}, example.Points[1].NewlyFailing)
+	require.Equal(t, []string{"rule-1", "rule-2"}, example.Points[2].NewlyFixed)
+
+	// control-1 has rule-1 and rule-2, control-2 has rule-2 and the not assessed rule-3.
+	require.Equal(t, []ControlTrendPoint{
+		{ControlID: "control-1", Status: StatusFail, Counts: StatusCounts{Fail: 2}, NewlyFailing: 2},
+		{ControlID: "control-2", Status: StatusFail, Counts: StatusCounts{Fail: 1, NotAssessed: 1}, NewlyFailing: 1},
+	}, example.Points[1].Controls)
+
+	// Both rules failed on day 2 and were fixed on day 4.
+	require.Equal(t, 2, example.Remediations)
+	require.Equal(t, 48.0, example.MeanTimeToRemediateHours)
+
+	other := trend.Frameworks[1]
+	require.Len(t, other.Points, 1)
+	require.Equal(t, 50.0, other.Points[0].PassRate)
+	require.Zero(t, other.Remediations)
+	require.Zero(t, other.MeanTimeToRemediateHours)
+}
+
+func TestWriteTrendCSV(t *testing.T) {
+	trend := ComputeTrend(testTrendInputs()[:2])
+	out := bytes.NewBuffer(nil)
+	require.NoError(t, WriteTrendCSV(out, trend))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Equal(t, []string{
+		"time,source,framework,control,status,pass_rate,pass,fail,error,not_assessed,newly_failing,newly_fixed",
+		"2025-05-01T00:00:00Z,run-1,example,,,100.00,2,0,0,1,0,0",
+		"2025-05-01T00:00:00Z,run-1,example,control-1,pass,100.00,2,0,0,0,0,0",
+		"2025-05-01T00:00:00Z,run-1,example,control-2,pass,100.00,1,0,0,1,0,0",
+		"2025-05-04T00:00:00Z,run-3,example,,,100.00,2,0,0,1,0,0",
+		"2025-05-04T00:00:00Z,run-3,example,control-1,pass,100.00,2,0,0,0,0,0",
+		"2025-05-04T00:00:00Z,run-3,example,control-2,pass,100.00,1,0,0,1,0,0",
+	}, lines)
+}
+
+func TestResultsTime(t *testing.T) {
+	start := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	assessmentResults := &oscalTypes.AssessmentResults{
+		Metadata: oscalTypes.Metadata{LastModified: start.Add(-time.Hour)},
+		Results:  []oscalTypes.Result{{Start: start}, {Start: start, End: &end}},
+	}
+	require.Equal(t, end, ResultsTime(assessmentResults))
+	assessmentResults.Results = nil
+	require.Equal(t, start.Add(-time.Hour), ResultsTime(assessmentResults))
+}
@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+// PlanUpdate lists the differences between an assessment plan and the plan regenerated
+// for one of its frameworks from the current component definitions.
+type PlanUpdate struct {
+	FrameworkID string `json:"framework" yaml:"framework"`
+	// NewControls are missing from the previous plan. They are left out of scope,
+	// since the previous scope may have excluded them.
+	NewControls     []string `json:"newControls,omitempty" yaml:"newControls,omitempty"`
+	RemovedControls []string `json:"removedControls,omitempty" yaml:"removedControls,omitempty"`
+	// NewRules are missing from the previous plan. They assess the controls in scope
+	// they are mapped to.
+	NewRules     []string `json:"newRules,omitempty" yaml:"newRules,omitempty"`
+	RemovedRules []string `json:"removedRules,omitempty" yaml:"removedRules,omitempty"`
+	// Conflicts are the changes the previous scope cannot decide on.
+	Conflicts []string `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
+}
+
+// Empty returns whether the regenerated plan has no changes.
+func (u PlanUpdate) Empty() bool {
+	return len(u.NewControls) == 0 && len(u.RemovedControls) == 0 &&
+		len(u.NewRules) == 0 && len(u.RemovedRules) == 0 && len(u.Conflicts) == 0
+}
+
+// UpdateScope returns the scope of the previous assessment plan for a framework, to apply to the
+// plan generated from the current component definitions, along with the differences between both.
+// The scope keeps the controls of the previous plan, the rules it excluded from controls or skipped,
+// and its parameter values. Rules newly mapped to a control they did not assess stay excluded, and
+// parameters whose default value changed keep their previous value; both are reported as conflicts.
+// Rules the previous scope excluded from a control are not reported again.
+func UpdateScope(previous, current *oscalTypes.AssessmentPlan, frameworkID string) (AssessmentScope, PlanUpdate) {
+	scope := NewAssessmentScope(frameworkID)
+	update := PlanUpdate{FrameworkID: frameworkID}
+
+	previousControls := reviewedControlIDs(previous, frameworkID)
+	currentControls := reviewedControlIDs(current, "")
+	previousRuleControls, _ := planRuleMappings(previous, frameworkID)
+	previousExcluded := planExcludedControls(previous, frameworkID)
+	currentRuleControls, _ := planRuleMappings(current, "")
+	previousRules := planActivities(previous)
+	currentRules := planActivities(current)
+
+	for _, controlID := range currentControls {
+		if !slices.Contains(previousControls, controlID) {
+			update.NewControls = append(update.NewControls, controlID)
+		}
+	}
+	for _, controlID := range previousControls {
+		if !slices.Contains(currentControls, controlID) {
+			update.RemovedControls = append(update.RemovedControls, controlID)
+		}
+	}
+	for _, ruleID := range sortedKeys(previousRuleControls) {
+		if _, found := currentRules[ruleID]; !found {
+			update.RemovedRules = append(update.RemovedRules, ruleID)
+		}
+	}
+
+	// Rules of the previous plan that assessed no control of the framework stay excluded.
+	excludedRules := make(map[string]bool)
+	for _, ruleID := range sortedKeys(currentRuleControls) {
+		inScope, found := previousRules[ruleID]
+		if !found {
+			update.NewRules = append(update.NewRules, ruleID)
+			continue
+		}
+		if _, assessed := previousRuleControls[ruleID]; assessed {
+			continue
+		}
+		excludedRules[ruleID] = true
+		scope.GlobalExcludeRules = append(scope.GlobalExcludeRules, ruleID)
+		if inScope && hasAny(currentRuleControls[ruleID], previousControls) {
+			update.Conflicts = append(update.Conflicts,
+				fmt.Sprintf("rule %s is only assessed for other frameworks, it stays excluded", ruleID))
+		}
+	}
+
+	for _, controlID := range currentControls {
+		if !slices.Contains(previousControls, controlID) {
+			continue
+		}
+		entry := ControlEntry{ControlID: controlID}
+		var mapped, allowed []string
+		for _, ruleID := range sortedKeys(currentRuleControls) {
+			if excludedRules[ruleID] || !slices.Contains(currentRuleControls[ruleID], controlID) {
+				continue
+			}
+			mapped = append(mapped, ruleID)
+			_, existed := previousRules[ruleID]
+			if !existed || slices.Contains(previousRuleControls[ruleID], controlID) {
+				allowed = append(allowed, ruleID)
+				continue
+			}
+			if slices.Contains(previousExcluded[ruleID], controlID) {
+				continue
+			}
+			update.Conflicts = append(update.Conflicts,
+				fmt.Sprintf("rule %s is now mapped to control %s, which it did not assess: it stays excluded from the control", ruleID, controlID))
+		}
+		switch {
+		case len(allowed) == 0 && len(mapped) > 0:
+			entry.ExcludeRules = []string{"*"}
+		case len(allowed) < len(mapped):
+			entry.IncludeRules = allowed
+		}
+		if len(allowed) == 0 && controlAssessed(previousRuleControls, controlID) {
+			update.Conflicts = append(update.Conflicts,
+				fmt.Sprintf("control %s is no longer assessed by any rule", controlID))
+		}
+		scope.IncludeControls = append(scope.IncludeControls, entry)
+	}
+
+	currentParameters := PlanParameters(current)
+	previousParameters := assessedParameters(previous, previousRuleControls)
+	for _, name := range sortedKeys(previousParameters) {
+		value := previousParameters[name]
+		defaultValue, found := currentParameters[name]
+		switch {
+		case !found:
+			update.Conflicts = append(update.Conflicts,
+				fmt.Sprintf("parameter %s is no longer used, its value %q is dropped", name, value))
+		case defaultValue != value:
+			if scope.Parameters == nil {
+				scope.Parameters = make(map[string]string)
+			}
+			scope.Parameters[name] = value
+			update.Conflicts = append(update.Conflicts,
+				fmt.Sprintf("parameter %s keeps the value %q of the previous plan, the default value is now %q", name, value, defaultValue))
+		}
+	}
+	return scope, update
+}
+
+// reviewedControlIDs returns the IDs of the controls reviewed by an assessment plan for a framework.
+func reviewedControlIDs(plan *oscalTypes.AssessmentPlan, frameworkID string) []string {
+	var controlIDs []string
+	for _, selection := range plan.ReviewedControls.ControlSelections {
+		if selection.IncludeControls == nil || !selectionInFramework(selection, frameworkID) {
+			continue
+		}
+		for _, control := range *selection.IncludeControls {
+			if !slices.Contains(controlIDs, control.ControlId) {
+				controlIDs = append(controlIDs, control.ControlId)
+			}
+		}
+	}
+	return controlIDs
+}
+
+// planExcludedControls returns the controls each rule of an assessment plan was excluded from
+// by the scope, for a framework.
+func planExcludedControls(plan *oscalTypes.AssessmentPlan, frameworkID string) map[string][]string {
+	excluded := make(map[string][]string)
+	if plan.LocalDefinitions == nil || plan.LocalDefinitions.Activities == nil {
+		return excluded
+	}
+	for _, activity := range *plan.LocalDefinitions.Activities {
+		if activity.RelatedControls == nil {
+			continue
+		}
+		for _, selection := range activity.RelatedControls.ControlSelections {
+			if selection.ExcludeControls == nil || !selectionInFramework(selection, frameworkID) {
+				continue
+			}
+			for _, control := range *selection.ExcludeControls {
+				excluded[activity.Title] = append(excluded[activity.Title], control.ControlId)
+			}
+		}
+	}
+	return excluded
+}
+
+// planActivities returns whether each rule of an assessment plan is in scope.
+func planActivities(plan *oscalTypes.AssessmentPlan) map[string]bool {
+	activities := make(map[string]bool)
+	if plan.LocalDefinitions == nil || plan.LocalDefinitions.Activities == nil {
+		return activities
+	}
+	for _, activity := range *plan.LocalDefinitions.Activities {
+		activities[activity.Title] = activityInScope(activity)
+	}
+	return activities
+}
+
+// assessedParameters returns the parameter values of the activities assessing controls, keeping
+// the first value of each parameter.
+func assessedParameters(plan *oscalTypes.AssessmentPlan, ruleControls map[string][]string) map[string]string {
+	parameters := make(map[string]string)
+	if plan.LocalDefinitions == nil || plan.LocalDefinitions.Activities == nil {
+		return parameters
+	}
+	for _, activity := range *plan.LocalDefinitions.Activities {
+		if _, assessed := ruleControls[activity.Title]; !assessed {
+			continue
+		}
+		for _, parameter := range activityParameters(activity) {
+			if _, found := parameters[parameter.Name]; !found {
+				parameters[parameter.Name] = parameter.Value
+			}
+		}
+	}
+	return parameters
+}
+
+// controlAssessed returns whether a rule assesses the control.
+func controlAssessed(ruleControls map[string][]string, controlID string) bool {
+	for _, controls := range ruleControls {
+		if slices.Contains(controls, controlID) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAny returns whether the values contain any of the candidates.
+func hasAny(values, candidates []string) bool {
+	for _, candidate := range candidates {
+		if slices.Contains(values, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a map in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
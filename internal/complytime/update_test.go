@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateScope(t *testing.T) {
+	previous := testFrameworkPlan([]oscalTypes.Activity{
+		testFrameworkActivity("a1", "rule-1", "value-1", "control-1", "control-2"),
+		testFrameworkActivity("a2", "rule-2", "", "control-2"),
+		testFrameworkActivity("a3", "rule-3", ""),
+		testFrameworkActivity("a4", "rule-4", "", "control-3"),
+		testFrameworkActivity("a6", "rule-6", "", "control-5"),
+	}, "comp-1")
+	current := testFrameworkPlan([]oscalTypes.Activity{
+		testFrameworkActivity("c1", "rule-1", "value-2", "control-1", "control-2", "control-3"),
+		testFrameworkActivity("c2", "rule-2", "", "control-2"),
+		testFrameworkActivity("c3", "rule-3", "", "control-1"),
+		testFrameworkActivity("c5", "rule-5", "", "control-2", "control-4"),
+	}, "comp-1")
+
+	scope, update := UpdateScope(previous, current, "cis")
+	require.Equal(t, PlanUpdate{
+		FrameworkID:     "cis",
+		NewControls:     []string{"control-4"},
+		RemovedControls: []string{"control-5"},
+		NewRules:        []string{"rule-5"},
+		RemovedRules:    []string{"rule-4", "rule-6"},
+		Conflicts: []string{
+			"rule rule-1 is now mapped to control control-3, which it did not assess: it stays excluded from the control",
+			"control control-3 is no longer assessed by any rule",
+			`parameter param-1 keeps the value "value-1" of the previous plan, the default value is now "value-2"`,
+		},
+	}, update)
+	require.False(t, update.Empty())
+	require.Equal(t, AssessmentScope{
+		FrameworkID: "cis",
+		IncludeControls: []ControlEntry{
+			{ControlID: "control-1"},
+			{ControlID: "control-2"},
+			{ControlID: "control-3", ExcludeRules: []string{"*"}},
+		},
+		GlobalExcludeRules: []string{"rule-3"},
+		Parameters:         map[string]string{"param-1": "value-1"},
+	}, scope)
+
+	scope.ApplyScope(current, hclog.NewNullLogger())
+	ruleControls, _ := planRuleMappings(current, "")
+	require.Equal(t, map[string][]string{
+		"rule-1": {"control-1", "control-2"},
+		"rule-2": {"control-2"},
+		"rule-5": {"control-2"},
+	}, ruleControls)
+	require.Equal(t, map[string]string{"param-1": "value-1"}, PlanParameters(current))
+
+	// Regenerating the updated plan carries over the same scope without changes.
+	regenerated := testFrameworkPlan([]oscalTypes.Activity{
+		testFrameworkActivity("c1", "rule-1", "value-1", "control-1", "control-2", "control-3"),
+		testFrameworkActivity("c2", "rule-2", "", "control-2"),
+		testFrameworkActivity("c3", "rule-3", "", "control-1"),
+		testFrameworkActivity("c5", "rule-5", "", "control-2", "control-4"),
+	}, "comp-1")
+	// The exclusion of rule-1 from control-3 was recorded and is not reported again.
+	require.Equal(t, map[string][]string{"rule-1": {"control-3"}}, planExcludedControls(current, "cis"))
+	scope, update = UpdateScope(current, regenerated, "cis")
+	require.Equal(t, []string{"control-4"}, update.NewControls)
+	require.Empty(t, update.Conflicts)
+	require.Equal(t, []ControlEntry{
+		{ControlID: "control-1"},
+		{ControlID: "control-2"},
+		{ControlID: "control-3", ExcludeRules: []string{"*"}},
+	}, scope.IncludeControls)
+}
+
+func TestUpdateScopeFrameworks(t *testing.T) {
+	previous := testMergedPlan(t, hclog.NewNullLogger())
+	current := testFrameworkPlan([]oscalTypes.Activity{
+		testFrameworkActivity("c1", "rule-1", "", "control-1"),
+		testFrameworkActivity("c2", "rule-2", "", "control-2"),
+		testFrameworkActivity("c3", "rule-3", "", "control-2"),
+	}, "comp-1")
+
+	scope, update := UpdateScope(previous, current, "cis")
+	require.Empty(t, update.NewRules)
+	require.Empty(t, update.RemovedRules)
+	require.Equal(t, []string{
+		"rule rule-3 is only assessed for other frameworks, it stays excluded",
+		`parameter param-1 is no longer used, its value "value-1" is dropped`,
+	}, update.Conflicts)
+	require.Equal(t, []string{"rule-3"}, scope.GlobalExcludeRules)
+	require.Equal(t, []ControlEntry{{ControlID: "control-1"}, {ControlID: "control-2"}}, scope.IncludeControls)
+	require.Empty(t, scope.Parameters)
+
+	_, update = UpdateScope(previous, testFrameworkPlan([]oscalTypes.Activity{
+		testFrameworkActivity("d2", "rule-2", "value-2", "b-1"),
+		testFrameworkActivity("d3", "rule-3", "value-2", "b-2"),
+		testFrameworkActivity("d4", "rule-4", "", "b-3"),
+	}, "comp-1"), "baseline")
+	require.Empty(t, update.NewControls)
+	require.Empty(t, update.NewRules)
+	// The merged plan kept the parameter value of the cis framework.
+	require.Equal(t, []string{
+		`parameter param-1 keeps the value "value-1" of the previous plan, the default value is now "value-2"`,
+	}, update.Conflicts)
+}
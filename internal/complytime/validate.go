@@ -0,0 +1,364 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	oscalValidation "github.com/defenseunicorns/go-oscal/src/pkg/validation"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/models/components"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+)
+
+// ContentError is a problem found in an OSCAL content file.
+type ContentError struct {
+	// File is the path of the file with the problem.
+	File string `json:"file" yaml:"file"`
+	// Path is a JSON pointer to the invalid value in the file.
+	// It is empty when the problem concerns the whole file.
+	Path string `json:"path" yaml:"path"`
+	// Message describes the problem.
+	Message string `json:"message" yaml:"message"`
+}
+
+// String returns the error in the form "file: path: message".
+func (e ContentError) String() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.File, e.Path, e.Message)
+}
+
+// ContentReport lists the validated files and all problems found in them.
+type ContentReport struct {
+	Files  []string       `json:"files" yaml:"files"`
+	Errors []ContentError `json:"errors" yaml:"errors"`
+}
+
+// ContentFiles returns the component definitions in the bundle directory and
+// the JSON files, such as profiles and catalogs, in the controls directory.
+func ContentFiles(appDir ApplicationDirectory) ([]string, error) {
+	var files []string
+	items, err := os.ReadDir(appDir.BundleDir())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read bundle directory %s: %w", appDir.BundleDir(), err)
+	}
+	for _, item := range items {
+		if !item.IsDir() && strings.HasSuffix(item.Name(), compDefSuffix) {
+			files = append(files, filepath.Join(appDir.BundleDir(), item.Name()))
+		}
+	}
+	controlFiles, err := FindContentFiles([]string{appDir.ControlDir()})
+	if err != nil {
+		return nil, err
+	}
+	return append(files, controlFiles...), nil
+}
+
+// FindContentFiles returns the given files and the JSON files found under the given directories.
+func FindContentFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(walkPath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && filepath.Ext(walkPath) == ".json" {
+				files = append(files, walkPath)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// ValidateContent validates each file against the OSCAL schema and checks the references between
+// component definitions, profiles and catalogs. Control sources are resolved from the application
+// directory, preferring the validated files so content can be checked before it is installed.
+// All problems are reported instead of stopping at the first one.
+func ValidateContent(appDir ApplicationDirectory, files []string) ContentReport {
+	v := &contentValidator{
+		appDir:    appDir,
+		documents: make(map[string]*oscalTypes.OscalModels),
+	}
+	v.report.Files = files
+
+	var loaded []string
+	for _, file := range files {
+		if v.load(file) {
+			loaded = append(loaded, file)
+		}
+	}
+	ruleIDs := v.definedRuleIDs(loaded)
+	for _, file := range loaded {
+		document := v.documents[absPath(file)]
+		switch {
+		case document.Profile != nil:
+			v.checkProfile(file, document.Profile)
+		case document.ComponentDefinition != nil:
+			v.checkComponentDefinition(file, document.ComponentDefinition, ruleIDs)
+		}
+	}
+
+	sort.SliceStable(v.report.Errors, func(i, j int) bool {
+		return v.report.Errors[i].File < v.report.Errors[j].File
+	})
+	return v.report
+}
+
+// contentValidator holds the decoded documents by absolute path and collects the problems found.
+type contentValidator struct {
+	appDir    ApplicationDirectory
+	documents map[string]*oscalTypes.OscalModels
+	report    ContentReport
+}
+
+func (v *contentValidator) addError(file, path, format string, args ...interface{}) {
+	v.report.Errors = append(v.report.Errors, ContentError{File: file, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// load validates a file against the OSCAL schema and decodes it for the reference checks.
+// It returns false if the file could not be decoded.
+func (v *contentValidator) load(file string) bool {
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		v.addError(file, "", "%v", err)
+		return false
+	}
+	var document oscalTypes.OscalModels
+	decodeErr := json.Unmarshal(data, &document)
+	var syntaxErr *json.SyntaxError
+	if errors.As(decodeErr, &syntaxErr) {
+		v.addError(file, "", "invalid JSON at offset %d: %v", syntaxErr.Offset, decodeErr)
+		return false
+	}
+	schemaErrors := schemaContentErrors(file, data)
+	v.report.Errors = append(v.report.Errors, schemaErrors...)
+	if decodeErr != nil {
+		if len(schemaErrors) == 0 {
+			v.addError(file, "", "unable to decode OSCAL model: %v", decodeErr)
+		}
+		return false
+	}
+	v.documents[absPath(file)] = &document
+	return true
+}
+
+// schemaContentErrors validates the data against the OSCAL JSON schema and returns
+// an error for each invalid value.
+func schemaContentErrors(file string, data []byte) []ContentError {
+	validator, err := oscalValidation.NewValidatorDesiredVersion(data, validation.OSCALVersion)
+	if err != nil {
+		return []ContentError{{File: file, Message: err.Error()}}
+	}
+	err = validator.Validate()
+	if err == nil {
+		return nil
+	}
+	result, resultErr := validator.GetValidationResult()
+	if resultErr != nil || len(result.Errors) == 0 {
+		return []ContentError{{File: file, Message: err.Error()}}
+	}
+	var contentErrors []ContentError
+	for _, validatorError := range result.Errors {
+		contentErrors = append(contentErrors, ContentError{File: file, Path: validatorError.InstanceLocation, Message: validatorError.Error})
+	}
+	// The schema validator does not report errors in a stable order.
+	sort.SliceStable(contentErrors, func(i, j int) bool {
+		return contentErrors[i].Path < contentErrors[j].Path
+	})
+	return contentErrors
+}
+
+// resolve returns the document for a control source href. Documents being validated are matched
+// by absolute path or by the href path relative to the application directory.
+func (v *contentValidator) resolve(href string) (*oscalTypes.OscalModels, error) {
+	path, err := controlSourcePath(v.appDir, href)
+	if err != nil {
+		return nil, err
+	}
+	if document, ok := v.documents[absPath(path)]; ok {
+		return document, nil
+	}
+	if relPath, err := filepath.Rel(v.appDir.AppDir(), path); err == nil && !strings.HasPrefix(relPath, "..") {
+		for documentPath, document := range v.documents {
+			if strings.HasSuffix(documentPath, string(os.PathSeparator)+relPath) {
+				return document, nil
+			}
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s does not resolve to an existing file: %s", href, path)
+		}
+		return nil, err
+	}
+	var document oscalTypes.OscalModels
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", path, err)
+	}
+	v.documents[absPath(path)] = &document
+	return &document, nil
+}
+
+// resolveCatalog returns the IDs of all controls in the catalog referenced by href.
+func (v *contentValidator) resolveCatalog(href string) (map[string]string, error) {
+	document, err := v.resolve(href)
+	if err != nil {
+		return nil, err
+	}
+	if document.Catalog == nil {
+		return nil, fmt.Errorf("%s is not an OSCAL catalog", href)
+	}
+	return CatalogControlTitles(document.Catalog), nil
+}
+
+// checkProfile checks that the profile imports resolve to catalogs containing the included controls.
+func (v *contentValidator) checkProfile(file string, profile *oscalTypes.Profile) {
+	for i, imp := range profile.Imports {
+		importPath := fmt.Sprintf("/profile/imports/%d", i)
+		controls, err := v.resolveCatalog(imp.Href)
+		if err != nil {
+			v.addError(file, importPath+"/href", "%v", err)
+			continue
+		}
+		if imp.IncludeControls == nil {
+			continue
+		}
+		for j, selection := range *imp.IncludeControls {
+			if selection.WithIds == nil {
+				continue
+			}
+			for k, controlID := range *selection.WithIds {
+				if _, ok := controls[controlID]; !ok {
+					v.addError(file, fmt.Sprintf("%s/include-controls/%d/with-ids/%d", importPath, j, k), "control %s not found in catalog %s", controlID, imp.Href)
+				}
+			}
+		}
+	}
+}
+
+// checkComponentDefinition checks that control implementation sources resolve to profiles,
+// implemented controls exist in the imported catalogs and referenced rules are defined.
+func (v *contentValidator) checkComponentDefinition(file string, compDef *oscalTypes.ComponentDefinition, ruleIDs map[string]struct{}) {
+	if compDef.Components == nil {
+		return
+	}
+	for i, component := range *compDef.Components {
+		componentPath := fmt.Sprintf("/component-definition/components/%d", i)
+		if component.Type == string(components.Validation) {
+			v.checkRuleProps(file, componentPath, component.Props, ruleIDs)
+		}
+		if component.ControlImplementations == nil {
+			continue
+		}
+		for j, implementation := range *component.ControlImplementations {
+			implementationPath := fmt.Sprintf("%s/control-implementations/%d", componentPath, j)
+			controls, err := v.profileControls(implementation.Source)
+			if err != nil {
+				v.addError(file, implementationPath+"/source", "%v", err)
+			}
+			for k, requirement := range implementation.ImplementedRequirements {
+				requirementPath := fmt.Sprintf("%s/implemented-requirements/%d", implementationPath, k)
+				if controls != nil {
+					if _, ok := controls[requirement.ControlId]; !ok {
+						v.addError(file, requirementPath+"/control-id", "control %s not found in the catalogs imported by %s", requirement.ControlId, implementation.Source)
+					}
+				}
+				v.checkRuleProps(file, requirementPath, requirement.Props, ruleIDs)
+			}
+		}
+	}
+}
+
+// profileControls returns the IDs of all controls in the catalogs imported by the profile referenced by href.
+func (v *contentValidator) profileControls(href string) (map[string]string, error) {
+	document, err := v.resolve(href)
+	if err != nil {
+		return nil, err
+	}
+	if document.Profile == nil {
+		return nil, fmt.Errorf("%s is not an OSCAL profile", href)
+	}
+	controls := make(map[string]string)
+	for _, imp := range document.Profile.Imports {
+		catalogControls, err := v.resolveCatalog(imp.Href)
+		if err != nil {
+			// Import problems are reported when the profile itself is validated.
+			return nil, fmt.Errorf("profile %s: %w", href, err)
+		}
+		for id, title := range catalogControls {
+			controls[id] = title
+		}
+	}
+	return controls, nil
+}
+
+// checkRuleProps checks that the rule IDs in the given properties are defined.
+func (v *contentValidator) checkRuleProps(file, parentPath string, props *[]oscalTypes.Property, ruleIDs map[string]struct{}) {
+	if props == nil {
+		return
+	}
+	for i, prop := range *props {
+		if prop.Name != extensions.RuleIdProp {
+			continue
+		}
+		if _, ok := ruleIDs[prop.Value]; !ok {
+			v.addError(file, fmt.Sprintf("%s/props/%d", parentPath, i), "rule %s is not defined by any component", prop.Value)
+		}
+	}
+}
+
+// definedRuleIDs returns the rule IDs defined by the non-validation components of
+// the loaded component definitions.
+func (v *contentValidator) definedRuleIDs(files []string) map[string]struct{} {
+	ruleIDs := make(map[string]struct{})
+	for _, file := range files {
+		compDef := v.documents[absPath(file)].ComponentDefinition
+		if compDef == nil || compDef.Components == nil {
+			continue
+		}
+		for _, component := range *compDef.Components {
+			if component.Type == string(components.Validation) || component.Props == nil {
+				continue
+			}
+			for _, prop := range *component.Props {
+				if prop.Name == extensions.RuleIdProp {
+					ruleIDs[prop.Value] = struct{}{}
+				}
+			}
+		}
+	}
+	return ruleIDs
+}
+
+// absPath returns the absolute form of path, or the cleaned path if it cannot be determined.
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
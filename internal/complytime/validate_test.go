@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateContent(t *testing.T) {
+	appDir, err := newApplicationDirectory("testdata", false)
+	require.NoError(t, err)
+	files, err := ContentFiles(appDir)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	// The test catalog has empty params and defines control r1 instead of example-1.
+	report := ValidateContent(appDir, files)
+	require.Equal(t, files, report.Files)
+	wantErrors := []ContentError{
+		{
+			File:    filepath.Join(appDir.BundleDir(), "example-component-definition.json"),
+			Path:    "/component-definition/components/0/control-implementations/0/implemented-requirements/0/control-id",
+			Message: "control example-1 not found in the catalogs imported by file://controls/sample-profile.json",
+		},
+		{File: filepath.Join(appDir.ControlDir(), "sample-catalog.json"), Path: "/catalog/groups/0/controls/0/params"},
+		{File: filepath.Join(appDir.ControlDir(), "sample-catalog.json"), Path: "/catalog/params"},
+		{
+			File:    filepath.Join(appDir.ControlDir(), "sample-profile.json"),
+			Path:    "/profile/imports/0/include-controls/0/with-ids/0",
+			Message: "control example-1 not found in catalog file://controls/sample-catalog.json",
+		},
+	}
+	require.Len(t, report.Errors, len(wantErrors))
+	for i, want := range wantErrors {
+		require.Equal(t, want.File, report.Errors[i].File)
+		require.Equal(t, want.Path, report.Errors[i].Path)
+		if want.Message != "" {
+			require.Equal(t, want.Message, report.Errors[i].Message)
+		}
+	}
+}
+
+func TestValidateContentPaths(t *testing.T) {
+	appDir, err := newApplicationDirectory("testdata", false)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(appDir.BundleDir(), "example-component-definition.json"))
+	require.NoError(t, err)
+	var document oscalTypes.OscalModels
+	require.NoError(t, json.Unmarshal(data, &document))
+	// Reference a missing profile and an undefined rule from the validation component.
+	compDef := document.ComponentDefinition
+	(*(*compDef.Components)[0].ControlImplementations)[0].Source = "file://controls/missing-profile.json"
+	(*(*compDef.Components)[1].Props)[0].Value = "rule-2"
+	content, err := json.Marshal(document)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "my-component-definition.json"), content, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte("{"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0600))
+
+	files, err := FindContentFiles([]string{dir})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	report := ValidateContent(appDir, files)
+	var got []string
+	for _, contentError := range report.Errors {
+		got = append(got, contentError.String())
+	}
+	require.Len(t, got, 3)
+	require.Contains(t, got[0], "invalid.json: invalid JSON at offset 1")
+	require.Contains(t, got[1], "my-component-definition.json: /component-definition/components/0/control-implementations/0/source: file://controls/missing-profile.json does not resolve to an existing file")
+	require.Contains(t, got[2], "my-component-definition.json: /component-definition/components/1/props/0: rule rule-2 is not defined by any component")
+
+	_, err = FindContentFiles([]string{filepath.Join(dir, "missing")})
+	require.Error(t, err)
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	// WorkspaceStateFile is the name of the state file in a workspace.
+	WorkspaceStateFile = "workspace.yaml"
+	// WorkspaceStateVersion is the version of the workspace state file format
+	// written by this version of complyctl.
+	WorkspaceStateVersion = 1
+)
+
+var (
+	// ErrWorkspaceStateNotFound is returned when a workspace has no state file.
+	ErrWorkspaceStateNotFound = errors.New("workspace state not found")
+	// ErrPlanModified is returned when the assessment plan does not match the digest in the workspace state.
+	ErrPlanModified = errors.New("assessment plan was modified outside of complyctl")
+)
+
+// WorkspaceState is the persistent state of a workspace, recorded by the commands
+// that create or use the workspace artifacts.
+type WorkspaceState struct {
+	// Version of the state file format.
+	Version int `json:"version" yaml:"version"`
+	// FrameworkID is the framework the workspace is assessed against.
+	// In workspaces assessed against several frameworks, it is the primary framework.
+	FrameworkID string `json:"framework,omitempty" yaml:"framework,omitempty"`
+	// Frameworks are all the frameworks the workspace is assessed against, starting
+	// with the primary framework. It is only set for several frameworks.
+	Frameworks []string `json:"frameworks,omitempty" yaml:"frameworks,omitempty"`
+	// PlanDigest is the digest of the assessment plan written by the plan command.
+	PlanDigest string `json:"planDigest,omitempty" yaml:"planDigest,omitempty"`
+	// Bundles are the component definitions implementing the framework when the plan was written.
+	Bundles []BundleVersion `json:"bundles,omitempty" yaml:"bundles,omitempty"`
+	// LastGenerate is the time of the last successful policy generation.
+	LastGenerate *time.Time `json:"lastGenerate,omitempty" yaml:"lastGenerate,omitempty"`
+	// LastScan is the time of the last successful scan.
+	LastScan *time.Time `json:"lastScan,omitempty" yaml:"lastScan,omitempty"`
+}
+
+// BundleVersion identifies a component definition by its metadata.
+type BundleVersion struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// NewWorkspaceState returns an empty workspace state with the current version.
+func NewWorkspaceState() *WorkspaceState {
+	return &WorkspaceState{Version: WorkspaceStateVersion}
+}
+
+// WorkspaceStatePath returns the path of the state file in a workspace.
+func WorkspaceStatePath(workspace string) string {
+	return filepath.Join(workspace, WorkspaceStateFile)
+}
+
+// CreateWorkspaceLayout creates the directories of a workspace: the workspace root,
+// the scan history and a directory for the artifacts of each plugin. Existing
+// directories are kept.
+func CreateWorkspaceLayout(workspace string, pluginIDs []string) error {
+	dirs := []string{workspace, HistoryPath(workspace)}
+	for _, pluginID := range pluginIDs {
+		dirs = append(dirs, filepath.Join(workspace, pluginID))
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("error creating workspace directory %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// ReadWorkspaceState reads the state file of a workspace.
+// ErrWorkspaceStateNotFound is returned if the workspace has no state file.
+func ReadWorkspaceState(workspace string) (*WorkspaceState, error) {
+	statePath := WorkspaceStatePath(workspace)
+	data, err := os.ReadFile(filepath.Clean(statePath))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrWorkspaceStateNotFound, statePath)
+		}
+		return nil, err
+	}
+	state := &WorkspaceState{}
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("error reading workspace state %s: %w", statePath, err)
+	}
+	switch {
+	case state.Version > WorkspaceStateVersion:
+		return nil, fmt.Errorf("workspace state %s has version %d, newer than the supported version %d: upgrade complyctl", statePath, state.Version, WorkspaceStateVersion)
+	case state.Version < 1:
+		return nil, fmt.Errorf("workspace state %s has an invalid version %d", statePath, state.Version)
+	}
+	return state, nil
+}
+
+// WriteWorkspaceState writes the state file of a workspace, creating the workspace if needed.
+func WriteWorkspaceState(workspace string, state *WorkspaceState) error {
+	if err := os.MkdirAll(workspace, 0700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("error marshalling workspace state: %w", err)
+	}
+	return os.WriteFile(WorkspaceStatePath(workspace), data, 0600)
+}
+
+// FrameworkIDs returns the frameworks the workspace is assessed against, starting with the primary framework.
+func (s *WorkspaceState) FrameworkIDs() []string {
+	if len(s.Frameworks) > 0 {
+		return s.Frameworks
+	}
+	if s.FrameworkID == "" {
+		return nil
+	}
+	return []string{s.FrameworkID}
+}
+
+// SetFrameworks records the frameworks the workspace is assessed against, the first one being the primary framework.
+func (s *WorkspaceState) SetFrameworks(frameworkIDs []string) {
+	s.FrameworkID = ""
+	s.Frameworks = nil
+	if len(frameworkIDs) > 0 {
+		s.FrameworkID = frameworkIDs[0]
+	}
+	if len(frameworkIDs) > 1 {
+		s.Frameworks = slices.Clone(frameworkIDs)
+	}
+}
+
+// RecordPlan records the frameworks, the digest of the assessment plan at planPath
+// and the component definitions the plan was created from.
+func (s *WorkspaceState) RecordPlan(planPath string, frameworkIDs []string, bundles []BundleVersion) error {
+	digest, err := PlanDigest(planPath)
+	if err != nil {
+		return err
+	}
+	s.SetFrameworks(frameworkIDs)
+	s.PlanDigest = digest
+	s.Bundles = bundles
+	return nil
+}
+
+// VerifyPlan returns ErrPlanModified if the assessment plan at planPath
+// does not match the recorded digest.
+func (s *WorkspaceState) VerifyPlan(planPath string) error {
+	digest, err := PlanDigest(planPath)
+	if err != nil {
+		return err
+	}
+	if s.PlanDigest != digest {
+		return fmt.Errorf("%w: %s does not match the digest in %s", ErrPlanModified, planPath, WorkspaceStateFile)
+	}
+	return nil
+}
+
+// PlanDigest returns the digest of the assessment plan at planPath in the form "sha256:<hex>".
+func PlanDigest(planPath string) (string, error) {
+	checksum, err := fileChecksum(planPath)
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + checksum, nil
+}
+
+// BundleVersions returns the title and version of the component definitions
+// with control implementations for any of the given frameworks.
+func BundleVersions(compDefs []oscalTypes.ComponentDefinition, frameworkIDs ...string) []BundleVersion {
+	var bundles []BundleVersion
+	for _, compDef := range compDefs {
+		frameworks := componentDefinitionFrameworks(compDef)
+		if slices.ContainsFunc(frameworkIDs, func(frameworkID string) bool { return slices.Contains(frameworks, frameworkID) }) {
+			bundles = append(bundles, BundleVersion{Title: compDef.Metadata.Title, Version: compDef.Metadata.Version})
+		}
+	}
+	return bundles
+}
+
+// BundleChanges compares the component definitions recorded when the plan was written with
+// the current ones and describes each added, removed or updated component definition.
+// No changes are returned when no bundles were recorded.
+func (s *WorkspaceState) BundleChanges(current []BundleVersion) []string {
+	if len(s.Bundles) == 0 {
+		return nil
+	}
+	recorded := make(map[string]string, len(s.Bundles))
+	for _, bundle := range s.Bundles {
+		recorded[bundle.Title] = bundle.Version
+	}
+	var changes []string
+	seen := make(map[string]bool, len(current))
+	for _, bundle := range current {
+		seen[bundle.Title] = true
+		version, found := recorded[bundle.Title]
+		switch {
+		case !found:
+			changes = append(changes, fmt.Sprintf("%s %s was added", bundle.Title, bundle.Version))
+		case version != bundle.Version:
+			changes = append(changes, fmt.Sprintf("%s changed from %s to %s", bundle.Title, version, bundle.Version))
+		}
+	}
+	for _, bundle := range s.Bundles {
+		if !seen[bundle.Title] {
+			changes = append(changes, fmt.Sprintf("%s %s was removed", bundle.Title, bundle.Version))
+		}
+	}
+	slices.Sort(changes)
+	return changes
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceState(t *testing.T) {
+	workspace := filepath.Join(t.TempDir(), "complytime")
+
+	_, err := ReadWorkspaceState(workspace)
+	require.ErrorIs(t, err, ErrWorkspaceStateNotFound)
+
+	planPath := filepath.Join(t.TempDir(), "assessment-plan.json")
+	require.NoError(t, os.WriteFile(planPath, []byte(`{"assessment-plan": {}}`), 0600))
+
+	state := NewWorkspaceState()
+	bundles := []BundleVersion{{Title: "My sample component definition.", Version: "0.1.0"}}
+	require.NoError(t, state.RecordPlan(planPath, []string{"example"}, bundles))
+	require.Equal(t, "sha256:12dd33716efd4c1b663c6f8923dce96f480d233957320776f66af73608beb138", state.PlanDigest)
+	require.NoError(t, state.VerifyPlan(planPath))
+	scanned := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	state.LastScan = &scanned
+	require.NoError(t, WriteWorkspaceState(workspace, state))
+
+	got, err := ReadWorkspaceState(workspace)
+	require.NoError(t, err)
+	require.Equal(t, state, got)
+	require.Nil(t, got.LastGenerate)
+
+	require.NoError(t, os.WriteFile(planPath, []byte(`{"assessment-plan": {"uuid": "changed"}}`), 0600))
+	require.ErrorIs(t, got.VerifyPlan(planPath), ErrPlanModified)
+
+	require.NoError(t, os.WriteFile(WorkspaceStatePath(workspace), []byte("version: 2\n"), 0600))
+	_, err = ReadWorkspaceState(workspace)
+	require.ErrorContains(t, err, "has version 2, newer than the supported version 1")
+
+	require.NoError(t, os.WriteFile(WorkspaceStatePath(workspace), []byte("framework: example\n"), 0600))
+	_, err = ReadWorkspaceState(workspace)
+	require.ErrorContains(t, err, "has an invalid version 0")
+}
+
+func TestWorkspaceStateFrameworks(t *testing.T) {
+	state := NewWorkspaceState()
+	require.Empty(t, state.FrameworkIDs())
+
+	state.SetFrameworks([]string{"example"})
+	require.Equal(t, "example", state.FrameworkID)
+	require.Empty(t, state.Frameworks)
+	require.Equal(t, []string{"example"}, state.FrameworkIDs())
+
+	state.SetFrameworks([]string{"cis", "baseline"})
+	require.Equal(t, "cis", state.FrameworkID)
+	require.Equal(t, []string{"cis", "baseline"}, state.Frameworks)
+	require.Equal(t, []string{"cis", "baseline"}, state.FrameworkIDs())
+
+	state.SetFrameworks(nil)
+	require.Empty(t, state.FrameworkID)
+	require.Empty(t, state.FrameworkIDs())
+}
+
+func TestBundleVersions(t *testing.T) {
+	compDefs, err := FindComponentDefinitions("testdata/complytime/bundles", validation.NoopValidator{})
+	require.NoError(t, err)
+
+	want := []BundleVersion{{Title: "My sample component definition.", Version: "0.1.0"}}
+	require.Equal(t, want, BundleVersions(compDefs, "example"))
+	require.Empty(t, BundleVersions(compDefs, "other"))
+	require.Equal(t, want, BundleVersions(compDefs, "other", "example"))
+}
+
+func TestBundleChanges(t *testing.T) {
+	state := NewWorkspaceState()
+	require.Empty(t, state.BundleChanges([]BundleVersion{{Title: "a", Version: "1.0.0"}}))
+
+	state.Bundles = []BundleVersion{{Title: "a", Version: "1.0.0"}, {Title: "b", Version: "1.0.0"}, {Title: "c", Version: "2.0.0"}}
+	require.Empty(t, state.BundleChanges(state.Bundles))
+	require.Equal(t, []string{
+		"a changed from 1.0.0 to 1.1.0",
+		"c 2.0.0 was removed",
+		"d 0.1.0 was added",
+	}, state.BundleChanges([]BundleVersion{{Title: "a", Version: "1.1.0"}, {Title: "b", Version: "1.0.0"}, {Title: "d", Version: "0.1.0"}}))
+}
@@ -0,0 +1,407 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package terminal
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// ScopeAction is the action chosen by the user when leaving the scope editor.
+type ScopeAction int
+
+const (
+	// ScopeQuit discards the changes.
+	ScopeQuit ScopeAction = iota
+	// ScopeSave saves the edited scope as a scope config.
+	ScopeSave
+	// ScopeApply applies the edited scope to the assessment plan.
+	ScopeApply
+)
+
+type scopeView int
+
+const (
+	controlsView scopeView = iota
+	controlRulesView
+	globalRulesView
+)
+
+const (
+	defaultScopeTableHeight = 20
+	// scopeViewLines is the number of lines around the table: header, borders, table header and help.
+	scopeViewLines = 6
+	allRules       = "*"
+)
+
+var _ tea.Model = (*ScopeModel)(nil)
+
+// scopeControl is a control of the framework with the rules mapped to it.
+type scopeControl struct {
+	entry    complytime.ControlEntry
+	included bool
+	rules    []string
+	selected map[string]bool
+}
+
+// ScopeModel is a terminal editor for an AssessmentScope. Controls are toggled in and out
+// of scope, and rules are selected per control or excluded from all controls.
+type ScopeModel struct {
+	frameworkID string
+	controls    []scopeControl
+	// rules are all the rules mapped to the controls, sorted by ID.
+	rules          []string
+	globalExcluded map[string]bool
+	// unknownGlobalExcludes are the global exclude rules not mapped to any control.
+	// They are kept as is.
+	unknownGlobalExcludes []string
+	// parameters are the global rule parameters, kept as is.
+	parameters map[string]string
+
+	view    scopeView
+	control int
+	table   table.Model
+	action  ScopeAction
+}
+
+// NewScopeModel returns a ScopeModel editing scope. The controls available for the framework are
+// listed in available, and controlRules holds the IDs of the rules mapped to each control.
+// Controls of scope that are not available are kept, and the entries of scope with patterns
+// are expanded to the available controls and rules they match.
+func NewScopeModel(scope complytime.AssessmentScope, available []complytime.ControlEntry, controlRules map[string][]string) *ScopeModel {
+	m := &ScopeModel{
+		frameworkID:    scope.FrameworkID,
+		globalExcluded: make(map[string]bool),
+		parameters:     scope.Parameters,
+	}
+
+	addControl := func(entry complytime.ControlEntry, included bool) {
+		control := scopeControl{
+			entry:    entry,
+			included: included,
+			rules:    controlRules[entry.ControlID],
+			selected: make(map[string]bool),
+		}
+		for _, rule := range control.rules {
+			control.selected[rule] = ruleSelected(entry, rule)
+			if !slices.Contains(m.rules, rule) {
+				m.rules = append(m.rules, rule)
+			}
+		}
+		m.controls = append(m.controls, control)
+	}
+	// Entries with patterns are expanded to the available controls they match.
+	seen := make(map[string]bool)
+	for _, entry := range available {
+		seen[entry.ControlID] = true
+		scopeEntry, included := scope.FindControlEntry(entry.ControlID)
+		if included {
+			if scopeEntry.ControlTitle == "" || scopeEntry.ControlID != entry.ControlID {
+				scopeEntry.ControlTitle = entry.ControlTitle
+			}
+			scopeEntry.ControlID = entry.ControlID
+			entry = scopeEntry
+		}
+		addControl(entry, included)
+	}
+	for _, entry := range scope.IncludeControls {
+		if seen[entry.ControlID] || complytime.IsScopePattern(entry.ControlID) {
+			continue
+		}
+		if _, included := scope.FindControlEntry(entry.ControlID); included {
+			addControl(entry, true)
+		}
+	}
+	sort.Strings(m.rules)
+
+	for _, rule := range scope.GlobalExcludeRules {
+		matched := false
+		if complytime.IsScopePattern(rule) {
+			for _, knownRule := range m.rules {
+				if complytime.MatchScopePattern(rule, knownRule) {
+					m.globalExcluded[knownRule] = true
+					matched = true
+				}
+			}
+		}
+		switch {
+		case matched:
+		case slices.Contains(m.rules, rule):
+			m.globalExcluded[rule] = true
+		default:
+			m.unknownGlobalExcludes = append(m.unknownGlobalExcludes, rule)
+		}
+	}
+
+	m.table = table.New(
+		table.WithFocused(true),
+		table.WithHeight(defaultScopeTableHeight),
+	)
+	styles := table.DefaultStyles()
+	styles.Header = styles.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	m.table.SetStyles(styles)
+	m.refresh()
+	return m
+}
+
+// ruleSelected returns whether the control entry includes the rule, as AssessmentScope.ApplyScope does.
+func ruleSelected(entry complytime.ControlEntry, rule string) bool {
+	if matchesAny(entry.ExcludeRules, rule) {
+		return false
+	}
+	return len(entry.IncludeRules) == 0 || matchesAny(entry.IncludeRules, rule)
+}
+
+// matchesAny returns whether any of the scope patterns matches the rule.
+func matchesAny(patterns []string, rule string) bool {
+	for _, pattern := range patterns {
+		if complytime.MatchScopePattern(pattern, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// Action returns the action chosen by the user when leaving the editor.
+func (m *ScopeModel) Action() ScopeAction {
+	return m.action
+}
+
+// Scope returns the edited AssessmentScope. The rules of each control are written
+// with the shortest of the include and exclude lists.
+func (m *ScopeModel) Scope() complytime.AssessmentScope {
+	scope := complytime.NewAssessmentScope(m.frameworkID)
+	scope.IncludeControls = []complytime.ControlEntry{}
+	for _, control := range m.controls {
+		if !control.included {
+			continue
+		}
+		entry := control.entry
+		if len(control.rules) > 0 {
+			var selected, excluded []string
+			for _, rule := range control.rules {
+				if control.selected[rule] {
+					selected = append(selected, rule)
+				} else {
+					excluded = append(excluded, rule)
+				}
+			}
+			entry.IncludeRules = []string{allRules}
+			entry.ExcludeRules = nil
+			switch {
+			case len(selected) == 0:
+				entry.IncludeRules = []string{}
+				entry.ExcludeRules = []string{allRules}
+			case len(selected) <= len(excluded):
+				entry.IncludeRules = selected
+			case len(excluded) > 0:
+				entry.ExcludeRules = excluded
+			}
+		}
+		scope.IncludeControls = append(scope.IncludeControls, entry)
+	}
+
+	var globalExcludes []string
+	for _, rule := range m.rules {
+		if m.globalExcluded[rule] {
+			globalExcludes = append(globalExcludes, rule)
+		}
+	}
+	if len(globalExcludes) > 0 && len(globalExcludes) == len(m.rules) {
+		globalExcludes = []string{allRules}
+	}
+	scope.GlobalExcludeRules = append(globalExcludes, m.unknownGlobalExcludes...)
+	scope.Parameters = m.parameters
+	return scope
+}
+
+func (m *ScopeModel) Init() tea.Cmd { return nil }
+
+func (m *ScopeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.table.SetHeight(max(msg.Height-scopeViewLines, 3))
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			m.action = ScopeQuit
+			return m, tea.Quit
+		case "q", "esc":
+			if m.view == controlsView {
+				m.action = ScopeQuit
+				return m, tea.Quit
+			}
+			m.setView(controlsView)
+			return m, nil
+		case "s":
+			m.action = ScopeSave
+			return m, tea.Quit
+		case "a":
+			m.action = ScopeApply
+			return m, tea.Quit
+		case " ":
+			m.toggle(m.table.Cursor())
+			return m, nil
+		case "+":
+			m.setAll(true)
+			return m, nil
+		case "-":
+			m.setAll(false)
+			return m, nil
+		case "enter":
+			if m.view == controlsView && len(m.controls) > 0 {
+				m.control = m.table.Cursor()
+				m.setView(controlRulesView)
+			}
+			return m, nil
+		case "g":
+			if m.view == controlsView {
+				m.setView(globalRulesView)
+			}
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// toggle switches the row at index i of the current view.
+func (m *ScopeModel) toggle(i int) {
+	switch m.view {
+	case controlsView:
+		if i < len(m.controls) {
+			m.controls[i].included = !m.controls[i].included
+		}
+	case controlRulesView:
+		control := m.controls[m.control]
+		if i < len(control.rules) {
+			rule := control.rules[i]
+			control.selected[rule] = !control.selected[rule]
+		}
+	case globalRulesView:
+		if i < len(m.rules) {
+			m.globalExcluded[m.rules[i]] = !m.globalExcluded[m.rules[i]]
+		}
+	}
+	m.refresh()
+}
+
+// setAll selects or deselects all rows of the current view.
+func (m *ScopeModel) setAll(value bool) {
+	switch m.view {
+	case controlsView:
+		for i := range m.controls {
+			m.controls[i].included = value
+		}
+	case controlRulesView:
+		for _, rule := range m.controls[m.control].rules {
+			m.controls[m.control].selected[rule] = value
+		}
+	case globalRulesView:
+		for _, rule := range m.rules {
+			m.globalExcluded[rule] = value
+		}
+	}
+	m.refresh()
+}
+
+func (m *ScopeModel) setView(view scopeView) {
+	cursor := 0
+	if view == controlsView && m.view == controlRulesView {
+		cursor = m.control
+	}
+	m.view = view
+	m.refresh()
+	m.table.SetCursor(cursor)
+}
+
+// refresh updates the table with the rows of the current view.
+func (m *ScopeModel) refresh() {
+	columns, rows := m.columnsAndRows()
+	// Rows are cleared first so they are never rendered with the columns of another view.
+	m.table.SetRows(nil)
+	m.table.SetColumns(columns)
+	m.table.SetRows(rows)
+}
+
+func (m *ScopeModel) columnsAndRows() ([]table.Column, []table.Row) {
+	var columns []table.Column
+	var rows []table.Row
+	switch m.view {
+	case controlsView:
+		columns = []table.Column{{Title: "", Width: 3}, {Title: "Control ID", Width: 12}, {Title: "Control Title", Width: 40}, {Title: "Rules", Width: 7}}
+		for _, control := range m.controls {
+			selected := 0
+			for _, rule := range control.rules {
+				if control.selected[rule] {
+					selected++
+				}
+			}
+			rows = append(rows, table.Row{checkbox(control.included), control.entry.ControlID, control.entry.ControlTitle, fmt.Sprintf("%d/%d", selected, len(control.rules))})
+		}
+	case controlRulesView:
+		columns = []table.Column{{Title: "", Width: 3}, {Title: "Rule ID", Width: 50}, {Title: "Note", Width: 18}}
+		control := m.controls[m.control]
+		for _, rule := range control.rules {
+			note := ""
+			if m.globalExcluded[rule] {
+				note = "globally excluded"
+			}
+			rows = append(rows, table.Row{checkbox(control.selected[rule]), rule, note})
+		}
+	case globalRulesView:
+		columns = []table.Column{{Title: "", Width: 3}, {Title: "Rule ID", Width: 50}}
+		for _, rule := range m.rules {
+			rows = append(rows, table.Row{checkbox(m.globalExcluded[rule]), rule})
+		}
+	}
+	for i := range columns {
+		for _, row := range rows {
+			columns[i].Width = max(columns[i].Width, lipgloss.Width(row[i]))
+		}
+	}
+	return columns, rows
+}
+
+func checkbox(checked bool) string {
+	if checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+func (m *ScopeModel) View() string {
+	var header, help string
+	switch m.view {
+	case controlsView:
+		included := 0
+		for _, control := range m.controls {
+			if control.included {
+				included++
+			}
+		}
+		header = fmt.Sprintf("Assessment scope for %s: %d of %d controls included", m.frameworkID, included, len(m.controls))
+		help = "space: toggle control • +/-: include all/none • enter: select rules • g: exclude rules globally • s: save • a: apply • q: quit"
+	case controlRulesView:
+		control := m.controls[m.control]
+		header = fmt.Sprintf("Rules of control %s %s", control.entry.ControlID, control.entry.ControlTitle)
+		help = "space: toggle rule • +/-: include all/none • esc: back"
+	case globalRulesView:
+		header = "Rules excluded from all controls"
+		help = "space: toggle exclusion • +/-: exclude all/none • esc: back"
+	}
+	return header + "\n" + baseStyle.Render(m.table.View()) + "\n" + help + "\n"
+}
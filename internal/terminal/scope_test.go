@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package terminal
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+var (
+	testAvailableControls = []complytime.ControlEntry{
+		{ControlID: "control-1", ControlTitle: "Control 1", IncludeRules: []string{"*"}},
+		{ControlID: "control-2", ControlTitle: "Control 2", IncludeRules: []string{"*"}},
+		{ControlID: "control-3", ControlTitle: "Control 3", IncludeRules: []string{"*"}},
+	}
+	testControlRules = map[string][]string{
+		"control-1": {"rule-1", "rule-2", "rule-3"},
+		"control-2": {"rule-2"},
+	}
+)
+
+func TestScopeModel(t *testing.T) {
+	scope := complytime.AssessmentScope{FrameworkID: "example", IncludeControls: testAvailableControls}
+	model := NewScopeModel(scope, testAvailableControls, testControlRules)
+	require.Equal(t, scope, model.Scope())
+
+	// Exclude control-2, exclude rule-3 from control-1 and exclude rule-2 from all controls.
+	sendKeys(model, "down", " ", "up", "enter", "down", "down", " ", "esc", "g", "down", " ", "esc")
+	wantScope := complytime.AssessmentScope{
+		FrameworkID: "example",
+		IncludeControls: []complytime.ControlEntry{
+			{ControlID: "control-1", ControlTitle: "Control 1", IncludeRules: []string{"*"}, ExcludeRules: []string{"rule-3"}},
+			{ControlID: "control-3", ControlTitle: "Control 3", IncludeRules: []string{"*"}},
+		},
+		GlobalExcludeRules: []string{"rule-2"},
+	}
+	require.Equal(t, wantScope, model.Scope())
+
+	// Select only rule-1 of control-1.
+	sendKeys(model, "enter", "-", " ")
+	require.Equal(t, []string{"rule-1"}, model.Scope().IncludeControls[0].IncludeRules)
+	require.Empty(t, model.Scope().IncludeControls[0].ExcludeRules)
+
+	// No rule of control-1 selected.
+	sendKeys(model, " ")
+	require.Equal(t, []string{}, model.Scope().IncludeControls[0].IncludeRules)
+	require.Equal(t, []string{"*"}, model.Scope().IncludeControls[0].ExcludeRules)
+
+	sendKeys(model, "esc", "a")
+	require.Equal(t, ScopeApply, model.Action())
+}
+
+func TestScopeModelFromConfig(t *testing.T) {
+	scope := complytime.AssessmentScope{
+		FrameworkID: "example",
+		IncludeControls: []complytime.ControlEntry{
+			{ControlID: "control-1", IncludeRules: []string{"rule-1", "rule-2"}, Parameters: map[string]string{"param-1": "control"}},
+			{ControlID: "control-4", ControlTitle: "Not available", IncludeRules: []string{"*"}},
+		},
+		GlobalExcludeRules: []string{"*", "unknown-rule"},
+		Parameters:         map[string]string{"param-1": "global"},
+	}
+	model := NewScopeModel(scope, testAvailableControls, testControlRules)
+	wantScope := complytime.AssessmentScope{
+		FrameworkID: "example",
+		IncludeControls: []complytime.ControlEntry{
+			{ControlID: "control-1", ControlTitle: "Control 1", IncludeRules: []string{"*"}, ExcludeRules: []string{"rule-3"}, Parameters: map[string]string{"param-1": "control"}},
+			{ControlID: "control-4", ControlTitle: "Not available", IncludeRules: []string{"*"}},
+		},
+		GlobalExcludeRules: []string{"*", "unknown-rule"},
+		Parameters:         map[string]string{"param-1": "global"},
+	}
+	require.Equal(t, wantScope, model.Scope())
+
+	// Include all controls and remove the global exclusions of the known rules.
+	sendKeys(model, "+", "g", "-", "q")
+	scope = model.Scope()
+	require.Len(t, scope.IncludeControls, 4)
+	require.Equal(t, []string{"unknown-rule"}, scope.GlobalExcludeRules)
+
+	sendKeys(model, "s")
+	require.Equal(t, ScopeSave, model.Action())
+	sendKeys(model, "q")
+	require.Equal(t, ScopeQuit, model.Action())
+}
+
+func TestScopeModelFromPatterns(t *testing.T) {
+	scope := complytime.AssessmentScope{
+		FrameworkID: "example",
+		IncludeControls: []complytime.ControlEntry{
+			{ControlID: "control-1", IncludeRules: []string{"re:^rule-[12]$"}},
+			{ControlID: "control-*", IncludeRules: []string{"*"}},
+		},
+		ExcludeControls:    []string{"control-3"},
+		GlobalExcludeRules: []string{"rule-?"},
+	}
+	model := NewScopeModel(scope, testAvailableControls, testControlRules)
+	wantScope := complytime.AssessmentScope{
+		FrameworkID: "example",
+		IncludeControls: []complytime.ControlEntry{
+			{ControlID: "control-1", ControlTitle: "Control 1", IncludeRules: []string{"*"}, ExcludeRules: []string{"rule-3"}},
+			{ControlID: "control-2", ControlTitle: "Control 2", IncludeRules: []string{"*"}},
+		},
+		GlobalExcludeRules: []string{"*"},
+	}
+	require.Equal(t, wantScope, model.Scope())
+}
+
+func TestScopeModelView(t *testing.T) {
+	scope := complytime.AssessmentScope{FrameworkID: "example", IncludeControls: testAvailableControls[:1]}
+	model := NewScopeModel(scope, testAvailableControls, testControlRules)
+	view := model.View()
+	require.Contains(t, view, "Assessment scope for example: 1 of 3 controls included")
+	require.Contains(t, view, "[x]")
+	require.Contains(t, view, "3/3")
+
+	sendKeys(model, "g", " ", "esc", "enter")
+	view = model.View()
+	require.Contains(t, view, "Rules of control control-1 Control 1")
+	require.Contains(t, view, "globally excluded")
+}
+
+func sendKeys(model *ScopeModel, keys ...string) {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		model.Update(msg)
+	}
+}
@@ -36,14 +36,19 @@ Build Date:	{{ .BuildDate }}
 Platform:	{{ .Platform }}
 `
 
-// WriteVersion will output the templated version message.
-func WriteVersion(writer io.Writer) error {
+// Version returns the client version, or a placeholder when not set at build time.
+func Version() string {
 	if version == "" {
-		version = "v0.0.0-unknown"
+		return "v0.0.0-unknown"
 	}
-	versionWithState := version
+	return version
+}
+
+// WriteVersion will output the templated version message.
+func WriteVersion(writer io.Writer) error {
+	versionWithState := Version()
 	if gitTreeState != "" {
-		versionWithState = fmt.Sprintf("%s+%s", version, gitTreeState)
+		versionWithState = fmt.Sprintf("%s+%s", versionWithState, gitTreeState)
 	}
 
 	versionInfo := clientVersion{
@@ -73,6 +73,13 @@ func NewLogger(o io.Writer) hclog.Logger {
 	return l
 }
 
+// NewJSONLogger initializes a new wrapped logger writing messages as JSON
+func NewJSONLogger(o io.Writer) hclog.Logger {
+	options := defaultOptions()
+	options.Formatter = charmlog.JSONFormatter
+	return &CharmHclog{charmlog.NewWithOptions(o, *options)}
+}
+
 // CharmHclog adapts the charm logger to the hashicorp logger.
 type CharmHclog struct {
 	logger *charmlog.Logger
@@ -164,3 +164,10 @@ func TestPrefixMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewJSONLogger(&buf)
+	l.Info("info", "plugin", "openscap")
+	assert.JSONEq(t, `{"level":"info","msg":"info","plugin":"openscap"}`, buf.String())
+}